@@ -0,0 +1,97 @@
+// cmd/satcli/archive_cmd.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive [name...]",
+	Short: "Retire satellite records without deleting them",
+	Long: `Marks each named satellite as archived and saves the datastore once at the end. Archived records
+keep all their fields but are left out of list and query unless --include-archived or --archived-only
+is given; 'satcli unarchive' brings them back. Names that are not found are reported and skipped.
+
+Examples:
+  satcli archive OldBird
+  satcli list --archived-only --output table`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setArchived(cmd, args, true)
+	},
+}
+
+var unarchiveCmd = &cobra.Command{
+	Use:   "unarchive [name...]",
+	Short: "Restore archived satellite records to list and query",
+	Long: `Clears the archived mark set by 'satcli archive' on each named satellite and saves the datastore once
+at the end. Names that are not found are reported and skipped.
+
+Examples:
+  satcli unarchive OldBird`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setArchived(cmd, args, false)
+	},
+}
+
+// setArchived sets the Archived field of each named satellite and saves once if any changed.
+func setArchived(cmd *cobra.Command, names []string, archived bool) error {
+	if !datastore.IsUnlocked() {
+		return errDatastoreLocked()
+	}
+	satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get satellites: %w", err)
+	}
+	cmd.SilenceUsage = true
+	verb, state := "Archived", "archived"
+	if !archived {
+		verb, state = "Unarchived", "unarchived"
+	}
+
+	changed, found := 0, 0
+	for _, name := range names {
+		sat, ok, err := lookupSatellite(satsMap, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintf(cmd.OutOrStdout(), "Not found: %s\n", name)
+			continue
+		}
+		name = sat.Name
+		found++
+		if sat.Archived == archived {
+			fmt.Fprintf(cmd.OutOrStdout(), "Already %s: %s\n", state, name)
+			continue
+		}
+		sat.Archived = archived
+		if err := datastore.AddSatellite(sat); err != nil {
+			return err
+		}
+		satsMap[name] = sat
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", verb, name)
+		changed++
+	}
+	if found == 0 {
+		return &exitCodeError{exitNotFound, fmt.Errorf("no matching satellites found")}
+	}
+	if changed == 0 {
+		return nil
+	}
+	if err := datastore.SaveCtx(cmd.Context()); err != nil {
+		return fmt.Errorf("failed to save after updating %d record(s): %w", changed, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Records %s: %d (datastore saved)\n", state, changed)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(unarchiveCmd)
+}
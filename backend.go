@@ -0,0 +1,262 @@
+// internal/datastore/backend.go
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// Backend stores the datastore's bytes. Data is encrypted before Write and decrypted after Read,
+// so a backend only ever handles ciphertext.
+type Backend interface {
+	// Location names the store in messages: a file path, or an s3://bucket/key URL.
+	Location() string
+	// Read returns the stored bytes and a version identifying them. A missing store is an error
+	// wrapping ErrNotFound.
+	Read(ctx context.Context) (data []byte, version string, err error)
+	// Version returns the version Read would return, or "" if the store does not exist.
+	Version(ctx context.Context) (string, error)
+	// Write replaces the stored bytes and returns their new version. Unless force is set, it fails
+	// with an error wrapping ErrModifiedExternally if the stored version is no longer ifVersion
+	// ("" meaning the store must not exist yet).
+	Write(ctx context.Context, data []byte, ifVersion string, force bool) (string, error)
+	// CheckWritable fails early, before a passphrase is asked for, if Write cannot succeed.
+	CheckWritable(ctx context.Context) error
+}
+
+// OpenBackend returns the backend for a datastore location: S3 (or an S3-compatible service) for
+// an s3://bucket/key URL, otherwise the local file at that path.
+func OpenBackend(location string) (Backend, error) {
+	if strings.HasPrefix(location, s3Scheme) {
+		return newS3Backend(location)
+	}
+	return &fileBackend{path: location}, nil
+}
+
+// SetBackend replaces the backend chosen from the datastore path, e.g. with a custom store.
+// Like SetPath, it must be called before Init.
+func SetBackend(b Backend) {
+	store, dataPath = b, b.Location()
+}
+
+// backend returns the backend for dataPath, opening it on first use. dataPath must be resolved.
+func backend() (Backend, error) {
+	if store == nil || store.Location() != dataPath {
+		b, err := OpenBackend(dataPath)
+		if err != nil {
+			return nil, err
+		}
+		store = b
+	}
+	return store, nil
+}
+
+// modifiedExternally is the error for a save that would overwrite someone else's changes.
+func modifiedExternally(location string) error {
+	return fmt.Errorf("%w: %s changed after it was loaded; re-run the command to apply it to the current data, or use --force-save to overwrite", ErrModifiedExternally, location)
+}
+
+// fileBackend is the default backend: one local file, replaced atomically through a temporary file.
+// Its versions are SHA-256 sums of the file's bytes (see fileSum). Reads hold a shared flock and
+// writes an exclusive one on the sidecar lock file (see lockFile), so any number of processes can
+// read while no save is in progress.
+type fileBackend struct {
+	path string
+}
+
+func (b *fileBackend) Location() string {
+	return b.path
+}
+
+// lockPath returns the sidecar file locked around reads and writes of the datastore at path. The
+// datastore itself can't carry the lock, as each save replaces it with a new file.
+func lockPath(path string) string {
+	return path + ".lock"
+}
+
+func (b *fileBackend) Read(ctx context.Context) ([]byte, string, error) {
+	unlock, err := lockFile(b.path, false)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to lock datastore %s for reading: %w", b.path, err)
+	}
+	defer unlock()
+	return b.read()
+}
+
+// read is Read without taking the file lock.
+func (b *fileBackend) read() ([]byte, string, error) {
+	data, err := ioutil.ReadFile(b.path)
+	if os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("%w: %s", ErrNotFound, b.path)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read encrypted datastore %s: %w", b.path, err)
+	}
+	return data, fileSum(data), nil
+}
+
+func (b *fileBackend) Version(ctx context.Context) (string, error) {
+	_, version, err := b.Read(ctx)
+	return versionOf(version, err)
+}
+
+// versionOf maps the result of a read of a missing store to the empty version.
+func versionOf(version string, err error) (string, error) {
+	if errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	return version, err
+}
+
+func (b *fileBackend) CheckWritable(ctx context.Context) error {
+	dir := filepath.Dir(b.path)
+	if err := checkDirWritable(dir); err != nil {
+		return fmt.Errorf("datastore directory %s is not writable: %w", dir, err)
+	}
+	if tempDir != "" { // Write creates its temporary file there
+		if err := checkDirWritable(tempDir); err != nil {
+			return fmt.Errorf("temporary directory %s (--temp-dir) is not writable: %w", tempDir, err)
+		}
+	}
+	return nil
+}
+
+// Write compares versions and then replaces the file, holding the exclusive lock throughout so no
+// other process can save or read in between.
+func (b *fileBackend) Write(ctx context.Context, data []byte, ifVersion string, force bool) (string, error) {
+	unlock, err := lockFile(b.path, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock datastore %s for writing: %w", b.path, err)
+	}
+	defer unlock()
+	if !force {
+		_, version, err := b.read()
+		current, err := versionOf(version, err)
+		if err != nil {
+			return "", err
+		}
+		if current != ifVersion {
+			return "", modifiedExternally(b.path)
+		}
+	}
+
+	// Write to a temporary file first for atomicity
+	tempDataPath := b.tempPath()
+	if err := ioutil.WriteFile(tempDataPath, data, 0600); err != nil { // 0600 for restricted permissions
+		return "", fmt.Errorf("failed to write temporary encrypted datastore %s: %w", tempDataPath, err)
+	}
+
+	// Atomically replace the old file with the new one
+	if err := os.Rename(tempDataPath, b.path); err != nil {
+		if errors.Is(err, syscall.EXDEV) {
+			// --temp-dir is on another filesystem: rename can't cross devices, so copy instead.
+			copyErr := copyFile(tempDataPath, b.path)
+			_ = os.Remove(tempDataPath)
+			if copyErr != nil {
+				return "", fmt.Errorf("failed to copy encrypted datastore from %s to %s: %w", tempDataPath, b.path, copyErr)
+			}
+			return fileSum(data), nil
+		}
+		// Attempt to clean up temp file if rename fails
+		_ = os.Remove(tempDataPath)
+		return "", fmt.Errorf("failed to commit encrypted datastore from %s to %s: %w", tempDataPath, b.path, err)
+	}
+	return fileSum(data), nil
+}
+
+// tempPath returns the temporary file Write creates before replacing the datastore.
+func (b *fileBackend) tempPath() string {
+	if tempDir != "" {
+		return filepath.Join(tempDir, filepath.Base(b.path)+".tmp")
+	}
+	return b.path + ".tmp"
+}
+
+// recoverStaleTemp deals with a temporary file left behind by a save that was killed before
+// committing, holding the write lock so a save in progress in another process is never disturbed.
+// Beside an intact datastore the file is removed. If the datastore is missing and the file holds a
+// complete-looking encrypted store, OnConfirm is asked whether to promote it to the datastore;
+// whether it decrypts is only known once it is unlocked.
+func (b *fileBackend) recoverStaleTemp() {
+	path := b.tempPath()
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return
+	}
+	unlock, err := lockFile(b.path, true)
+	if err != nil {
+		logger.Debug("stale temporary file left in place: datastore cannot be locked", "path", path, "error", err)
+		return
+	}
+	defer unlock()
+	temp, err := ioutil.ReadFile(path)
+	if err != nil {
+		return // Committed by the save that held the lock
+	}
+	current, err := ioutil.ReadFile(b.path)
+	switch {
+	case err == nil && looksLikeStore(current, false):
+		b.removeStaleTemp(path, "datastore intact")
+	case os.IsNotExist(err) && looksLikeStore(temp, true):
+		b.promoteStaleTemp(path)
+	case os.IsNotExist(err):
+		b.removeStaleTemp(path, "incomplete, no datastore to recover")
+	case err != nil:
+		noticef("Warning: left %s in place: cannot read the datastore %s to check it: %v\n", path, b.path, err)
+	default:
+		noticef("Warning: left %s in place: the datastore %s is damaged, and the file may be needed to recover it.\n", path, b.path)
+	}
+}
+
+// looksLikeStore reports whether data parses as an encrypted datastore. A temporary file must also
+// carry the header, as saves always write one; only old datastores are headerless.
+func looksLikeStore(data []byte, requireHeader bool) bool {
+	if requireHeader && !bytes.HasPrefix(data, fileMagic) {
+		return false
+	}
+	_, _, _, err := parseHeader(data)
+	return err == nil
+}
+
+// removeStaleTemp deletes the temporary file at path.
+func (b *fileBackend) removeStaleTemp(path, reason string) {
+	if err := os.Remove(path); err != nil {
+		noticef("Warning: could not remove stale temporary file %s: %v\n", path, err)
+		return
+	}
+	logger.Info("removed stale temporary file", "path", path, "reason", reason)
+	noticef("Notice: removed stale temporary file %s left by an interrupted save.\n", path)
+}
+
+// promoteStaleTemp makes the temporary file at path the datastore, if OnConfirm agrees.
+func (b *fileBackend) promoteStaleTemp(path string) {
+	question := fmt.Sprintf("The datastore %s is missing, but %s holds one left by an interrupted save. Recover it?", b.path, path)
+	if OnConfirm == nil {
+		noticef("Notice: %s holds a datastore left by an interrupted save; rename it to %s to recover it.\n", path, b.path)
+		return
+	}
+	if ok, err := OnConfirm(question); err != nil || !ok {
+		logger.Info("stale temporary file not recovered", "path", path, "error", err)
+		noticef("Notice: %s holds a datastore left by an interrupted save; rename it to %s to recover it.\n", path, b.path)
+		return
+	}
+	err := os.Rename(path, b.path)
+	if errors.Is(err, syscall.EXDEV) {
+		if err = copyFile(path, b.path); err == nil {
+			_ = os.Remove(path)
+		}
+	}
+	if err != nil {
+		noticef("Warning: could not recover the datastore from %s: %v\n", path, err)
+		return
+	}
+	logger.Info("recovered datastore from stale temporary file", "path", path, "datastore", b.path)
+	noticef("Notice: recovered the datastore %s from %s.\n", b.path, path)
+}
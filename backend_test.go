@@ -0,0 +1,48 @@
+// internal/datastore/backend_test.go
+package datastore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileBackendCheckWritable(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing")
+	if err := os.Mkdir(filepath.Join(dir, "tmp"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		path     string
+		tempDir  string
+		wantName string // Directory the error must name; "" for no error
+	}{
+		{"writable", filepath.Join(dir, "satellites.dat"), "", ""},
+		{"writable temp dir", filepath.Join(dir, "satellites.dat"), filepath.Join(dir, "tmp"), ""},
+		{"missing datastore directory", filepath.Join(missing, "satellites.dat"), "", missing},
+		{"missing temp dir", filepath.Join(dir, "satellites.dat"), missing, missing},
+	}
+	defer SetTempDir("")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetTempDir(tt.tempDir)
+			err := (&fileBackend{path: tt.path}).CheckWritable(context.Background())
+			if tt.wantName == "" {
+				if err != nil {
+					t.Fatalf("CheckWritable: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantName+" ") {
+				t.Errorf("err = %v, want one naming %s", err, tt.wantName)
+			}
+			if tt.tempDir != "" && !strings.Contains(err.Error(), "--temp-dir") {
+				t.Errorf("err = %v, want it to name --temp-dir", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// internal/crypto/calibrate.go
+package crypto
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2Params are the Argon2id cost parameters.
+type Argon2Params struct {
+	Time      uint32 `json:"time"`
+	MemoryKiB uint32 `json:"memoryKiB"`
+	Threads   uint8  `json:"threads"`
+}
+
+// Limits on Argon2id parameters, which also bound the work a corrupted or hostile header can demand.
+const (
+	argon2KeyLen       = 32 // AES-256 and ChaCha20-Poly1305
+	argon2MaxTime      = 64
+	argon2MaxMemoryKiB = 4 * 1024 * 1024 // 4 GiB
+)
+
+// Validate checks that p is usable: at least one pass and thread, at least 8 KiB of memory per
+// thread as Argon2 requires, and no more than 64 passes or 4 GiB of memory.
+func (p Argon2Params) Validate() error {
+	switch {
+	case p.Time < 1 || p.Time > argon2MaxTime:
+		return fmt.Errorf("argon2id time cost must be between 1 and %d, got %d", argon2MaxTime, p.Time)
+	case p.Threads < 1:
+		return fmt.Errorf("argon2id threads must be at least 1")
+	case p.MemoryKiB < 8*uint32(p.Threads) || p.MemoryKiB > argon2MaxMemoryKiB:
+		return fmt.Errorf("argon2id memory cost must be between %d KiB and %d MiB, got %d KiB", 8*uint32(p.Threads), argon2MaxMemoryKiB/1024, p.MemoryKiB)
+	}
+	return nil
+}
+
+// CalibrationStep is one benchmarked parameter set.
+type CalibrationStep struct {
+	Params   Argon2Params  `json:"params"`
+	Duration time.Duration `json:"duration"`
+}
+
+// calibrationPassphrase is a throwaway input; only the derivation time matters.
+const calibrationPassphrase = "satcli-calibration"
+
+// calibrationStartMemoryKiB is the first memory cost tried (64 MiB).
+const calibrationStartMemoryKiB = 64 * 1024
+
+// BenchmarkDefaultKDF times one DeriveKeyWithArgon2id call, i.e. the cost of unlocking a store today.
+func BenchmarkDefaultKDF() (time.Duration, error) {
+	salt, err := calibrationSalt()
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	if _, err := DeriveKeyWithArgon2id(calibrationPassphrase, salt); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// CalibrateArgon2id searches for Argon2id parameters whose derivation takes at least target.
+// Memory is doubled from 64 MiB up to maxMemoryKiB first, then the time cost is raised. It returns
+// the first parameter set reaching target (or the most expensive one tried) and every step benchmarked.
+func CalibrateArgon2id(target time.Duration, maxMemoryKiB uint32, threads uint8) (Argon2Params, []CalibrationStep, error) {
+	if target <= 0 {
+		return Argon2Params{}, nil, fmt.Errorf("calibration target must be positive")
+	}
+	if maxMemoryKiB < calibrationStartMemoryKiB {
+		return Argon2Params{}, nil, fmt.Errorf("maximum memory must be at least %d MiB", calibrationStartMemoryKiB/1024)
+	}
+	if maxMemoryKiB > argon2MaxMemoryKiB {
+		return Argon2Params{}, nil, fmt.Errorf("maximum memory must be at most %d MiB", argon2MaxMemoryKiB/1024)
+	}
+	if threads == 0 {
+		return Argon2Params{}, nil, fmt.Errorf("threads must be at least 1")
+	}
+	salt, err := calibrationSalt()
+	if err != nil {
+		return Argon2Params{}, nil, err
+	}
+
+	var steps []CalibrationStep
+	p := Argon2Params{Time: 1, MemoryKiB: calibrationStartMemoryKiB, Threads: threads}
+	for {
+		start := time.Now()
+		argon2.IDKey([]byte(calibrationPassphrase), salt, p.Time, p.MemoryKiB, p.Threads, argon2KeyLen)
+		elapsed := time.Since(start)
+		steps = append(steps, CalibrationStep{Params: p, Duration: elapsed})
+		if elapsed >= target || p.Time >= argon2MaxTime { // argon2MaxTime guards against targets no reasonable cost can reach
+			return p, steps, nil
+		}
+		if p.MemoryKiB*2 <= maxMemoryKiB {
+			p.MemoryKiB *= 2
+		} else {
+			p.Time++
+		}
+	}
+}
+
+func calibrationSalt() ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate calibration salt: %w", err)
+	}
+	return salt, nil
+}
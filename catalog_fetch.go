@@ -0,0 +1,135 @@
+// cmd/satcli/catalog_fetch.go
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// catalogRetryMaxDelay caps the backoff between fetch attempts.
+	catalogRetryMaxDelay = 30 * time.Second
+	// defaultCatalogCacheTTL follows CelesTrak's request not to download a group more than once
+	// every two hours.
+	defaultCatalogCacheTTL = 2 * time.Hour
+)
+
+// catalogRetryDelay is the backoff before the first retry; it doubles for each one after.
+var catalogRetryDelay = time.Second
+
+// catalogFetch says how fetchCatalog requests a catalog group.
+type catalogFetch struct {
+	Group      string
+	URL        string
+	Timeout    time.Duration // Per attempt
+	MaxRetries int           // Further attempts after a network error or 5xx response
+	CacheDir   string        // Empty disables the cache
+	CacheTTL   time.Duration // How long a cached response is used instead of fetching; 0 always fetches
+}
+
+// catalogCacheDir returns <user cache dir>/satcli/catalogs (~/.cache/satcli/catalogs on Linux).
+func catalogCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "satcli", "catalogs"), nil
+}
+
+// cachePath returns the cache file of the group and URL.
+func (f catalogFetch) cachePath() string {
+	sum := sha256.Sum256([]byte(f.Group + "\x00" + f.URL))
+	return filepath.Join(f.CacheDir, hex.EncodeToString(sum[:16])+".tle")
+}
+
+// fetchCatalog returns the body of f.URL, or of its cached copy if that is younger than f.CacheTTL.
+// Network errors and 5xx responses are retried up to f.MaxRetries times with exponential backoff
+// and jitter, and a fetched body is cached in f.CacheDir. A cache that can't be read or written
+// only costs a fetch.
+func fetchCatalog(ctx context.Context, f catalogFetch) ([]byte, error) {
+	if f.CacheDir != "" && f.CacheTTL > 0 {
+		if info, err := os.Stat(f.cachePath()); err == nil && time.Since(info.ModTime()) < f.CacheTTL {
+			if data, err := os.ReadFile(f.cachePath()); err == nil {
+				logger.Debug("catalog read from cache", "url", f.URL, "path", f.cachePath(), "age", time.Since(info.ModTime()).Round(time.Second))
+				return data, nil
+			}
+		}
+	}
+
+	delay := catalogRetryDelay
+	for attempt := 0; ; attempt++ {
+		data, retry, err := fetchCatalogOnce(ctx, f.URL, f.Timeout)
+		if err == nil {
+			if f.CacheDir != "" {
+				if err := writeCatalogCache(f.cachePath(), data); err != nil {
+					logger.Debug("catalog cache not written", "path", f.cachePath(), "error", err)
+				}
+			}
+			return data, nil
+		}
+		if !retry || attempt >= f.MaxRetries || ctx.Err() != nil {
+			return nil, err
+		}
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)) // Jitter spreads out clients retrying together
+		noticef("Warning: %v; retrying in %s (%d of %d).\n", err, wait.Round(100*time.Millisecond), attempt+1, f.MaxRetries)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, catalogRetryMaxDelay)
+	}
+}
+
+// fetchCatalogOnce GETs rawURL, returning the body of a 200 response. retry reports whether the
+// failure is a network error or a 5xx response, which a later attempt may not meet.
+func fetchCatalogOnce(ctx context.Context, rawURL string, timeout time.Duration) (data []byte, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("User-Agent", "satcli")
+	logger.Debug("catalog fetch started", "url", rawURL)
+	resp, err := (&http.Client{Timeout: timeout}).Do(req)
+	if err != nil {
+		return nil, !errors.Is(err, context.Canceled), fmt.Errorf("failed to fetch the catalog: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode >= 500, fmt.Errorf("failed to fetch the catalog: %s returned %s", rawURL, resp.Status)
+	}
+	if data, err = io.ReadAll(resp.Body); err != nil {
+		return nil, true, fmt.Errorf("failed to fetch the catalog: %w", err)
+	}
+	return data, false, nil
+}
+
+// writeCatalogCache replaces the cache file at path with data, through a rename so a concurrent
+// reader never sees part of it.
+func writeCatalogCache(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fetch-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
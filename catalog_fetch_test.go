@@ -0,0 +1,145 @@
+// cmd/satcli/catalog_fetch_test.go
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// catalogServer answers with the statuses in order, then with 200 and body; it counts requests.
+func catalogServer(t *testing.T, body string, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// quietRetries makes retries immediate and their notices silent for the rest of the test.
+func quietRetries(t *testing.T) {
+	delay, out := catalogRetryDelay, noticeOut
+	catalogRetryDelay, noticeOut = time.Millisecond, io.Discard
+	t.Cleanup(func() { catalogRetryDelay, noticeOut = delay, out })
+}
+
+func TestFetchCatalogRetries(t *testing.T) {
+	quietRetries(t)
+	tests := []struct {
+		name         string
+		statuses     []int
+		maxRetries   int
+		wantErr      bool
+		wantRequests int32
+	}{
+		{"ok", nil, 3, false, 1},
+		{"5xx then ok", []int{503, 500}, 3, false, 3},
+		{"5xx beyond the retries", []int{503, 503, 503}, 2, true, 3},
+		{"no retries", []int{502}, 0, true, 1},
+		{"4xx not retried", []int{404}, 3, true, 1},
+		{"429 not retried", []int{429}, 3, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := catalogServer(t, "ISS (ZARYA)\n", tt.statuses...)
+			data, err := fetchCatalog(context.Background(), catalogFetch{Group: "stations", URL: server.URL, Timeout: time.Second, MaxRetries: tt.maxRetries})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && string(data) != "ISS (ZARYA)\n" {
+				t.Errorf("body = %q", data)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("%d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestFetchCatalogRetriesNetworkErrors(t *testing.T) {
+	quietRetries(t)
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close() // Connections are now refused
+	if _, err := fetchCatalog(context.Background(), catalogFetch{URL: url, Timeout: time.Second, MaxRetries: 2}); err == nil {
+		t.Fatal("fetch from a closed server succeeded")
+	}
+}
+
+func TestFetchCatalogCache(t *testing.T) {
+	server, requests := catalogServer(t, "fresh\n")
+	fetch := catalogFetch{Group: "active", URL: server.URL, Timeout: time.Second, CacheDir: t.TempDir(), CacheTTL: time.Hour}
+
+	for i := 0; i < 2; i++ {
+		if data, err := fetchCatalog(context.Background(), fetch); err != nil || string(data) != "fresh\n" {
+			t.Fatalf("fetch %d = %q, %v", i, data, err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("%d requests within the TTL, want 1", got)
+	}
+
+	other := fetch
+	other.Group = "stations" // Same URL, different group: not the same cache entry
+	if _, err := fetchCatalog(context.Background(), other); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("%d requests after fetching another group, want 2", got)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(fetch.cachePath(), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fetchCatalog(context.Background(), fetch); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Fatalf("%d requests after the TTL expired, want 3", got)
+	}
+
+	always := fetch
+	always.CacheTTL = 0
+	if _, err := fetchCatalog(context.Background(), always); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 4 {
+		t.Fatalf("%d requests with a zero TTL, want 4", got)
+	}
+
+	noCache := fetch
+	noCache.CacheDir = ""
+	if _, err := fetchCatalog(context.Background(), noCache); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 5 {
+		t.Fatalf("%d requests without a cache, want 5", got)
+	}
+}
+
+func TestFetchCatalogDoesNotCacheFailures(t *testing.T) {
+	server, requests := catalogServer(t, "fresh\n", http.StatusNotFound)
+	fetch := catalogFetch{Group: "active", URL: server.URL, Timeout: time.Second, CacheDir: t.TempDir(), CacheTTL: time.Hour}
+	if _, err := fetchCatalog(context.Background(), fetch); err == nil {
+		t.Fatal("404 fetch succeeded")
+	}
+	if data, err := fetchCatalog(context.Background(), fetch); err != nil || string(data) != "fresh\n" {
+		t.Fatalf("fetch after a failure = %q, %v", data, err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("%d requests, want 2", got)
+	}
+}
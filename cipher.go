@@ -0,0 +1,126 @@
+// internal/crypto/cipher.go
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// CipherID identifies an authenticated encryption algorithm in the datastore file header.
+type CipherID byte
+
+const (
+	CipherAESGCM           CipherID = 1
+	CipherChaCha20Poly1305 CipherID = 2
+)
+
+// ErrWrongPassphrase is returned (wrapped) by Cipher.Decrypt when authentication fails.
+// An AEAD cannot tell a wrong key from tampered ciphertext, so this also covers corruption
+// that leaves the data long enough to hold a nonce and tag.
+var ErrWrongPassphrase = errors.New("failed to decrypt data (likely incorrect passphrase or corrupted data)")
+
+// ErrCorrupted is returned (wrapped) by Cipher.Decrypt when the data cannot be a sealed payload at
+// all, such as when it is truncated below a nonce and authentication tag; no passphrase would help.
+var ErrCorrupted = errors.New("encrypted data is corrupted")
+
+// minSealedSize is the shortest possible nonce+ciphertext: both ciphers use 12-byte nonces and
+// 16-byte authentication tags, and the plaintext may be empty.
+const minSealedSize = 12 + 16
+
+// checkSealedSize fails with ErrCorrupted if nonceAndCiphertext is too short to authenticate.
+func checkSealedSize(nonceAndCiphertext []byte) error {
+	if len(nonceAndCiphertext) < minSealedSize {
+		return fmt.Errorf("%w: %d byte(s) of nonce and ciphertext, at least %d expected (truncated?)", ErrCorrupted, len(nonceAndCiphertext), minSealedSize)
+	}
+	return nil
+}
+
+// Cipher encrypts and decrypts the datastore payload with a derived key.
+// Encrypt returns nonce+ciphertext; Decrypt accepts the same layout.
+type Cipher interface {
+	ID() CipherID
+	Name() string
+	Encrypt(plaintext, key []byte) ([]byte, error)
+	Decrypt(nonceAndCiphertext, key []byte) ([]byte, error)
+}
+
+// AESGCMCipher is the default Cipher, backed by Encrypt and Decrypt.
+type AESGCMCipher struct{}
+
+func (AESGCMCipher) ID() CipherID { return CipherAESGCM }
+func (AESGCMCipher) Name() string { return "aes-gcm" }
+func (AESGCMCipher) Encrypt(plaintext, key []byte) ([]byte, error) {
+	return Encrypt(plaintext, key)
+}
+func (AESGCMCipher) Decrypt(nonceAndCiphertext, key []byte) ([]byte, error) {
+	if err := checkSealedSize(nonceAndCiphertext); err != nil {
+		return nil, err
+	}
+	plaintext, err := Decrypt(nonceAndCiphertext, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWrongPassphrase, err)
+	}
+	return plaintext, nil
+}
+
+// ChaCha20Poly1305Cipher is an alternative to AES-GCM that is fast without AES hardware support.
+type ChaCha20Poly1305Cipher struct{}
+
+func (ChaCha20Poly1305Cipher) ID() CipherID { return CipherChaCha20Poly1305 }
+func (ChaCha20Poly1305Cipher) Name() string { return "chacha20-poly1305" }
+func (ChaCha20Poly1305Cipher) Encrypt(plaintext, key []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ChaCha20-Poly1305 cipher: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+func (ChaCha20Poly1305Cipher) Decrypt(nonceAndCiphertext, key []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ChaCha20-Poly1305 cipher: %w", err)
+	}
+	if err := checkSealedSize(nonceAndCiphertext); err != nil {
+		return nil, err
+	}
+	nonce, ciphertext := nonceAndCiphertext[:aead.NonceSize()], nonceAndCiphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWrongPassphrase, err)
+	}
+	return plaintext, nil
+}
+
+// DefaultCipher is used for new datastores unless another cipher is selected.
+var DefaultCipher Cipher = AESGCMCipher{}
+
+var ciphers = []Cipher{AESGCMCipher{}, ChaCha20Poly1305Cipher{}}
+
+// CipherByID returns the Cipher recorded under id in a file header.
+func CipherByID(id CipherID) (Cipher, error) {
+	for _, c := range ciphers {
+		if c.ID() == id {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown cipher id %d", id)
+}
+
+// CipherByName returns the Cipher with the given name (case-insensitive).
+func CipherByName(name string) (Cipher, error) {
+	for _, c := range ciphers {
+		if strings.EqualFold(c.Name(), name) {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown cipher '%s'. Supported: aes-gcm, chacha20-poly1305", name)
+}
@@ -0,0 +1,206 @@
+// internal/crypto/cipher_test.go
+package crypto_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/crypto"
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+)
+
+var testCiphers = []crypto.Cipher{crypto.AESGCMCipher{}, crypto.ChaCha20Poly1305Cipher{}}
+
+func TestCipherRoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	wrongKey := bytes.Repeat([]byte{8}, 32)
+	for _, c := range testCiphers {
+		for _, plaintext := range [][]byte{{}, []byte("{}"), bytes.Repeat([]byte("satellite "), 10000)} {
+			t.Run(fmt.Sprintf("%s %d bytes", c.Name(), len(plaintext)), func(t *testing.T) {
+				sealed, err := c.Encrypt(plaintext, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := c.Decrypt(sealed, key)
+				if err != nil {
+					t.Fatalf("Decrypt: %v", err)
+				}
+				if !bytes.Equal(got, plaintext) {
+					t.Error("plaintext changed in the round trip")
+				}
+				if again, _ := c.Encrypt(plaintext, key); bytes.Equal(again, sealed) {
+					t.Error("two encryptions are identical; the nonce is not random")
+				}
+				if _, err := c.Decrypt(sealed, wrongKey); err == nil {
+					t.Error("Decrypt succeeded with the wrong key")
+				}
+			})
+		}
+	}
+}
+
+func TestCiphersDoNotReadEachOther(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	sealed, err := crypto.AESGCMCipher{}.Encrypt([]byte("{}"), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (crypto.ChaCha20Poly1305Cipher{}).Decrypt(sealed, key); err == nil {
+		t.Error("ChaCha20-Poly1305 decrypted AES-GCM data")
+	}
+}
+
+func TestCipherByIDAndName(t *testing.T) {
+	for _, c := range testCiphers {
+		if got, err := crypto.CipherByID(c.ID()); err != nil || got != c {
+			t.Errorf("CipherByID(%d) = %v, %v", c.ID(), got, err)
+		}
+		if got, err := crypto.CipherByName(c.Name()); err != nil || got != c {
+			t.Errorf("CipherByName(%q) = %v, %v", c.Name(), got, err)
+		}
+	}
+	if _, err := crypto.CipherByID(0); err == nil {
+		t.Error("CipherByID accepted id 0")
+	}
+}
+
+// TestDatastoreSaveLoadEachFormat creates a datastore with every KDF and cipher, saves a record,
+// and loads it back with the new-store settings reset, so only the file header selects the format.
+func TestDatastoreSaveLoadEachFormat(t *testing.T) {
+	const passphrase = "Correct-Horse-9-battery"
+	t.Setenv(config.PassphraseEnvVar, passphrase)
+	datastore.SetNoticeWriter(io.Discard)
+	params := &crypto.Argon2Params{Time: 1, MemoryKiB: 8 * 1024, Threads: 1}
+	t.Cleanup(func() {
+		datastore.SetNewStoreKDF("argon2id")
+		datastore.SetNewStoreCipher("aes-gcm")
+		datastore.SetPath("")
+	})
+	sat := types.Satellite{Name: "ISS", OrbitType: "LEO", Altitude: 420, Operator: "NASA", Status: "active",
+		LaunchDate: "1998-11-20", Custom: map[string]string{"noradId": "25544"}}
+
+	tests := []struct {
+		kdf    string
+		params *crypto.Argon2Params
+		kdfID  crypto.KDFID
+	}{
+		{"argon2id", nil, crypto.KDFArgon2id},
+		{"argon2id", params, crypto.KDFArgon2idParams},
+		{"scrypt", nil, crypto.KDFScrypt},
+	}
+	for _, tt := range tests {
+		for _, c := range testCiphers {
+			t.Run(fmt.Sprintf("kdf %d %s", tt.kdfID, c.Name()), func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "satellites.dat")
+				datastore.SetPath(path)
+				if err := datastore.SetNewStoreKDF(tt.kdf); err != nil {
+					t.Fatal(err)
+				}
+				if err := datastore.SetNewStoreArgon2Params(tt.params); err != nil {
+					t.Fatal(err)
+				}
+				if err := datastore.SetNewStoreCipher(c.Name()); err != nil {
+					t.Fatal(err)
+				}
+				if err := datastore.Create(context.Background(), false); err != nil {
+					t.Fatalf("Create: %v", err)
+				}
+				if err := datastore.AddSatellite(sat); err != nil {
+					t.Fatal(err)
+				}
+				if err := datastore.Save(); err != nil {
+					t.Fatalf("Save: %v", err)
+				}
+
+				file, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if kdfID := crypto.KDFID(file[5]); kdfID != tt.kdfID {
+					t.Errorf("header KDF id = %d, want %d", kdfID, tt.kdfID)
+				}
+				if c.ID() != crypto.CipherAESGCM || tt.params != nil {
+					if cipherID := crypto.CipherID(file[6]); file[4] != 2 || cipherID != c.ID() {
+						t.Errorf("header version %d cipher id %d, want version 2 cipher id %d", file[4], cipherID, c.ID())
+					}
+				}
+
+				datastore.SetNewStoreKDF("argon2id") // A store opens with the format in its header
+				datastore.SetNewStoreCipher("aes-gcm")
+				if err := datastore.Init(); err != nil {
+					t.Fatalf("Init: %v", err)
+				}
+				if !datastore.IsUnlocked() {
+					t.Fatal("datastore not unlocked after loading")
+				}
+				got, err := datastore.GetSatellites()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := map[string]types.Satellite{"ISS": sat}; !reflect.DeepEqual(got, want) {
+					t.Errorf("loaded %+v, want %+v", got, want)
+				}
+				if err := datastore.Verify(context.Background()); err != nil {
+					t.Errorf("Verify: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestCipherDecryptDamagedInput(t *testing.T) {
+	const minSealedSize = 12 + 16 // Nonce and tag; mirrors the unexported constant
+	key := bytes.Repeat([]byte{7}, 32)
+	plaintext := []byte(`{"ISS":{"name":"ISS"}}`)
+	flip := func(i int) func([]byte) []byte {
+		return func(sealed []byte) []byte {
+			if i < 0 {
+				i += len(sealed)
+			}
+			sealed[i] ^= 0x01
+			return sealed
+		}
+	}
+	cut := func(n int) func([]byte) []byte {
+		return func(sealed []byte) []byte { return sealed[:n] }
+	}
+	tests := []struct {
+		name    string
+		damage  func(sealed []byte) []byte
+		wantErr error
+	}{
+		{"flipped nonce byte", flip(0), crypto.ErrWrongPassphrase},
+		{"flipped ciphertext byte", flip(12), crypto.ErrWrongPassphrase},
+		{"flipped tag byte", flip(-1), crypto.ErrWrongPassphrase},
+		{"cut to the minimum size", cut(minSealedSize), crypto.ErrWrongPassphrase},
+		{"cut below the minimum size", cut(minSealedSize - 1), crypto.ErrCorrupted},
+		{"nonce only", cut(12), crypto.ErrCorrupted},
+		{"empty", cut(0), crypto.ErrCorrupted},
+	}
+	for _, c := range testCiphers {
+		if sealed, _ := c.Encrypt(nil, key); len(sealed) != minSealedSize {
+			t.Errorf("%s: empty plaintext sealed to %d bytes, want %d", c.Name(), len(sealed), minSealedSize)
+		}
+		for _, tt := range tests {
+			t.Run(c.Name()+" "+tt.name, func(t *testing.T) {
+				sealed, err := c.Encrypt(plaintext, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = c.Decrypt(tt.damage(sealed), key)
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+			})
+		}
+	}
+}
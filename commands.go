@@ -1,7 +1,684 @@
 // cmd/satcli/commands.go
 package main
 
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
 // This file can be used to move Cobra Command RunE functions
 // (for queryCmd, addCmd, listCmd, explainCmd) if main.go becomes too large.
 // For example:
 // func queryCmdRunE(cmd *cobra.Command, args []string) error { ... }
+
+// validateSatellite checks the fields every stored record must satisfy, returning the first problem.
+func validateSatellite(sat types.Satellite) error {
+	if problems := satelliteFieldErrors(sat); len(problems) > 0 {
+		return problems[0].Err
+	}
+	return nil
+}
+
+// fieldError is a problem with one field of a record, named by its JSON key.
+type fieldError struct {
+	Field string
+	Err   error
+}
+
+// satelliteFieldErrors returns every check of validateSatellite that sat fails, in order, so a
+// caller can tell which fields are at fault.
+func satelliteFieldErrors(sat types.Satellite) []fieldError {
+	var problems []fieldError
+	if strings.TrimSpace(sat.Name) == "" {
+		problems = append(problems, fieldError{"name", fmt.Errorf("satellite name cannot be empty")})
+	}
+	if sat.LaunchDate != "" {
+		if _, err := time.Parse(config.DateFormat, sat.LaunchDate); err != nil {
+			problems = append(problems, fieldError{"launchDate", fmt.Errorf("invalid launchDate '%s'. Use YYYY-MM-DD", sat.LaunchDate)})
+		}
+	}
+	if sat.SemiMajorAxisKm != 0 && sat.SemiMajorAxisKm <= types.EarthRadiusKm {
+		problems = append(problems, fieldError{"semiMajorAxis", fmt.Errorf("semiMajorAxis (%.0f km) must be greater than Earth's radius (%.0f km)", sat.SemiMajorAxisKm, types.EarthRadiusKm)})
+	}
+	if sat.Altitude < 0 {
+		problems = append(problems, fieldError{"altitude", fmt.Errorf("altitude cannot be negative (%.0f)", sat.Altitude)})
+	}
+	if sat.Longitude < -180 || sat.Longitude > 180 {
+		problems = append(problems, fieldError{"longitude", fmt.Errorf("longitude (%g) must be between -180 and 180 degrees", sat.Longitude)})
+	}
+	return problems
+}
+
+// warnIfOrbitInconsistent prints a warning when --warn-inconsistent is set and sat's altitude does
+// not fit its orbit type (see types.Satellite.ValidateOrbitConsistency). The record is still stored.
+func warnIfOrbitInconsistent(cmd *cobra.Command, sat types.Satellite) {
+	if warn, _ := cmd.Flags().GetBool("warn-inconsistent"); !warn {
+		return
+	}
+	if err := sat.ValidateOrbitConsistency(); err != nil {
+		noticef("Warning: '%s': %v\n", sat.Name, err)
+	}
+}
+
+// addFromStdin reads newline-delimited JSON satellites from r and adds them in a single save.
+// Nothing is stored if any line fails to parse or validate.
+func addFromStdin(cmd *cobra.Command, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var sats []types.Satellite
+	failed := 0
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var sat types.Satellite
+		if err := json.Unmarshal([]byte(line), &sat); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "line %d: invalid JSON: %v\n", lineNo, err)
+			failed++
+			continue
+		}
+		sat.Operator = types.NormalizeOperator(sat.Operator)
+		if err := validateSatellite(sat); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "line %d: %v\n", lineNo, err)
+			failed++
+			continue
+		}
+		warnIfOrbitInconsistent(cmd, sat)
+		sats = append(sats, sat)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stdin: %w", err)
+	}
+
+	cmd.SilenceUsage = true
+	if failed > 0 {
+		return fmt.Errorf("%d line(s) failed validation; no records were added", failed)
+	}
+	if len(sats) == 0 {
+		return fmt.Errorf("no satellite records read from stdin")
+	}
+	for _, sat := range sats {
+		if err := datastore.AddSatellite(sat); err != nil {
+			return err
+		}
+	}
+	if err := datastore.SaveCtx(cmd.Context()); err != nil {
+		return fmt.Errorf("failed to save %d record(s) read from stdin: %w", len(sats), err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Records added: %d (encrypted in datastore)\n", len(sats))
+	return nil
+}
+
+// promptMissingFields asks on out for the fields the positional add cannot set, reading answers
+// from in. An empty answer keeps the value already in sat; an invalid one is reported and asked again.
+func promptMissingFields(in io.Reader, out io.Writer, sat *types.Satellite) error {
+	reader := bufio.NewReader(in)
+	ask := func(question, current string, parse func(string) error) error {
+		for {
+			fmt.Fprintf(out, "%s [%s]: ", question, current)
+			answer, err := reader.ReadString('\n')
+			answer = strings.TrimSpace(answer)
+			if err != nil && (err != io.EOF || answer == "") {
+				return fmt.Errorf("failed to read %s: %w", strings.ToLower(question), err)
+			}
+			if answer == "" {
+				return nil
+			}
+			if errParse := parse(answer); errParse != nil {
+				fmt.Fprintf(out, "  %v\n", errParse)
+				if err == io.EOF {
+					return errParse
+				}
+				continue
+			}
+			return nil
+		}
+	}
+	if err := ask("Altitude in km", fmt.Sprintf("%g", sat.Altitude), func(s string) error {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 {
+			return fmt.Errorf("altitude must be a non-negative number of km, got '%s'", s)
+		}
+		sat.Altitude = v
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := ask("Launch date (YYYY-MM-DD)", sat.LaunchDate, func(s string) error {
+		if _, err := time.Parse(config.DateFormat, s); err != nil {
+			return fmt.Errorf("invalid launch date '%s'. Use YYYY-MM-DD", s)
+		}
+		sat.LaunchDate = s
+		return nil
+	}); err != nil {
+		return err
+	}
+	return ask("Inclination in degrees", fmt.Sprintf("%g", sat.Inclination), func(s string) error {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 || v > 180 {
+			return fmt.Errorf("inclination must be a number of degrees from 0 to 180, got '%s'", s)
+		}
+		sat.Inclination = v
+		return nil
+	})
+}
+
+// parseCustomPairs parses key=value flag values into a custom attribute map (nil if pairs is empty).
+func parseCustomPairs(flagName string, pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	custom := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid value for %s: '%s'. Use key=value", flagName, pair)
+		}
+		custom[key] = strings.TrimSpace(value)
+	}
+	return custom, nil
+}
+
+// matchesCustom reports whether sat has every key in filter with an equal value (case-insensitive).
+func matchesCustom(sat types.Satellite, filter map[string]string) bool {
+	for key, want := range filter {
+		got, ok := sat.Custom[key]
+		if !ok || !strings.EqualFold(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// tagModes are the accepted --tag-mode values.
+var tagModes = []string{"any", "all"}
+
+// matchesTags reports whether sat has any (or, with all, every) tag in tags; no tags match everything.
+func matchesTags(sat types.Satellite, tags []string, all bool) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		has := sat.HasTag(tag)
+		if has && !all {
+			return true
+		}
+		if !has && all {
+			return false
+		}
+	}
+	return all
+}
+
+// launchDateLayouts are the LaunchDate formats understood by date filters and sorting, tried in order.
+var launchDateLayouts = []string{config.DateFormat, "2006/01/02", "2006.01.02", time.RFC3339, "2 Jan 2006", "Jan 2, 2006", "January 2, 2006"}
+
+// parseLaunchDate parses s with the first matching launchDateLayouts entry and returns its calendar date (UTC).
+func parseLaunchDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range launchDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized launch date '%s'. Use YYYY-MM-DD, YYYY/MM/DD, YYYY.MM.DD, RFC3339, '2 Jan 2006' or 'Jan 2, 2006'", s)
+}
+
+// launchDateSortKey normalizes parsable launch dates to YYYY-MM-DD so mixed formats sort together.
+func launchDateSortKey(sat types.Satellite) string {
+	if t, err := parseLaunchDate(sat.LaunchDate); err == nil {
+		return t.Format(config.DateFormat)
+	}
+	return sat.LaunchDate
+}
+
+// completeOrbitTypes completes orbit type flag values from types.Orbits, described by their full names.
+func completeOrbitTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, name := range types.OrbitNames() {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(toComplete)) {
+			info, _ := types.LookupOrbit(name)
+			completions = append(completions, name+"\t"+info.FullName)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// geoBandToleranceKm widens fixed-altitude bands (GEO, GSO) so station-kept satellites still match.
+const geoBandToleranceKm = 100
+
+// altitudeBandRange returns the altitude range in km of the orbit type named by band, from types.Orbits.
+func altitudeBandRange(band string) (float64, float64, error) {
+	info, ok := types.LookupOrbit(band)
+	if !ok || info.AltitudeMaxKm == 0 {
+		return 0, 0, fmt.Errorf("invalid value for --altitude-band: '%s'. Use one of: %s", band, strings.Join(altitudeBandNames(), ", "))
+	}
+	minKm, maxKm := info.AltitudeMinKm, info.AltitudeMaxKm
+	if minKm == maxKm {
+		minKm, maxKm = minKm-geoBandToleranceKm, maxKm+geoBandToleranceKm
+	}
+	return minKm, maxKm, nil
+}
+
+// altitudeBandNames lists the orbit types usable with --altitude-band (those with an Earth altitude range), lower-cased.
+func altitudeBandNames() []string {
+	var names []string
+	for _, name := range types.OrbitNames() {
+		if info, _ := types.LookupOrbit(name); info.AltitudeMaxKm > 0 {
+			names = append(names, strings.ToLower(name))
+		}
+	}
+	return names
+}
+
+// constellationFlags are the alternative ways to filter by constellation status; an explicit one
+// overrides any of them saved in a profile.
+var constellationFlags = []string{"constellation", "constellation-only", "exclude-constellation"}
+
+// addConstellationShorthandFlags registers --constellation-only and --exclude-constellation, which
+// select what --constellation true and --constellation false do.
+func addConstellationShorthandFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("constellation-only", false, "Only constellation members (same as --constellation true)")
+	cmd.Flags().Bool("exclude-constellation", false, "Only satellites outside a constellation (same as --constellation false)")
+}
+
+// constellationFilterFromFlags returns the constellation status selected by --constellation,
+// --constellation-only or --exclude-constellation, or nil if none is given. At most one may be given.
+func constellationFilterFromFlags(cmd *cobra.Command) (*bool, error) {
+	value, _ := cmd.Flags().GetString("constellation")
+	only, _ := cmd.Flags().GetBool("constellation-only")
+	exclude, _ := cmd.Flags().GetBool("exclude-constellation")
+	given := 0
+	for _, set := range []bool{value != "", only, exclude} {
+		if set {
+			given++
+		}
+	}
+	if given > 1 {
+		return nil, fmt.Errorf("--constellation, --constellation-only and --exclude-constellation cannot be combined")
+	}
+	switch {
+	case only:
+		return &only, nil
+	case exclude:
+		member := false
+		return &member, nil
+	case value != "":
+		member, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --constellation: '%s'. Use 'true' or 'false'", value)
+		}
+		return &member, nil
+	}
+	return nil, nil
+}
+
+// addArchivedFlags registers --include-archived and --archived-only on commands that hide archived
+// records by default.
+func addArchivedFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("include-archived", false, "Include archived records (see 'satcli archive')")
+	cmd.Flags().Bool("archived-only", false, "Only archived records")
+}
+
+// archivedFilterFromFlags returns the Archived value records must have: false by default, true with
+// --archived-only, or nil (any) with --include-archived. The two flags cannot be combined.
+func archivedFilterFromFlags(cmd *cobra.Command) (*bool, error) {
+	include, _ := cmd.Flags().GetBool("include-archived")
+	only, _ := cmd.Flags().GetBool("archived-only")
+	switch {
+	case include && only:
+		return nil, fmt.Errorf("--include-archived and --archived-only cannot be combined")
+	case include:
+		return nil, nil
+	}
+	return &only, nil
+}
+
+// lookupSatellite finds the record stored under name or, failing that, the one whose name equals
+// it ignoring case, as the filters compare. ok is false if there is none; the error lists the
+// candidates when several stored names differ from name only in case.
+func lookupSatellite(sats map[string]types.Satellite, name string) (sat types.Satellite, ok bool, err error) {
+	if sat, ok := sats[name]; ok {
+		return sat, true, nil
+	}
+	var candidates []string
+	for key := range sats {
+		if strings.EqualFold(key, name) {
+			candidates = append(candidates, key)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return types.Satellite{}, false, nil
+	case 1:
+		return sats[candidates[0]], true, nil
+	}
+	sort.Strings(candidates)
+	return types.Satellite{}, false, fmt.Errorf("satellite name '%s' is ambiguous: it matches %s; use the exact name", name, strings.Join(candidates, ", "))
+}
+
+// defaultMaxResults is the --max-results default, far above any normal datastore.
+const defaultMaxResults = 100000
+
+// addResultCapFlags registers --limit, --max-results and --force on commands that write every record
+// as one document.
+func addResultCapFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("limit", 0, "Write at most this many records, after sorting (0 means all)")
+	cmd.Flags().Int("max-results", defaultMaxResults, "Refuse to write more records than this as one document unless --force is given (0 means no cap)")
+	cmd.Flags().Bool("force", false, "Write more than --max-results records")
+}
+
+// limitResults applies --limit to sats, then fails if more than --max-results remain and neither
+// streamed (output written record by record) nor --force is set. remedy suggests how else to
+// get the records.
+func limitResults(cmd *cobra.Command, sats []types.Satellite, streamed bool, remedy string) ([]types.Satellite, error) {
+	limit, _ := cmd.Flags().GetInt("limit")
+	maxResults, _ := cmd.Flags().GetInt("max-results")
+	force, _ := cmd.Flags().GetBool("force")
+	if limit < 0 || maxResults < 0 {
+		return nil, fmt.Errorf("--limit and --max-results cannot be negative")
+	}
+	if limit > 0 && len(sats) > limit {
+		sats = sats[:limit]
+	}
+	if maxResults > 0 && len(sats) > maxResults && !streamed && !force {
+		return nil, fmt.Errorf("%d records exceed --max-results (%d) for a single document; %s", len(sats), maxResults, remedy)
+	}
+	return sats, nil
+}
+
+// addFirstLastFlags registers --first and --last on list.
+func addFirstLastFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("first", 0, "Show only the first N records in the sort order")
+	cmd.Flags().Int("last", 0, "Show only the last N records in the sort order")
+}
+
+// firstOrLast applies --first or --last to sats, which must already be sorted. They cannot be
+// combined with each other or with --limit, which --first duplicates.
+func firstOrLast(cmd *cobra.Command, sats []types.Satellite) ([]types.Satellite, error) {
+	first, _ := cmd.Flags().GetInt("first")
+	last, _ := cmd.Flags().GetInt("last")
+	given := 0
+	for _, name := range []string{"first", "last", "limit"} {
+		if cmd.Flags().Changed(name) {
+			given++
+		}
+	}
+	switch {
+	case first < 0 || last < 0:
+		return nil, fmt.Errorf("--first and --last cannot be negative")
+	case given > 1:
+		return nil, fmt.Errorf("--first, --last and --limit cannot be combined")
+	case first > 0 && len(sats) > first:
+		return sats[:first], nil
+	case last > 0 && len(sats) > last:
+		return sats[len(sats)-last:], nil
+	}
+	return sats, nil
+}
+
+// rangeClause describes a bounded filter for --explain-query, e.g. `altitude in [500, 600] km`.
+// An empty min or max is unbounded; with both empty it returns "".
+func rangeClause(field, min, max, unit string) string {
+	if unit != "" {
+		unit = " " + unit
+	}
+	switch {
+	case min != "" && max != "":
+		return fmt.Sprintf("%s in [%s, %s]%s", field, min, max, unit)
+	case min != "":
+		return fmt.Sprintf("%s >= %s%s", field, min, unit)
+	case max != "":
+		return fmt.Sprintf("%s <= %s%s", field, max, unit)
+	}
+	return ""
+}
+
+// inLongitudeRange reports whether sat is geosynchronous with a slot longitude in [min, max], or in
+// [min, 180] or [-180, max] when min > max, for a range that crosses the antimeridian.
+func inLongitudeRange(sat types.Satellite, min, max float64) bool {
+	if !sat.IsGeosynchronous() {
+		return false
+	}
+	if min > max {
+		return sat.Longitude >= min || sat.Longitude <= max
+	}
+	return sat.Longitude >= min && sat.Longitude <= max
+}
+
+// longitudeClause describes the longitude filter for --explain-query, or "" if it is not set.
+func longitudeClause(filtered bool, min, max float64) string {
+	if !filtered {
+		return ""
+	}
+	clause := rangeClause("longitude", strconv.FormatFloat(min, 'f', -1, 64), strconv.FormatFloat(max, 'f', -1, 64), "deg")
+	if min > max {
+		clause = fmt.Sprintf("longitude >= %g or <= %g deg (across 180)", min, max)
+	}
+	return clause + " (GEO/GSO only)"
+}
+
+// bound formats a numeric filter bound for rangeClause, where 0 means no filter.
+func bound(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// printQueryExplanation writes the --explain-query summary: the filter clauses joined with AND,
+// how they match, and the sort order.
+func printQueryExplanation(w io.Writer, clauses []string, sortBy string) {
+	var kept []string
+	for _, c := range clauses {
+		if c != "" {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) == 0 {
+		fmt.Fprintln(w, "Query: all satellites (no filters)")
+	} else {
+		fmt.Fprintln(w, "Query: "+strings.Join(kept, " AND "))
+	}
+	fmt.Fprintln(w, "Match: every condition must hold; text comparisons are exact and case-insensitive")
+	fmt.Fprintln(w, "Sort: by "+sortBy)
+}
+
+// altitudeUnit is a unit altitudes can be displayed and filtered in; altitudes are always stored in km.
+type altitudeUnit struct {
+	Name  string
+	PerKm float64 // Units in one kilometre
+}
+
+var altitudeUnits = []altitudeUnit{
+	{"km", 1},
+	{"mi", 1 / 1.609344}, // International mile
+	{"nmi", 1 / 1.852},   // International nautical mile
+}
+
+// addAltitudeUnitFlag registers --altitude-unit on cmd.
+func addAltitudeUnitFlag(cmd *cobra.Command) {
+	cmd.Flags().String("altitude-unit", "km", "Unit for displayed altitudes and --min/--max-altitude: km, mi, or nmi")
+}
+
+// altitudeUnitFromFlags returns the unit selected by --altitude-unit; commands without the flag use km.
+func altitudeUnitFromFlags(cmd *cobra.Command) (altitudeUnit, error) {
+	if cmd.Flags().Lookup("altitude-unit") == nil {
+		return altitudeUnits[0], nil
+	}
+	name, _ := cmd.Flags().GetString("altitude-unit")
+	var names []string
+	for _, unit := range altitudeUnits {
+		if strings.EqualFold(unit.Name, name) {
+			return unit, nil
+		}
+		names = append(names, unit.Name)
+	}
+	return altitudeUnit{}, fmt.Errorf("invalid value for --altitude-unit: '%s'. Use one of: %s", name, strings.Join(names, ", "))
+}
+
+// convertAltitudes returns a copy of sats with Altitude expressed in unit.
+func convertAltitudes(sats []types.Satellite, unit altitudeUnit) []types.Satellite {
+	converted := make([]types.Satellite, len(sats))
+	for i, sat := range sats {
+		sat.Altitude *= unit.PerKm
+		converted[i] = sat
+	}
+	return converted
+}
+
+// relativeDateFormat is the --date-format value that shows launch dates relative to today.
+const relativeDateFormat = "relative"
+
+// addDateFormatFlag registers --date-format on cmd.
+func addDateFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String("date-format", "", "Display launch dates with a Go time layout (e.g. 'Jan 2, 2006') or 'relative' (e.g. 'launched 3y ago'); stored dates are unchanged")
+}
+
+// dateFormatFromFlags returns the --date-format layout; "" (dates shown as stored) when unset or the command lacks the flag.
+func dateFormatFromFlags(cmd *cobra.Command) (string, error) {
+	if cmd.Flags().Lookup("date-format") == nil {
+		return "", nil
+	}
+	layout, _ := cmd.Flags().GetString("date-format")
+	if layout == "" || strings.EqualFold(layout, relativeDateFormat) {
+		return strings.ToLower(layout), nil
+	}
+	// A layout without any reference-time element would print every date as the same literal text.
+	if time.Date(1999, 11, 30, 13, 57, 58, 0, time.UTC).Format(layout) == layout {
+		return "", fmt.Errorf("invalid value for --date-format: '%s'. Use a Go time layout (e.g. 2006-01-02, 'Jan 2, 2006') or '%s'", layout, relativeDateFormat)
+	}
+	return layout, nil
+}
+
+// formatLaunchDates returns a copy of sats with LaunchDate rendered by formatLaunchDate.
+func formatLaunchDates(sats []types.Satellite, layout string, now time.Time) []types.Satellite {
+	formatted := make([]types.Satellite, len(sats))
+	for i, sat := range sats {
+		sat.LaunchDate = formatLaunchDate(sat.LaunchDate, layout, now)
+		formatted[i] = sat
+	}
+	return formatted
+}
+
+// formatLaunchDate renders a stored launch date in layout, or relative to now. Dates that cannot
+// be parsed are shown as stored, marked "(unparsed)".
+func formatLaunchDate(date, layout string, now time.Time) string {
+	t, err := parseLaunchDate(date)
+	switch {
+	case err != nil && date != "":
+		return date + " (unparsed)"
+	case err != nil:
+		return date
+	case layout == relativeDateFormat:
+		return relativeLaunchDate(t, now)
+	default:
+		return t.Format(layout)
+	}
+}
+
+// relativeLaunchDate describes the calendar date t against now's local calendar date in the largest
+// whole unit, e.g. "launched 3y ago", "launched 5mo ago" or "launches in 12d".
+func relativeLaunchDate(t, now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from, to, future := t, today, t.After(today)
+	if future {
+		from, to = today, t
+	}
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	var span string
+	switch days := int(to.Sub(from).Hours() / 24); {
+	case days == 0:
+		return "launched today"
+	case months >= 12:
+		span = fmt.Sprintf("%dy", months/12)
+	case months >= 1:
+		span = fmt.Sprintf("%dmo", months)
+	default:
+		span = fmt.Sprintf("%dd", days)
+	}
+	if future {
+		return "launches in " + span
+	}
+	return "launched " + span + " ago"
+}
+
+// sortKeys lists the accepted --sort-by values.
+var sortKeys = []string{"name", "operator", "status", "orbit-type", "launch-date", "altitude", "inclination"}
+
+// sortByUsage is the help text of --sort-by.
+var sortByUsage = "Sort results by: " + strings.Join(sortKeys, ", ") + "; comma-separate keys to break ties, and add :desc to reverse one (e.g. operator,altitude:desc)"
+
+// sortComparators compare two satellites by each sort key, returning -1, 0 or +1.
+var sortComparators = map[string]func(a, b types.Satellite) int{
+	"name": func(a, b types.Satellite) int { return strings.Compare(a.Name, b.Name) },
+	"operator": func(a, b types.Satellite) int {
+		return strings.Compare(strings.ToLower(a.Operator), strings.ToLower(b.Operator))
+	},
+	"status": func(a, b types.Satellite) int {
+		return strings.Compare(strings.ToLower(a.Status), strings.ToLower(b.Status))
+	},
+	"orbit-type": func(a, b types.Satellite) int {
+		return strings.Compare(strings.ToLower(a.OrbitType), strings.ToLower(b.OrbitType))
+	},
+	"launch-date": func(a, b types.Satellite) int { return strings.Compare(launchDateSortKey(a), launchDateSortKey(b)) },
+	"altitude":    func(a, b types.Satellite) int { return cmp.Compare(a.Altitude, b.Altitude) },
+	"inclination": func(a, b types.Satellite) int { return cmp.Compare(a.Inclination, b.Inclination) },
+}
+
+// sortSatellites orders sats in place by spec, a comma-separated list of sort keys each optionally
+// suffixed :asc or :desc, such as "operator,altitude:desc". Later keys break ties in earlier ones,
+// and name breaks any that remain.
+func sortSatellites(sats []types.Satellite, spec string) error {
+	if strings.TrimSpace(spec) == "" {
+		spec = "name"
+	}
+	var compares []func(a, b types.Satellite) int
+	for _, part := range strings.Split(spec, ",") {
+		key, direction, _ := strings.Cut(strings.ToLower(strings.TrimSpace(part)), ":")
+		compare, ok := sortComparators[key]
+		if !ok {
+			return fmt.Errorf("invalid value for --sort-by: '%s'. Use one of: %s, each optionally followed by :desc", part, strings.Join(sortKeys, ", "))
+		}
+		switch direction {
+		case "", "asc":
+		case "desc":
+			ascending := compare
+			compare = func(a, b types.Satellite) int { return ascending(b, a) }
+		default:
+			return fmt.Errorf("invalid sort direction '%s' in --sort-by '%s'. Use asc or desc", direction, part)
+		}
+		compares = append(compares, compare)
+	}
+	compares = append(compares, sortComparators["name"])
+	sort.SliceStable(sats, func(i, j int) bool {
+		for _, compare := range compares {
+			if c := compare(sats[i], sats[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
@@ -0,0 +1,127 @@
+// cmd/satcli/commands_test.go
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAltitudeUnitConversion(t *testing.T) {
+	tests := []struct {
+		unit      string
+		km        float64
+		want      float64
+		tolerance float64 // Half the last digit of want
+	}{
+		{"km", 1, 1, 0},
+		{"mi", 1, 0.621371, 5e-7},
+		{"nmi", 1, 0.539957, 5e-7},
+		{"mi", 35786, 22236.39, 5e-3},
+		{"nmi", 35786, 19322.89, 5e-3},
+		{"mi", 1.609344, 1, 1e-12},
+		{"nmi", 1.852, 1, 1e-12},
+		{"nmi", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{Use: "test"}
+		addAltitudeUnitFlag(cmd)
+		if err := cmd.Flags().Set("altitude-unit", tt.unit); err != nil {
+			t.Fatal(err)
+		}
+		unit, err := altitudeUnitFromFlags(cmd)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.unit, err)
+		}
+		if unit.Name != tt.unit {
+			t.Errorf("unit name = %q, want %q", unit.Name, tt.unit)
+		}
+		converted := convertAltitudes([]types.Satellite{{Name: "A", Altitude: tt.km}}, unit)
+		if got := converted[0].Altitude; math.Abs(got-tt.want) > tt.tolerance {
+			t.Errorf("%g km in %s = %g, want %g", tt.km, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestConvertAltitudesLeavesInputUnchanged(t *testing.T) {
+	sats := []types.Satellite{{Name: "A", Altitude: 550}}
+	convertAltitudes(sats, altitudeUnit{"mi", 1 / 1.609344})
+	if sats[0].Altitude != 550 {
+		t.Errorf("input altitude changed to %g", sats[0].Altitude)
+	}
+}
+
+func TestAltitudeUnitFromFlagsRejectsUnknownUnit(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	addAltitudeUnitFlag(cmd)
+	cmd.Flags().Set("altitude-unit", "furlong")
+	if _, err := altitudeUnitFromFlags(cmd); err == nil {
+		t.Error("accepted --altitude-unit furlong")
+	}
+}
+
+func TestFilterFromFlagsConvertsAltitudesToKm(t *testing.T) {
+	tests := []struct {
+		name               string
+		flags              map[string]string
+		wantMinKm, wantMax float64
+	}{
+		{"km", map[string]string{"min-altitude": "500", "max-altitude": "2000"}, 500, 2000},
+		{"mi", map[string]string{"altitude-unit": "mi", "min-altitude": "100", "max-altitude": "1000"}, 160.9344, 1609.344},
+		{"nmi", map[string]string{"altitude-unit": "nmi", "min-altitude": "100", "max-altitude": "19323"}, 185.2, 35786.196},
+		{"band is in km whatever the unit", map[string]string{"altitude-unit": "mi", "altitude-band": "leo"}, bandMin("leo"), bandMax("leo")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newFilterTestCommand()
+			for name, value := range tt.flags {
+				if err := cmd.Flags().Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			f, err := filterFromFlags(cmd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(f.MinAltitude-tt.wantMinKm) > 1e-6 || math.Abs(f.MaxAltitude-tt.wantMax) > 1e-6 {
+				t.Errorf("altitude range = %g-%g km, want %g-%g", f.MinAltitude, f.MaxAltitude, tt.wantMinKm, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestFilterFromFlagsConvertsApsisBoundsToKm(t *testing.T) {
+	cmd := newFilterTestCommand()
+	cmd.Flags().Set("altitude-unit", "nmi")
+	cmd.Flags().Set("min-perigee", "100")
+	cmd.Flags().Set("max-apogee", "1000")
+	f, err := filterFromFlags(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(f.MinPerigee-185.2) > 1e-6 || math.Abs(f.MaxApogee-1852) > 1e-6 {
+		t.Errorf("perigee/apogee = %g/%g km, want 185.2/1852", f.MinPerigee, f.MaxApogee)
+	}
+}
+
+// newFilterTestCommand returns a command with the flags filterFromFlags reads, as query has them.
+func newFilterTestCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	addQueryFilterFlags(cmd)
+	addQueryMatchFlags(cmd)
+	addAltitudeUnitFlag(cmd)
+	return cmd
+}
+
+func bandMin(name string) float64 {
+	low, _, _ := altitudeBandRange(name)
+	return low
+}
+
+func bandMax(name string) float64 {
+	_, high, _ := altitudeBandRange(name)
+	return high
+}
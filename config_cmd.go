@@ -0,0 +1,143 @@
+// cmd/satcli/config_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the satcli configuration file and the settings in effect",
+	Long: `The config file supplies defaults for --output, --datastore, --sort-by, --color, --kdf, --cipher,
+and the Argon2id cost of new datastores (--argon2-time, --argon2-memory in MiB, --argon2-threads).
+Values are resolved as: command-line flag > environment variable > config file > built-in default.
+
+Example config.yaml:
+  output: table
+  datastore: ~/sats.store
+  sort-by: altitude
+  color: auto
+  kdf: argon2id
+  cipher: aes-gcm
+  argon2-time: 3       # see 'satcli crypto calibrate'
+  argon2-memory: 256
+  argon2-threads: 4
+  profiles:            # managed with 'satcli profile'
+    leo-active:
+      orbit-type: LEO
+      status: active
+  operator-aliases:    # see 'satcli operators --aliases'
+    ESA: [European Space Agency, ESA/ESOC]`,
+	// Overrides rootCmd's hook: config commands must work even when the config file is broken.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the resolved config file location",
+	Long:  "Prints the config file path ($" + config.ConfigPathEnvVar + " if set, otherwise the user config directory). The file need not exist.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), path)
+		return nil
+	},
+}
+
+// shownSettings lists the settings 'config show' reports, in display order.
+var shownSettings = []string{"datastore", "output", "sort-by", "color", "kdf", "cipher", "argon2-time", "argon2-memory", "argon2-threads", "quiet", "log-level"}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the settings in effect and where each value comes from",
+	Long: `Resolves each setting the way other commands do (flag > environment variable > config file >
+built-in default) and prints its value and source. Flags given to 'config show' itself, such as
+--datastore or --color, count as the flag source. output and sort-by are the defaults for list and
+query, which take them as their own flags. Nothing is unlocked or written.
+
+Examples:
+  satcli config show
+  SATCOM_SORT_BY=altitude satcli config show --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		file, err := config.Load(path)
+		if err != nil {
+			return err
+		}
+		_, statErr := os.Stat(path)
+		fileValues := file.Values()
+
+		type shownSetting struct {
+			Setting string `json:"setting"`
+			Value   string `json:"value"`
+			Source  string `json:"source"`
+			EnvVar  string `json:"envVar"`
+		}
+		settings := make([]shownSetting, 0, len(shownSettings))
+		for _, name := range shownSettings {
+			from := cmd
+			if name == "output" || name == "sort-by" {
+				from = listCmd // config show's own --output is its format, not the setting
+			}
+			value, source := resolveSetting(from, name, fileValues)
+			if name == "datastore" {
+				if value == "" {
+					value, err = datastore.Path()
+				} else {
+					value, err = config.ExpandHome(value)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			settings = append(settings, shownSetting{name, value, source, settingEnvVars[name]})
+		}
+
+		if strings.ToLower(outputFormat) == "json" {
+			output, errJson := json.MarshalIndent(struct {
+				ConfigFile   string         `json:"configFile"`
+				ConfigExists bool           `json:"configExists"`
+				Settings     []shownSetting `json:"settings"`
+			}{path, statErr == nil, settings}, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal settings to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+		if statErr != nil {
+			path += " (not found)"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Config file: %s\n\n", path)
+		rows := make([][]string, len(settings))
+		for i, s := range settings {
+			rows[i] = []string{s.Setting, s.Value, s.Source, s.EnvVar}
+		}
+		printTableRows(cmd.OutOrStdout(), []tableColumn{{Header: "SETTING"}, {Header: "VALUE"}, {Header: "SOURCE"}, {Header: "ENV"}}, rows)
+		return nil
+	},
+}
+
+func init() {
+	configShowCmd.Flags().StringP("output", "O", "text", "Output format: text or json")
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,117 @@
+// internal/config/config_file.go
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables overriding config file values (flag > env > config file > built-in default).
+const (
+	ConfigPathEnvVar    = "SATCOM_CONFIG"
+	DatastoreEnvVar     = "SATCOM_DATASTORE"
+	OutputEnvVar        = "SATCOM_OUTPUT"
+	SortByEnvVar        = "SATCOM_SORT_BY"
+	ColorEnvVar         = "SATCOM_COLOR"
+	KDFEnvVar           = "SATCOM_KDF"
+	CipherEnvVar        = "SATCOM_CIPHER"
+	Argon2TimeEnvVar    = "SATCOM_ARGON2_TIME"
+	Argon2MemoryEnvVar  = "SATCOM_ARGON2_MEMORY"
+	Argon2ThreadsEnvVar = "SATCOM_ARGON2_THREADS"
+	QuietEnvVar         = "SATCOM_QUIET"
+	LogLevelEnvVar      = "SATCOM_LOG_LEVEL"
+)
+
+// File holds the defaults read from the satcli config file.
+type File struct {
+	Output    string `yaml:"output,omitempty"`
+	Datastore string `yaml:"datastore,omitempty"`
+	SortBy    string `yaml:"sort-by,omitempty"`
+	Color     string `yaml:"color,omitempty"`
+	KDF       string `yaml:"kdf,omitempty"`
+	Cipher    string `yaml:"cipher,omitempty"`
+	// Argon2id cost parameters for new datastores; memory is in MiB.
+	Argon2Time    string `yaml:"argon2-time,omitempty"`
+	Argon2Memory  string `yaml:"argon2-memory,omitempty"`
+	Argon2Threads string `yaml:"argon2-threads,omitempty"`
+	// Profiles maps a profile name to saved query filter flags (flag name -> value).
+	Profiles map[string]map[string]string `yaml:"profiles,omitempty"`
+	// OperatorAliases maps a canonical operator name to its alternative spellings.
+	OperatorAliases map[string][]string `yaml:"operator-aliases,omitempty"`
+}
+
+// Path returns the config file location: $SATCOM_CONFIG if set,
+// otherwise <user config dir>/satcli/config.yaml (~/.config/satcli/config.yaml on Linux).
+func Path() (string, error) {
+	if p := os.Getenv(ConfigPathEnvVar); p != "" {
+		return ExpandHome(p)
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "satcli", "config.yaml"), nil
+}
+
+// Load reads the config file at path. A missing file yields an empty File.
+func Load(path string) (File, error) {
+	var f File
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return f, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Save writes f to path as YAML, creating the parent directory if needed.
+// Comments in an existing file are not preserved.
+func Save(path string, f File) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Values returns the config entries keyed by the flag name they provide a default for.
+func (f File) Values() map[string]string {
+	return map[string]string{
+		"output":         f.Output,
+		"datastore":      f.Datastore,
+		"sort-by":        f.SortBy,
+		"color":          f.Color,
+		"kdf":            f.KDF,
+		"cipher":         f.Cipher,
+		"argon2-time":    f.Argon2Time,
+		"argon2-memory":  f.Argon2Memory,
+		"argon2-threads": f.Argon2Threads,
+	}
+}
+
+// ExpandHome replaces a leading "~/" in p with the user's home directory.
+func ExpandHome(p string) (string, error) {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to expand '~' in %s: %w", p, err)
+	}
+	return filepath.Join(home, p[1:]), nil
+}
@@ -0,0 +1,66 @@
+// types/constellation.go
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+// ConstellationGroup summarizes the constellation members of one operator.
+type ConstellationGroup struct {
+	Operator      string      `json:"operator"`
+	Members       int         `json:"members"`
+	AltitudeMinKm float64     `json:"altitudeMinKm"`
+	AltitudeMaxKm float64     `json:"altitudeMaxKm"`
+	OrbitTypes    []string    `json:"orbitTypes"`
+	Satellites    []Satellite `json:"-"`
+}
+
+// GroupConstellations splits sats into non-constellation satellites (in their original order)
+// and one ConstellationGroup per operator (case-insensitive), ordered by operator.
+func GroupConstellations(sats []Satellite) ([]Satellite, []ConstellationGroup) {
+	var individuals []Satellite
+	byOperator := make(map[string]*ConstellationGroup)
+	for _, sat := range sats {
+		if !sat.Constellation {
+			individuals = append(individuals, sat)
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(sat.Operator))
+		g, ok := byOperator[key]
+		if !ok {
+			g = &ConstellationGroup{Operator: sat.Operator, AltitudeMinKm: sat.Altitude, AltitudeMaxKm: sat.Altitude, OrbitTypes: []string{}}
+			byOperator[key] = g
+		}
+		g.Members++
+		g.Satellites = append(g.Satellites, sat)
+		if sat.Altitude < g.AltitudeMinKm {
+			g.AltitudeMinKm = sat.Altitude
+		}
+		if sat.Altitude > g.AltitudeMaxKm {
+			g.AltitudeMaxKm = sat.Altitude
+		}
+		if sat.OrbitType != "" && !containsFold(g.OrbitTypes, sat.OrbitType) {
+			g.OrbitTypes = append(g.OrbitTypes, sat.OrbitType)
+		}
+	}
+
+	groups := make([]ConstellationGroup, 0, len(byOperator))
+	for _, g := range byOperator {
+		sort.Strings(g.OrbitTypes)
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Operator) < strings.ToLower(groups[j].Operator)
+	})
+	return individuals, groups
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// cmd/satcli/crypto_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yackko/satcom-code/internal/crypto"
+
+	"github.com/spf13/cobra"
+)
+
+var cryptoCmd = &cobra.Command{
+	Use:         "crypto",
+	Short:       "Cryptography utilities (never touch the datastore)",
+	Annotations: map[string]string{skipDatastoreAnnotation: "true"},
+}
+
+var cryptoCalibrateCmd = &cobra.Command{
+	Use:   "calibrate",
+	Short: "Benchmark Argon2id on this machine and recommend cost parameters",
+	Long: `Times the key derivation currently used to unlock datastores, then benchmarks Argon2id with
+increasing memory cost (doubling from 64 MiB up to --max-memory) and then increasing time cost,
+until one derivation takes at least --target. The datastore is not read or modified.
+
+Pass the recommended parameters to 'satcli init' as --argon2-time, --argon2-memory and
+--argon2-threads; they are recorded in the new datastore's header, so it opens with the same
+cost on any machine. Existing datastores keep the cost they were created with.
+
+Examples:
+  satcli crypto calibrate --target 500ms
+  satcli crypto calibrate --target 1s --max-memory 512 --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, _ := cmd.Flags().GetDuration("target")
+		maxMemoryMiB, _ := cmd.Flags().GetUint32("max-memory")
+		threads, _ := cmd.Flags().GetUint8("threads")
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+
+		current, err := crypto.BenchmarkDefaultKDF()
+		if err != nil {
+			return fmt.Errorf("failed to benchmark current key derivation: %w", err)
+		}
+		params, steps, err := crypto.CalibrateArgon2id(target, maxMemoryMiB*1024, threads)
+		if err != nil {
+			return err
+		}
+		reached := steps[len(steps)-1].Duration >= target
+
+		if strings.ToLower(outputFormat) == "json" {
+			output, errJson := json.MarshalIndent(struct {
+				Target      string                   `json:"target"`
+				Current     string                   `json:"currentDuration"`
+				Recommended crypto.Argon2Params      `json:"recommended"`
+				Reached     bool                     `json:"targetReached"`
+				Steps       []crypto.CalibrationStep `json:"steps"`
+			}{target.String(), current.String(), params, reached, steps}, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal calibration results to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Current key derivation: %s per unlock.\n\n", current.Round(time.Millisecond))
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tMEMORY\tTHREADS\tDURATION")
+		for _, s := range steps {
+			fmt.Fprintf(w, "%d\t%d MiB\t%d\t%s\n", s.Params.Time, s.Params.MemoryKiB/1024, s.Params.Threads, s.Duration.Round(time.Millisecond))
+		}
+		w.Flush()
+		fmt.Fprintln(cmd.OutOrStdout())
+		if !reached {
+			fmt.Fprintf(cmd.OutOrStdout(), "Target %s was not reached; these are the most expensive parameters tried.\n", target)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Recommended Argon2id parameters for ~%s: time=%d memory=%dMiB threads=%d\n", target, params.Time, params.MemoryKiB/1024, params.Threads)
+		fmt.Fprintf(cmd.OutOrStdout(), "Create a datastore with them: satcli init --argon2-time %d --argon2-memory %d --argon2-threads %d\n", params.Time, params.MemoryKiB/1024, params.Threads)
+		return nil
+	},
+}
+
+// argon2ParamsFromFlags returns the Argon2id parameters for new datastores given by --argon2-time,
+// --argon2-memory and --argon2-threads, or nil when none is set. Setting some but not all is an error.
+func argon2ParamsFromFlags(cmd *cobra.Command) (*crypto.Argon2Params, error) {
+	timeCost, _ := cmd.Flags().GetUint32("argon2-time")
+	memoryMiB, _ := cmd.Flags().GetUint32("argon2-memory")
+	threads, _ := cmd.Flags().GetUint8("argon2-threads")
+	if timeCost == 0 && memoryMiB == 0 && threads == 0 {
+		return nil, nil
+	}
+	if timeCost == 0 || memoryMiB == 0 || threads == 0 {
+		return nil, fmt.Errorf("--argon2-time, --argon2-memory and --argon2-threads must be given together")
+	}
+	if memoryMiB > math.MaxUint32/1024 {
+		return nil, fmt.Errorf("invalid value for --argon2-memory: %d MiB is too large", memoryMiB)
+	}
+	return &crypto.Argon2Params{Time: timeCost, MemoryKiB: memoryMiB * 1024, Threads: threads}, nil
+}
+
+func init() {
+	defaultThreads := runtime.NumCPU()
+	if defaultThreads > 4 {
+		defaultThreads = 4
+	}
+	cryptoCalibrateCmd.Flags().Duration("target", 500*time.Millisecond, "Target duration of one key derivation")
+	cryptoCalibrateCmd.Flags().Uint32("max-memory", 1024, "Maximum Argon2id memory cost to try, in MiB")
+	cryptoCalibrateCmd.Flags().Uint8("threads", uint8(defaultThreads), "Argon2id parallelism")
+	cryptoCalibrateCmd.Flags().StringP("output", "O", "text", "Output format: text or json")
+	cryptoCmd.AddCommand(cryptoCalibrateCmd)
+	rootCmd.AddCommand(cryptoCmd)
+}
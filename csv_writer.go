@@ -0,0 +1,77 @@
+// cmd/satcli/csv_writer.go
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/yackko/satcom-code/types"
+)
+
+// writeCSV writes sats in the CSV layout 'satcli import' reads: a header row of JSON field names in
+// struct order, then one custom.<key> column per custom attribute in use. Numbers are written with
+// the shortest representation that parses back to the same float64, so nothing is rounded; tags are
+// joined with csvListSeparator.
+func writeCSV(w io.Writer, sats []types.Satellite) error {
+	t := reflect.TypeOf(types.Satellite{})
+	var header []string
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || t.Field(i).Type.Kind() == reflect.Map {
+			continue
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+	customKeys := make(map[string]bool)
+	for _, sat := range sats {
+		for key := range sat.Custom {
+			customKeys[key] = true
+		}
+	}
+	keys := sortedKeys(customKeys)
+	for _, key := range keys {
+		header = append(header, "custom."+key)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, sat := range sats {
+		v := reflect.ValueOf(sat)
+		row := make([]string, 0, len(header))
+		for _, i := range fields {
+			row = append(row, csvCell(v.Field(i)))
+		}
+		for _, key := range keys {
+			row = append(row, sat.Custom[key])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvListSeparator joins the elements of list fields (tags) in one CSV cell.
+const csvListSeparator = ";"
+
+// csvCell formats one struct field for writeCSV.
+func csvCell(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Slice:
+		return strings.Join(v.Interface().([]string), csvListSeparator)
+	default:
+		return v.String()
+	}
+}
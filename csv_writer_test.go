@@ -0,0 +1,104 @@
+// cmd/satcli/csv_writer_test.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/yackko/satcom-code/types"
+)
+
+// roundTripSatellites has float values that a fixed precision would round, and custom values
+// that need CSV quoting.
+var roundTripSatellites = []types.Satellite{
+	{
+		Name: "ODD-FLOATS", OrbitType: "LEO", Altitude: 550.123456789012, Eccentricity: 1e-7,
+		SemiMajorAxisKm: 6928.123456789012, Inclination: 0.1 + 0.2, Longitude: -179.99999999999997,
+		PowerSystem: "Solar", Communication: "Ka-band", Size: math.SmallestNonzeroFloat64, Weight: 1e21,
+		Constellation: true, RemoteSensing: "None", LaunchDate: "2024-02-29", Operator: "Acme, Inc.",
+		MissionObjective: `Relay "bent pipe" traffic`, Status: "active", Archived: true,
+		UpdatedAt: "2024-06-01T12:30:00Z", Tags: []string{"watchlist", "batch-1"},
+		Custom: map[string]string{
+			"notes":    `comma, and "quotes"`,
+			"multi":    "first line\nsecond line",
+			"quoted":   `"all of it"`,
+			"noradId":  "58001",
+			"operator": "shadows a field name",
+		},
+	},
+	{
+		Name: "PLAIN", OrbitType: "GEO", Altitude: 35786, Inclination: 0.05, Longitude: 19.2,
+		PowerSystem: "Solar", Communication: "Ku-band", Size: 4, Weight: 3000, LaunchDate: "2010-01-01",
+		Operator: "SES", Status: "inactive",
+		Custom: map[string]string{"noradId": "36101"},
+	},
+	{
+		Name: "NO-CUSTOM", OrbitType: "MEO", Altitude: 20200, Eccentricity: 0.003, Inclination: 55,
+		PowerSystem: "Solar", Communication: "L-band", Size: 2.5, Weight: 1630.7, LaunchDate: "2018-12-23",
+		Operator: "USSF", Status: "active",
+	},
+}
+
+// importRecords decodes records as 'satcli import --strict-fields' does.
+func importRecords(t *testing.T, records []json.RawMessage) []types.Satellite {
+	t.Helper()
+	sats := make([]types.Satellite, 0, len(records))
+	for i, raw := range records {
+		sat, err := decodeImportRecord(raw, true)
+		if err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+		sats = append(sats, sat)
+	}
+	return sats
+}
+
+func TestCSVExportImportRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, roundTripSatellites); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csvToRecords(buf.Bytes())
+	if err != nil {
+		t.Fatalf("csvToRecords: %v\n%s", err, buf.Bytes())
+	}
+	got := importRecords(t, records)
+	if !reflect.DeepEqual(got, roundTripSatellites) {
+		t.Errorf("CSV round trip changed the records:\n got %+v\nwant %+v\nCSV:\n%s", got, roundTripSatellites, buf.Bytes())
+	}
+}
+
+func TestJSONExportImportRoundTrip(t *testing.T) {
+	output, err := json.MarshalIndent(roundTripSatellites, "", "  ") // As list --output json
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []json.RawMessage
+	if err := json.Unmarshal(output, &records); err != nil {
+		t.Fatal(err)
+	}
+	got := importRecords(t, records)
+	if !reflect.DeepEqual(got, roundTripSatellites) {
+		t.Errorf("JSON round trip changed the records:\n got %+v\nwant %+v", got, roundTripSatellites)
+	}
+}
+
+func TestCSVCellFloatsParseBackExactly(t *testing.T) {
+	for _, v := range []float64{550.123456789012, 1e-7, 0.1 + 0.2, 1e21, -0.5, math.MaxFloat64, math.SmallestNonzeroFloat64} {
+		cell := csvCell(reflect.ValueOf(v))
+		records, err := csvToRecords([]byte("altitude\n" + cell + "\n"))
+		if err != nil {
+			t.Fatalf("%v: %v", v, err)
+		}
+		var rec struct{ Altitude float64 }
+		if err := json.Unmarshal(records[0], &rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Altitude != v {
+			t.Errorf("%v written as %q read back as %v", v, cell, rec.Altitude)
+		}
+	}
+}
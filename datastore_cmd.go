@@ -0,0 +1,97 @@
+// cmd/satcli/datastore_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+var datastoreCmd = &cobra.Command{
+	Use:         "datastore",
+	Short:       "Inspect the datastore without unlocking it",
+	Annotations: map[string]string{skipDatastoreAnnotation: "true"},
+}
+
+var datastorePathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print where the datastore lives, whether it exists, and its permissions",
+	Long: `Prints the datastore location as resolved from --datastore, $` + config.DatastoreEnvVar + `, the config file, or the
+default (next to the satcli executable), and says which of those it came from. For a local file it
+also reports whether the file exists and its permissions, which should be 0600; for S3 it reports
+whether the object exists. No passphrase is needed and the datastore is not decrypted.
+
+Examples:
+  satcli datastore path
+  satcli datastore path --datastore ~/sats.store --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+		path, err := datastore.Path()
+		if err != nil {
+			return err
+		}
+		source := settingSources["datastore"]
+		if source == "" || source == "default" {
+			source = "default (next to the executable)"
+		}
+
+		var exists bool
+		var permissions string
+		if strings.HasPrefix(path, "s3://") {
+			b, err := datastore.OpenBackend(path)
+			if err != nil {
+				return err
+			}
+			version, err := b.Version(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to check %s: %w", path, err)
+			}
+			exists = version != ""
+		} else {
+			info, err := os.Stat(path)
+			switch {
+			case err == nil:
+				exists, permissions = true, fmt.Sprintf("%04o", info.Mode().Perm())
+			case !os.IsNotExist(err):
+				return fmt.Errorf("failed to check %s: %w", path, err)
+			}
+		}
+
+		if strings.ToLower(outputFormat) == "json" {
+			output, errJson := json.MarshalIndent(struct {
+				Path        string `json:"path"`
+				Source      string `json:"source"`
+				Exists      bool   `json:"exists"`
+				Permissions string `json:"permissions,omitempty"`
+			}{path, settingSources["datastore"], exists, permissions}, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal datastore path to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+		rows := [][]string{{"Path:", path}, {"Source:", source}, {"Exists:", yesNo(exists)}}
+		if permissions != "" {
+			if permissions != "0600" {
+				permissions += " (expected 0600; run 'chmod 600 " + path + "')"
+			}
+			rows = append(rows, []string{"Permissions:", permissions})
+		}
+		printAlignedRows(cmd.OutOrStdout(), rows)
+		return nil
+	},
+}
+
+func init() {
+	datastorePathCmd.Flags().StringP("output", "O", "text", "Output format: text or json")
+	datastoreCmd.AddCommand(datastorePathCmd)
+	rootCmd.AddCommand(datastoreCmd)
+}
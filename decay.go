@@ -0,0 +1,67 @@
+// types/decay.go
+package types
+
+import (
+	"math"
+	"time"
+)
+
+// decayLifetimes are rough orbital lifetimes of a typical small satellite by perigee altitude, at
+// moderate solar activity. Real lifetimes vary several-fold with mass, area and the solar cycle.
+var decayLifetimes = []struct {
+	altitudeKm float64
+	lifetime   time.Duration
+}{
+	{150, 24 * time.Hour},
+	{200, 7 * 24 * time.Hour},
+	{250, 30 * 24 * time.Hour},
+	{300, 120 * 24 * time.Hour},
+	{350, 365 * 24 * time.Hour},
+	{400, 2 * 365 * 24 * time.Hour},
+	{500, 10 * 365 * 24 * time.Hour},
+	{600, 25 * 365 * 24 * time.Hour},
+}
+
+// EstimatedLifetime returns a coarse orbital lifetime for an orbit with its perigee at perigeeKm,
+// interpolated log-linearly between decayLifetimes. ok is false above the table, where
+// drag is too weak to estimate a lifetime this way.
+func EstimatedLifetime(perigeeKm float64) (lifetime time.Duration, ok bool) {
+	first, last := decayLifetimes[0], decayLifetimes[len(decayLifetimes)-1]
+	switch {
+	case perigeeKm <= first.altitudeKm:
+		return first.lifetime, true
+	case perigeeKm > last.altitudeKm:
+		return 0, false
+	}
+	for i := 1; i < len(decayLifetimes); i++ {
+		lo, hi := decayLifetimes[i-1], decayLifetimes[i]
+		if perigeeKm <= hi.altitudeKm {
+			f := (perigeeKm - lo.altitudeKm) / (hi.altitudeKm - lo.altitudeKm)
+			logLifetime := math.Log(float64(lo.lifetime)) + f*(math.Log(float64(hi.lifetime))-math.Log(float64(lo.lifetime)))
+			return time.Duration(math.Exp(logLifetime)), true
+		}
+	}
+	return 0, false
+}
+
+// LikelyDecayed reports whether more than the estimated lifetime at the satellite's perigee has
+// passed since its orbital elements were last known: UpdatedAt if set, otherwise the launch date.
+// It returns the estimated reentry time, and ok false when no estimate can be made.
+func (s Satellite) LikelyDecayed(now time.Time) (decayed bool, reentry time.Time, ok bool) {
+	_, perigee, err := s.ApogeePerigeeKm()
+	if err != nil {
+		return false, time.Time{}, false
+	}
+	lifetime, ok := EstimatedLifetime(perigee)
+	if !ok {
+		return false, time.Time{}, false
+	}
+	epoch, err := time.Parse(time.RFC3339, s.UpdatedAt)
+	if err != nil {
+		if epoch, err = time.Parse("2006-01-02", s.LaunchDate); err != nil {
+			return false, time.Time{}, false
+		}
+	}
+	reentry = epoch.Add(lifetime)
+	return now.After(reentry), reentry, true
+}
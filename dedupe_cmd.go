@@ -0,0 +1,203 @@
+// cmd/satcli/dedupe_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// duplicateGroup is a set of satellites that are likely the same object.
+// Keep is the most complete record; Duplicates are the others.
+type duplicateGroup struct {
+	Keep       types.Satellite   `json:"keep"`
+	Duplicates []types.Satellite `json:"duplicates"`
+}
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Report (and optionally merge) likely duplicate satellite records",
+	Long: `Groups satellites with the same operator whose altitude and inclination are within the given
+tolerances, and reports them as likely duplicates. Constellation members are skipped unless
+--include-constellations is set, since they legitimately share orbits.
+
+With --merge, each group is collapsed into its most complete record (empty fields are filled
+from the other members). The proposed groupings are always printed first; --yes is required to apply.
+
+Examples:
+  satcli dedupe --altitude-tol 5 --inclination-tol 0.2
+  satcli dedupe --merge --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		altitudeTol, _ := cmd.Flags().GetFloat64("altitude-tol")
+		inclinationTol, _ := cmd.Flags().GetFloat64("inclination-tol")
+		includeConstellations, _ := cmd.Flags().GetBool("include-constellations")
+		merge, _ := cmd.Flags().GetBool("merge")
+		yes, _ := cmd.Flags().GetBool("yes")
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+		if altitudeTol < 0 || inclinationTol < 0 {
+			return fmt.Errorf("tolerances cannot be negative")
+		}
+
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		var candidates []types.Satellite
+		for _, sat := range satsMap {
+			if sat.Constellation && !includeConstellations {
+				continue
+			}
+			candidates = append(candidates, sat)
+		}
+		groups := findDuplicateGroups(candidates, altitudeTol, inclinationTol)
+
+		if strings.ToLower(outputFormat) == "json" {
+			if groups == nil {
+				groups = []duplicateGroup{}
+			}
+			output, errJson := json.MarshalIndent(groups, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal duplicate groups to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		} else {
+			printDuplicateGroups(cmd.OutOrStdout(), groups)
+		}
+
+		if len(groups) == 0 || !merge {
+			return nil
+		}
+		if !yes {
+			fmt.Fprintln(cmd.OutOrStdout(), "Dry run: re-run with --merge --yes to apply the merges above.")
+			return nil
+		}
+		removed := 0
+		for _, g := range groups {
+			merged := g.Keep
+			for _, dup := range g.Duplicates {
+				merged = fillEmptyFields(merged, dup)
+				if err := datastore.DeleteSatellite(dup.Name); err != nil {
+					return err
+				}
+				removed++
+			}
+			if err := datastore.AddSatellite(merged); err != nil {
+				return err
+			}
+		}
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to save merged records: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Merged %d group(s), removed %d duplicate record(s).\n", len(groups), removed)
+		return nil
+	},
+}
+
+// findDuplicateGroups clusters sats with the same operator (case-insensitive) whose altitude and
+// inclination are within tolerance of another member (single linkage). Only groups of two or more
+// are returned, ordered by the kept record's name.
+func findDuplicateGroups(sats []types.Satellite, altitudeTol, inclinationTol float64) []duplicateGroup {
+	sort.Slice(sats, func(i, j int) bool { return sats[i].Name < sats[j].Name })
+
+	parent := make([]int, len(sats))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range sats {
+		for j := i + 1; j < len(sats); j++ {
+			if !strings.EqualFold(sats[i].Operator, sats[j].Operator) {
+				continue
+			}
+			if math.Abs(sats[i].Altitude-sats[j].Altitude) <= altitudeTol &&
+				math.Abs(sats[i].Inclination-sats[j].Inclination) <= inclinationTol {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	members := make(map[int][]types.Satellite)
+	for i, sat := range sats {
+		root := find(i)
+		members[root] = append(members[root], sat)
+	}
+	var groups []duplicateGroup
+	for _, m := range members {
+		if len(m) < 2 {
+			continue
+		}
+		sort.SliceStable(m, func(i, j int) bool { return filledFieldCount(m[i]) > filledFieldCount(m[j]) })
+		groups = append(groups, duplicateGroup{Keep: m[0], Duplicates: m[1:]})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Keep.Name < groups[j].Keep.Name })
+	return groups
+}
+
+// filledFieldCount returns how many fields of sat hold a non-zero value.
+func filledFieldCount(sat types.Satellite) int {
+	v := reflect.ValueOf(sat)
+	n := 0
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).IsZero() {
+			n++
+		}
+	}
+	return n
+}
+
+// fillEmptyFields returns dst with each zero-valued field copied from src.
+func fillEmptyFields(dst, src types.Satellite) types.Satellite {
+	d := reflect.ValueOf(&dst).Elem()
+	s := reflect.ValueOf(src)
+	for i := 0; i < d.NumField(); i++ {
+		if d.Field(i).IsZero() {
+			d.Field(i).Set(s.Field(i))
+		}
+	}
+	return dst
+}
+
+func printDuplicateGroups(out io.Writer, groups []duplicateGroup) {
+	if len(groups) == 0 {
+		fmt.Fprintln(out, "No likely duplicates found.")
+		return
+	}
+	fmt.Fprintf(out, "Found %d group(s) of likely duplicates:\n", len(groups))
+	for i, g := range groups {
+		fmt.Fprintf(out, "\nGroup %d (%s, ~%.0f km, %.2f deg):\n", i+1, g.Keep.Operator, g.Keep.Altitude, g.Keep.Inclination)
+		fmt.Fprintf(out, "  keep    %s (%d fields set)\n", g.Keep.Name, filledFieldCount(g.Keep))
+		for _, dup := range g.Duplicates {
+			fmt.Fprintf(out, "  merge   %s (%d fields set, %.0f km, %.2f deg)\n", dup.Name, filledFieldCount(dup), dup.Altitude, dup.Inclination)
+		}
+	}
+}
+
+func init() {
+	dedupeCmd.Flags().Float64("altitude-tol", 10, "Maximum altitude difference in km for records to be considered duplicates")
+	dedupeCmd.Flags().Float64("inclination-tol", 0.5, "Maximum inclination difference in degrees for records to be considered duplicates")
+	dedupeCmd.Flags().Bool("include-constellations", false, "Also compare constellation members")
+	dedupeCmd.Flags().Bool("merge", false, "Merge each group into its most complete record")
+	dedupeCmd.Flags().Bool("yes", false, "Apply the proposed merges (required with --merge)")
+	dedupeCmd.Flags().StringP("output", "O", "text", "Output format: text or json")
+	rootCmd.AddCommand(dedupeCmd)
+}
@@ -0,0 +1,77 @@
+// cmd/satcli/delete_cmd.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete [name...]",
+	Short: "Delete one or more satellite records from the secure datastore",
+	Long: `Deletes each named satellite and saves the datastore once at the end.
+Names that are not found are reported and skipped, unless --strict is set, in which case nothing is deleted.
+A name may differ from the stored one in case; if it matches several records, nothing is deleted.
+
+Examples:
+  satcli delete Starlink-1007
+  satcli delete Starlink-1007 Starlink-1008 --strict`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		strict, _ := cmd.Flags().GetBool("strict")
+		cmd.SilenceUsage = true
+
+		var missing []string
+		names := make([]string, len(args)) // Stored names, or the argument if not found
+		for i, name := range args {
+			sat, ok, err := lookupSatellite(satsMap, name)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				missing = append(missing, name)
+				sat.Name = name
+			}
+			names[i] = sat.Name
+		}
+		if strict && len(missing) > 0 {
+			for _, name := range missing {
+				fmt.Fprintf(cmd.OutOrStdout(), "Not found: %s\n", name)
+			}
+			return &exitCodeError{exitNotFound, fmt.Errorf("%d of %d satellite(s) not found; nothing was deleted", len(missing), len(args))}
+		}
+
+		deleted := 0
+		for _, name := range names {
+			if err := datastore.DeleteSatellite(name); err != nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "Not found: %s\n", name)
+				continue
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted: %s\n", name)
+			deleted++
+		}
+		if deleted == 0 {
+			return &exitCodeError{exitNotFound, fmt.Errorf("no matching satellites found")}
+		}
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to save after deleting %d record(s): %w", deleted, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Records deleted: %d (datastore saved)\n", deleted)
+		return nil
+	},
+}
+
+func init() {
+	deleteCmd.Flags().Bool("strict", false, "Fail without deleting anything if any named satellite is not found")
+	rootCmd.AddCommand(deleteCmd)
+}
@@ -0,0 +1,122 @@
+// internal/datastore/diagnostics.go
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/crypto"
+
+	"golang.org/x/term"
+)
+
+// CheckResult is the outcome of one Diagnose step.
+type CheckResult struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+// Diagnose inspects the datastore environment without modifying it or unlocking the session:
+// the file exists and is readable, has 0600 permissions (local files only), a passphrase source
+// is available, and the file decrypts. Later checks are skipped once a prerequisite fails.
+func Diagnose() []CheckResult {
+	var results []CheckResult
+	add := func(name string, ok bool, detail string) bool {
+		results = append(results, CheckResult{Name: name, OK: ok, Detail: detail})
+		return ok
+	}
+
+	path, err := Path()
+	if !add("datastore path resolved", err == nil, errDetail(err, path)) {
+		return results
+	}
+
+	b, err := OpenBackend(path)
+	if !add("datastore backend opened", err == nil, errDetail(err, path)) {
+		return results
+	}
+	fileBytes, _, err := b.Read(context.Background())
+	if !add("datastore file exists", !errors.Is(err, ErrNotFound), errDetail(err, path)) {
+		return results
+	}
+	if !add("datastore file readable", err == nil, errDetail(err, fmt.Sprintf("%d bytes", len(fileBytes)))) {
+		return results
+	}
+	if _, ok := b.(*fileBackend); ok {
+		if info, err := os.Stat(path); err == nil {
+			perm := info.Mode().Perm()
+			add("datastore permissions are 0600", perm == 0600, fmt.Sprintf("%04o", perm))
+		}
+	}
+
+	envSet := os.Getenv(config.PassphraseEnvVar) != ""
+	interactive := term.IsTerminal(int(os.Stdin.Fd()))
+	source := "not configured"
+	switch {
+	case envSet:
+		source = config.PassphraseEnvVar + " environment variable"
+	case interactive:
+		source = "interactive prompt"
+	}
+	if !add("passphrase source configured", envSet || interactive, source) {
+		return results
+	}
+
+	passphrase, err := getPassphrase(false)
+	if err == nil && passphrase == "" {
+		err = fmt.Errorf("empty passphrase")
+	}
+	if !add("passphrase obtained", err == nil, errDetail(err, source)) {
+		return results
+	}
+	sats, format, _, err := decryptStore(context.Background(), fileBytes, passphrase)
+	if err != nil {
+		add("datastore decrypts", false, err.Error())
+		return results
+	}
+	kdf := format.KDF.Name()
+	if k, ok := format.KDF.(crypto.Argon2idParamsKDF); ok {
+		kdf += fmt.Sprintf(" (time=%d memory=%dMiB threads=%d)", k.Params.Time, k.Params.MemoryKiB/1024, k.Params.Threads)
+	}
+	add("datastore decrypts", true, "KDF "+kdf+", cipher "+format.Cipher.Name())
+	add("record count", true, fmt.Sprintf("%d record(s)", len(sats)))
+	return results
+}
+
+// Verify checks that the datastore decrypts with the configured passphrase source, without
+// unlocking the session or returning any records. Errors wrap ErrNotFound, crypto.ErrWrongPassphrase
+// or ErrCorrupted where they apply.
+func Verify(ctx context.Context) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	b, err := OpenBackend(path)
+	if err != nil {
+		return err
+	}
+	fileBytes, _, err := b.Read(ctx)
+	if err != nil {
+		return err
+	}
+	passphrase, err := getPassphrase(false)
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return fmt.Errorf("passphrase not provided for existing datastore '%s'", path)
+	}
+	_, _, _, err = decryptStore(ctx, fileBytes, passphrase)
+	return err
+}
+
+func errDetail(err error, okDetail string) string {
+	if err != nil {
+		return err.Error()
+	}
+	return okDetail
+}
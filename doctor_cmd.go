@@ -0,0 +1,67 @@
+// cmd/satcli/doctor_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the datastore environment without modifying anything",
+	Long: `Verifies that the datastore file exists and is readable, has 0600 permissions,
+that a passphrase source is configured, and that the file decrypts; reports the record count.
+No satellite data is printed and nothing is written. Exits non-zero if any check fails.
+
+Examples:
+  satcli doctor
+  satcli doctor --output json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{skipDatastoreAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results := datastore.Diagnose()
+		failed := 0
+		for _, r := range results {
+			if !r.OK {
+				failed++
+			}
+		}
+
+		outputFormat, _ := cmd.Flags().GetString("output")
+		switch strings.ToLower(outputFormat) {
+		case "json":
+			output, errJson := json.MarshalIndent(results, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal check results to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		default:
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			for _, r := range results {
+				status := "PASS"
+				if !r.OK {
+					status = "FAIL"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", status, r.Name, r.Detail)
+			}
+			w.Flush()
+		}
+
+		if failed > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d check(s) failed", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().StringP("output", "O", "text", "Output format: text or json")
+	rootCmd.AddCommand(doctorCmd)
+}
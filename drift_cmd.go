@@ -0,0 +1,257 @@
+// cmd/satcli/drift_cmd.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// driftCatalogs maps each --catalog name to the URL of a group's element sets in TLE format.
+var driftCatalogs = map[string]string{
+	"celestrak": "https://celestrak.org/NORAD/elements/gp.php?GROUP=%s&FORMAT=tle",
+}
+
+// driftEntry is a satellite whose stored orbit differs from the catalog's beyond the tolerances.
+type driftEntry struct {
+	Name               string  `json:"name"`
+	CatalogName        string  `json:"catalogName"`
+	StoredAltitude     float64 `json:"storedAltitude"`
+	CatalogAltitude    float64 `json:"catalogAltitude"`
+	AltitudeDiff       float64 `json:"altitudeDiff"`
+	StoredInclination  float64 `json:"storedInclination"`
+	CatalogInclination float64 `json:"catalogInclination"`
+	InclinationDiff    float64 `json:"inclinationDiff"`
+}
+
+// driftReport compares the datastore with a catalog. The diffs are catalog minus stored.
+type driftReport struct {
+	Source        string       `json:"source"`
+	Matched       int          `json:"matched"`
+	Drifted       []driftEntry `json:"drifted"`
+	OnlyInStore   []string     `json:"onlyInStore"`
+	OnlyInCatalog []string     `json:"onlyInCatalog"`
+}
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Report how the stored orbits diverge from a live catalog",
+	Long: `Fetches the element sets of a catalog group, matches them to the stored satellites by name
+(case-insensitively, as catalogs publish names in capitals), and reports the satellites whose
+altitude or inclination differs by more than --altitude-tol or --inclination-tol, plus the
+satellites present in only one of the two. Catalog altitudes are derived and rounded to the km as
+by 'satcli import-tle', so records imported or refreshed from the same data show no drift.
+Archived records are left out unless --include-archived or --archived-only is set.
+
+The table lists every satellite missing from the catalog, but only counts the catalog satellites
+missing from the datastore unless --show-catalog-only is set, since a catalog group usually holds
+far more than a curated store; the JSON report always lists both. Use --file or --stdin to compare
+against saved TLE text instead of fetching. Nothing is changed; use 'satcli refresh-tle' to adopt
+the catalog's elements.
+
+A fetch failing with a network error or a 5xx response is retried up to --max-retries times, waiting
+about 1s, 2s, 4s and so on (at most 30s) with random jitter. Fetched groups are cached under the
+user cache directory (~/.cache/satcli/catalogs on Linux), keyed by group and URL, and a group
+fetched within --cache-ttl (default 2h, as CelesTrak asks) is read from there instead. --no-cache
+bypasses the cache; --cache-ttl 0 always fetches but still refreshes it.
+
+Examples:
+  satcli drift --catalog celestrak --group active
+  satcli drift --group stations --altitude-tol 5 --inclination-tol 0.05
+  satcli drift --group starlink --no-cache --max-retries 5
+  satcli drift --file active.txt --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		catalog, _ := cmd.Flags().GetString("catalog")
+		group, _ := cmd.Flags().GetString("group")
+		altitudeTol, _ := cmd.Flags().GetFloat64("altitude-tol")
+		inclinationTol, _ := cmd.Flags().GetFloat64("inclination-tol")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		maxRetries, _ := cmd.Flags().GetInt("max-retries")
+		cacheTTL, _ := cmd.Flags().GetDuration("cache-ttl")
+		noCache, _ := cmd.Flags().GetBool("no-cache")
+		showCatalogOnly, _ := cmd.Flags().GetBool("show-catalog-only")
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+		if altitudeTol < 0 || inclinationTol < 0 {
+			return fmt.Errorf("tolerances cannot be negative")
+		}
+		if maxRetries < 0 || cacheTTL < 0 {
+			return fmt.Errorf("--max-retries and --cache-ttl cannot be negative")
+		}
+		archived, err := archivedFilterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		var in io.Reader
+		var source string
+		if cmd.Flags().Changed("file") || cmd.Flags().Changed("stdin") {
+			counted, name, _, closeInput, err := openTLEInput(cmd)
+			if err != nil {
+				return err
+			}
+			defer closeInput()
+			in, source = counted, name
+		} else {
+			pattern, ok := driftCatalogs[strings.ToLower(catalog)]
+			if !ok {
+				return fmt.Errorf("unknown catalog '%s'; supported: %s", catalog, strings.Join(sortedKeys(driftCatalogs), ", "))
+			}
+			if strings.TrimSpace(group) == "" {
+				return fmt.Errorf("--group cannot be empty")
+			}
+			fetch := catalogFetch{Group: group, URL: fmt.Sprintf(pattern, url.QueryEscape(group)), Timeout: timeout, MaxRetries: maxRetries, CacheTTL: cacheTTL}
+			if !noCache {
+				if fetch.CacheDir, err = catalogCacheDir(); err != nil {
+					logger.Debug("catalog cache disabled", "error", err)
+				}
+			}
+			body, err := fetchCatalog(cmd.Context(), fetch)
+			if err != nil {
+				return err
+			}
+			in, source = bytes.NewReader(body), fmt.Sprintf("%s group %s", strings.ToLower(catalog), group)
+		}
+
+		byName := make(map[string]types.TLE) // Keyed by upper-case name; the first element set wins
+		skipped, err := scanTLEBlocks(in, func(t types.TLE) {
+			key := strings.ToUpper(t.Name)
+			if _, seen := byName[key]; !seen {
+				byName[key] = t
+			}
+		}, func(at tleLine, reason error) {})
+		if err != nil {
+			return fmt.Errorf("failed to read TLEs from %s: %w", source, err)
+		}
+		if len(byName) == 0 {
+			return fmt.Errorf("no valid TLEs found in %s (%d block(s) skipped)", source, skipped)
+		}
+		if skipped > 0 {
+			noticef("Warning: skipped %d malformed block(s) in %s.\n", skipped, source)
+		}
+
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		var sats []types.Satellite
+		for _, sat := range satsMap {
+			if archived == nil || sat.Archived == *archived {
+				sats = append(sats, sat)
+			}
+		}
+		report := compareToCatalog(sats, byName, altitudeTol, inclinationTol)
+		report.Source = source
+
+		if strings.ToLower(outputFormat) == "json" {
+			output, errJson := json.MarshalIndent(report, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal drift report to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+		printDriftReport(cmd.OutOrStdout(), report, altitudeTol, inclinationTol, showCatalogOnly)
+		return nil
+	},
+}
+
+// compareToCatalog matches sats to the catalog element sets, keyed by upper-case name, and
+// reports the drifted and unmatched satellites, each sorted by name.
+func compareToCatalog(sats []types.Satellite, catalog map[string]types.TLE, altitudeTol, inclinationTol float64) driftReport {
+	report := driftReport{Drifted: []driftEntry{}, OnlyInStore: []string{}, OnlyInCatalog: []string{}}
+	matched := make(map[string]bool)
+	for _, sat := range sats {
+		key := strings.ToUpper(sat.Name)
+		t, ok := catalog[key]
+		if !ok {
+			report.OnlyInStore = append(report.OnlyInStore, sat.Name)
+			continue
+		}
+		matched[key] = true
+		report.Matched++
+		altitude := math.Round(t.AltitudeKm())
+		entry := driftEntry{
+			Name:               sat.Name,
+			CatalogName:        t.Name,
+			StoredAltitude:     sat.Altitude,
+			CatalogAltitude:    altitude,
+			AltitudeDiff:       altitude - sat.Altitude,
+			StoredInclination:  sat.Inclination,
+			CatalogInclination: t.InclinationDeg,
+			InclinationDiff:    math.Round((t.InclinationDeg-sat.Inclination)*1e4) / 1e4, // TLE precision
+		}
+		if math.Abs(entry.AltitudeDiff) > altitudeTol || math.Abs(entry.InclinationDiff) > inclinationTol {
+			report.Drifted = append(report.Drifted, entry)
+		}
+	}
+	for key, t := range catalog {
+		if !matched[key] {
+			report.OnlyInCatalog = append(report.OnlyInCatalog, t.Name)
+		}
+	}
+	sort.Slice(report.Drifted, func(i, j int) bool { return report.Drifted[i].Name < report.Drifted[j].Name })
+	sort.Strings(report.OnlyInStore)
+	sort.Strings(report.OnlyInCatalog)
+	return report
+}
+
+func printDriftReport(out io.Writer, report driftReport, altitudeTol, inclinationTol float64, showCatalogOnly bool) {
+	fmt.Fprintf(out, "Compared with %s: %d matched, %d drifted beyond %.0f km or %.2f deg, %d only in the datastore, %d only in the catalog.\n",
+		report.Source, report.Matched, len(report.Drifted), altitudeTol, inclinationTol, len(report.OnlyInStore), len(report.OnlyInCatalog))
+	if len(report.Drifted) > 0 {
+		fmt.Fprintln(out, "\nDrifted:")
+		rows := make([][]string, len(report.Drifted))
+		for i, d := range report.Drifted {
+			rows[i] = []string{d.Name,
+				fmt.Sprintf("%.0f", d.StoredAltitude), fmt.Sprintf("%.0f", d.CatalogAltitude), fmt.Sprintf("%+.0f", d.AltitudeDiff),
+				fmt.Sprintf("%.2f", d.StoredInclination), fmt.Sprintf("%.2f", d.CatalogInclination), fmt.Sprintf("%+.2f", d.InclinationDiff)}
+		}
+		printTableRows(out, []tableColumn{{Header: "NAME"}, {Header: "STORED KM"}, {Header: "CATALOG KM"}, {Header: "DIFF KM"},
+			{Header: "STORED DEG"}, {Header: "CATALOG DEG"}, {Header: "DIFF DEG"}}, rows)
+	}
+	if len(report.OnlyInStore) > 0 {
+		fmt.Fprintln(out, "\nOnly in the datastore:")
+		for _, name := range report.OnlyInStore {
+			fmt.Fprintf(out, "  %s\n", name)
+		}
+	}
+	if showCatalogOnly && len(report.OnlyInCatalog) > 0 {
+		fmt.Fprintln(out, "\nOnly in the catalog:")
+		for _, name := range report.OnlyInCatalog {
+			fmt.Fprintf(out, "  %s\n", name)
+		}
+	}
+}
+
+func init() {
+	driftCmd.Flags().String("catalog", "celestrak", "Catalog to compare with: celestrak")
+	driftCmd.Flags().String("group", "active", "Catalog group to fetch, e.g. active, stations or starlink")
+	driftCmd.Flags().String("file", "", "Compare with a TLE text file (- for standard input) instead of fetching")
+	driftCmd.Flags().Bool("stdin", false, "Compare with TLE text from stdin instead of fetching")
+	driftCmd.Flags().Float64("altitude-tol", 10, "Largest altitude difference in km not reported as drift")
+	driftCmd.Flags().Float64("inclination-tol", 0.1, "Largest inclination difference in degrees not reported as drift")
+	driftCmd.Flags().Duration("timeout", 30*time.Second, "Time limit for each attempt at fetching the catalog")
+	driftCmd.Flags().Int("max-retries", 3, "Times to retry a fetch failing with a network error or 5xx response")
+	driftCmd.Flags().Duration("cache-ttl", defaultCatalogCacheTTL, "Reuse a group fetched within this time instead of fetching it again (0 to always fetch)")
+	driftCmd.Flags().Bool("no-cache", false, "Neither read nor write the catalog cache")
+	driftCmd.Flags().Bool("show-catalog-only", false, "List the catalog satellites missing from the datastore, not just their count")
+	driftCmd.Flags().StringP("output", "O", "table", "Output format: table or json")
+	addArchivedFlags(driftCmd)
+	rootCmd.AddCommand(driftCmd)
+}
@@ -0,0 +1,97 @@
+// cmd/satcli/dry_run.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/yackko/satcom-code/types"
+)
+
+// printDryRunChanges prints what a save skipped by --dry-run would have changed: "+ name" for an
+// added record, "- name" for a removed one, and "~ name: " with each changed field for the rest,
+// in the "field old -> new" form of update-many.
+func printDryRunChanges(out io.Writer, saved, pending map[string]types.Satellite) {
+	var lines []string
+	for _, name := range sortedKeys(saved) {
+		if _, kept := pending[name]; !kept {
+			lines = append(lines, "- "+name)
+		}
+	}
+	for _, name := range sortedKeys(pending) {
+		before, existed := saved[name]
+		if !existed {
+			lines = append(lines, "+ "+name)
+			continue
+		}
+		if changes := recordChanges(before, pending[name]); len(changes) > 0 {
+			lines = append(lines, fmt.Sprintf("~ %s: %s", name, strings.Join(changes, ", ")))
+		}
+	}
+	if len(lines) == 0 {
+		fmt.Fprintln(out, "Dry run: no changes to save.")
+		return
+	}
+	fmt.Fprintf(out, "Dry run: %d record(s) would change:\n", len(lines))
+	for _, line := range lines {
+		fmt.Fprintf(out, "  %s\n", line)
+	}
+}
+
+// recordChanges describes each field that differs between two versions of a record, in struct
+// order, with custom attributes compared key by key.
+func recordChanges(before, after types.Satellite) []string {
+	old, updated := satelliteFieldValues(before), satelliteFieldValues(after)
+	var changes []string
+	t := reflect.TypeOf(types.Satellite{})
+	for i := 0; i < t.NumField(); i++ {
+		field := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if field == "custom" {
+			keys := make(map[string]string)
+			for key, value := range before.Custom {
+				keys[key] = value
+			}
+			for key := range after.Custom {
+				keys[key] = before.Custom[key]
+			}
+			for _, key := range sortedKeys(keys) {
+				was, had := before.Custom[key]
+				now, has := after.Custom[key]
+				switch {
+				case !has:
+					changes = append(changes, fmt.Sprintf("custom.%s %s -> (removed)", key, describeSetValue(was, true)))
+				case !had || was != now:
+					changes = append(changes, fmt.Sprintf("custom.%s %s -> %s", key, describeSetValue(was, had), describeSetValue(now, true)))
+				}
+			}
+			continue
+		}
+		was, had := old[field]
+		now, has := updated[field]
+		if had == has && reflect.DeepEqual(was, now) {
+			continue
+		}
+		if !has {
+			changes = append(changes, fmt.Sprintf("%s %s -> (unset)", field, describeSetValue(was, true)))
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s %s -> %s", field, describeSetValue(was, had), describeSetValue(now, true)))
+	}
+	return changes
+}
+
+// satelliteFieldValues returns sat keyed by JSON field name, as it is saved; omitted empty fields are absent.
+func satelliteFieldValues(sat types.Satellite) map[string]interface{} {
+	var values map[string]interface{}
+	raw, err := json.Marshal(sat)
+	if err == nil {
+		err = json.Unmarshal(raw, &values)
+	}
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	return values
+}
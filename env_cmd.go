@@ -0,0 +1,121 @@
+// cmd/satcli/env_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// envVar is an environment variable satcli reads. The value of a secret one is never printed.
+type envVar struct {
+	Name    string
+	Purpose string
+	Secret  bool
+}
+
+// recognizedEnvVars returns every environment variable satcli reads, in display order. The
+// setting defaults come from settingEnvVars, so a new setting is listed without changes here.
+func recognizedEnvVars() []envVar {
+	vars := []envVar{
+		{config.PassphraseEnvVar, "Passphrase that unlocks (or creates) the datastore instead of prompting", true},
+		{newPassphraseEnvVar, "New passphrase for 'satcli passphrase change' instead of prompting", true},
+		{config.ConfigPathEnvVar, "Path of the config file (see 'satcli config path')", false},
+	}
+	for _, name := range shownSettings {
+		vars = append(vars, envVar{settingEnvVars[name], "Default for --" + name + ", over the config file (see 'satcli config show')", false})
+	}
+	return append(vars,
+		envVar{"NO_COLOR", "Disables color when --color is auto", false},
+		envVar{"AWS_ACCESS_KEY_ID", "S3 datastores: access key, with AWS_SECRET_ACCESS_KEY", true},
+		envVar{"AWS_SECRET_ACCESS_KEY", "S3 datastores: secret key", true},
+		envVar{"AWS_SESSION_TOKEN", "S3 datastores: session token for temporary credentials", true},
+		envVar{"AWS_PROFILE", "S3 datastores: profile of the shared config and credentials files, e.g. for SSO or credential_process", false},
+		envVar{"AWS_CONFIG_FILE", "S3 datastores: shared config file (default ~/.aws/config)", false},
+		envVar{"AWS_SHARED_CREDENTIALS_FILE", "S3 datastores: shared credentials file (default ~/.aws/credentials)", false},
+		envVar{"AWS_WEB_IDENTITY_TOKEN_FILE", "S3 datastores: web identity token for assuming AWS_ROLE_ARN", false},
+		envVar{"AWS_ROLE_ARN", "S3 datastores: role assumed with the web identity token", false},
+		envVar{"AWS_REGION", "S3 datastores: region (default us-east-1)", false},
+		envVar{"AWS_DEFAULT_REGION", "S3 datastores: region when AWS_REGION is not set", false},
+		envVar{"AWS_ENDPOINT_URL_S3", "S3 datastores: endpoint of an S3-compatible service", false},
+		envVar{"AWS_ENDPOINT_URL", "S3 datastores: endpoint when AWS_ENDPOINT_URL_S3 is not set", false},
+	)
+}
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "List the environment variables satcli recognizes and whether each is set",
+	Long: `Lists every environment variable satcli reads, what it is for, whether it is set, and its value.
+The values of secrets (passphrases and AWS keys) are never printed, only whether they are set. An
+empty variable counts as unset, as it does everywhere else in satcli. Nothing is unlocked or written.
+
+Examples:
+  satcli env
+  satcli env --set-only
+  satcli env --output json`,
+	Args: cobra.NoArgs,
+	// Overrides rootCmd's hook: env must work even when a variable or the config file is invalid.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setOnly, _ := cmd.Flags().GetBool("set-only")
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+
+		type shownEnvVar struct {
+			Name    string `json:"name"`
+			Set     bool   `json:"set"`
+			Value   string `json:"value,omitempty"`
+			Secret  bool   `json:"secret"`
+			Purpose string `json:"purpose"`
+		}
+		shown := []shownEnvVar{}
+		for _, v := range recognizedEnvVars() {
+			value := os.Getenv(v.Name)
+			if setOnly && value == "" {
+				continue
+			}
+			entry := shownEnvVar{Name: v.Name, Set: value != "", Secret: v.Secret, Purpose: v.Purpose}
+			if !v.Secret {
+				entry.Value = value
+			}
+			shown = append(shown, entry)
+		}
+
+		if strings.ToLower(outputFormat) == "json" {
+			output, errJson := json.MarshalIndent(shown, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal environment variables to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+		if len(shown) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "None of the recognized environment variables are set.")
+			return nil
+		}
+		rows := make([][]string, len(shown))
+		for i, v := range shown {
+			set, value := "no", v.Value
+			if v.Set {
+				set = "yes"
+				if v.Secret {
+					value = "(hidden)"
+				}
+			}
+			rows[i] = []string{v.Name, set, value, v.Purpose}
+		}
+		printTableRows(cmd.OutOrStdout(), []tableColumn{{Header: "VARIABLE"}, {Header: "SET"}, {Header: "VALUE"}, {Header: "PURPOSE"}}, rows)
+		return nil
+	},
+}
+
+func init() {
+	envCmd.Flags().Bool("set-only", false, "Only list variables that are set")
+	envCmd.Flags().StringP("output", "O", "text", "Output format: text or json")
+	rootCmd.AddCommand(envCmd)
+}
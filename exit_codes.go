@@ -0,0 +1,109 @@
+// cmd/satcli/exit_codes.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/crypto"
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes, documented in the root help; any other failure exits 1.
+const (
+	exitUsage     = 2 // Unknown command or flag, invalid flag value, wrong arguments, or a missing required flag
+	exitLocked    = 3 // Datastore locked: passphrase missing or wrong
+	exitNotFound  = 4 // Datastore file or a named record not found
+	exitCorrupted = 5 // Datastore file is corrupted
+	exitIO        = 6 // Reading or writing a file failed
+)
+
+// exitCodesHelp documents the exit codes for the root command's help.
+const exitCodesHelp = `Exit codes:
+  0  success
+  1  any other error
+  2  usage error: unknown command or flag, invalid flag value, wrong arguments, or a missing required flag
+  3  datastore locked: passphrase not provided or wrong
+  4  datastore file or a named record not found
+  5  datastore file is corrupted
+  6  I/O error reading or writing a file, or opening the datastore`
+
+// exitCodeError makes main exit with code instead of 1 after reporting err.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// errDatastoreLocked is returned by commands that need the datastore when it could not be unlocked.
+func errDatastoreLocked() error {
+	return &exitCodeError{exitLocked, fmt.Errorf("datastore not accessible. Passphrase not provided or was incorrect. Set %s or enter correct passphrase at prompt.", config.PassphraseEnvVar)}
+}
+
+// usageError marks err as a usage error, exiting with exitUsage.
+func usageError(err error) error {
+	return &exitCodeError{exitUsage, err}
+}
+
+// markUsageErrors makes cobra's flag and argument errors for cmd and its descendants usage errors:
+// flag parsing through the flag error func, and the Args, required-flag and flag-group checks
+// through a wrapper of each command's Args. An unknown command is detected by isUnknownCommand.
+func markUsageErrors(cmd *cobra.Command) {
+	if !cmd.HasParent() {
+		cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error { return usageError(err) })
+	}
+	// The root's nil Args makes cobra report unknown commands, so it is left alone.
+	if args := cmd.Args; args != nil || cmd.HasParent() {
+		if args == nil {
+			args = cobra.ArbitraryArgs // What cobra uses for a subcommand without Args
+		}
+		cmd.Args = func(c *cobra.Command, a []string) error {
+			if err := args(c, a); err != nil {
+				return usageError(err)
+			}
+			// Checked here, before the root pre-run prompts for a passphrase, rather than after it by cobra.
+			if err := c.ValidateRequiredFlags(); err != nil {
+				return usageError(err)
+			}
+			if err := c.ValidateFlagGroups(); err != nil {
+				return usageError(err)
+			}
+			return nil
+		}
+	}
+	for _, child := range cmd.Commands() {
+		markUsageErrors(child)
+	}
+}
+
+// isUnknownCommand reports whether args name no command of root, which cobra reports before
+// running anything.
+func isUnknownCommand(root *cobra.Command, args []string) bool {
+	_, _, err := root.Find(args)
+	return err != nil
+}
+
+// exitCodeFor maps err, as returned by rootCmd.Execute, to the process exit code.
+func exitCodeFor(err error) int {
+	var exitErr *exitCodeError
+	var pathErr *fs.PathError
+	switch {
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	case errors.Is(err, crypto.ErrWrongPassphrase):
+		return exitLocked
+	case errors.Is(err, datastore.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, datastore.ErrCorrupted):
+		return exitCorrupted
+	case errors.As(err, &pathErr):
+		return exitIO
+	}
+	return 1
+}
@@ -0,0 +1,79 @@
+// cmd/satcli/exit_codes_test.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newExitCodeTestRoot returns a command tree like rootCmd's: a pre-run that fails on request, a
+// command with positional arguments and a required flag, and one whose RunE returns runErr.
+func newExitCodeTestRoot(preRunErr, runErr error) *cobra.Command {
+	root := &cobra.Command{
+		Use:               "satcli",
+		SilenceErrors:     true,
+		SilenceUsage:      true,
+		PersistentPreRunE: func(*cobra.Command, []string) error { return preRunErr },
+	}
+	root.PersistentFlags().Int("passphrase-attempts", 3, "")
+	get := &cobra.Command{Use: "get", Args: cobra.ExactArgs(1), RunE: func(*cobra.Command, []string) error { return nil }}
+	imp := &cobra.Command{Use: "import", RunE: func(*cobra.Command, []string) error { return nil }}
+	imp.Flags().String("file", "", "")
+	_ = imp.MarkFlagRequired("file")
+	imp.Flags().Bool("stdin", false, "")
+	imp.MarkFlagsMutuallyExclusive("file", "stdin")
+	run := &cobra.Command{Use: "run", RunE: func(*cobra.Command, []string) error { return runErr }}
+	root.AddCommand(get, imp, run)
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	markUsageErrors(root)
+	return root
+}
+
+func TestExitCodes(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "satellites.dat", Err: fs.ErrPermission}
+	tests := []struct {
+		name      string
+		args      []string
+		preRunErr error
+		runErr    error
+		want      int
+	}{
+		{"success", []string{"get", "ISS"}, nil, nil, 0},
+		{"unknown command", []string{"bogus"}, nil, nil, exitUsage},
+		{"unknown flag", []string{"get", "ISS", "--bogus"}, nil, nil, exitUsage},
+		{"invalid flag value", []string{"--passphrase-attempts", "many", "get", "ISS"}, nil, nil, exitUsage},
+		{"too few arguments", []string{"get"}, nil, nil, exitUsage},
+		{"too many arguments", []string{"get", "a", "b"}, nil, nil, exitUsage},
+		{"missing required flag", []string{"import"}, nil, nil, exitUsage},
+		{"exclusive flags", []string{"import", "--file", "x", "--stdin"}, nil, nil, exitUsage},
+		{"arguments checked before the pre-run", []string{"get"}, errors.New("prompt failed"), nil, exitUsage},
+		{"pre-run error", []string{"get", "ISS"}, errors.New("invalid operator-aliases"), nil, 1},
+		{"pre-run I/O error", []string{"get", "ISS"}, fmt.Errorf("failed to read config file: %w", pathErr), nil, exitIO},
+		{"pre-run usage error", []string{"get", "ISS"}, usageError(errors.New("invalid value for --kdf")), nil, exitUsage},
+		{"command error", []string{"run"}, nil, errors.New("no such satellite"), 1},
+		{"command I/O error", []string{"run"}, nil, pathErr, exitIO},
+		{"command locked error", []string{"run"}, nil, errDatastoreLocked(), exitLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newExitCodeTestRoot(tt.preRunErr, tt.runErr)
+			root.SetArgs(tt.args)
+			got := 0
+			if err := root.Execute(); err != nil {
+				got = exitCodeFor(err)
+				if isUnknownCommand(root, tt.args) {
+					got = exitUsage
+				}
+			}
+			if got != tt.want {
+				t.Errorf("exit code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+// cmd/satcli/export_cmd.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// exportFormats lists the accepted --format values.
+var exportFormats = []string{"xlsx", "html", "csv", "protobuf", "ndjson"}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export satellite records to a file for use outside satcli",
+	Long: `Writes every satellite record, ordered by name, to --file in the chosen format.
+The xlsx format produces a single worksheet with a bold, frozen header row and auto-sized columns;
+numeric fields are stored as numbers. The html format produces a self-contained page (no external
+assets) headed by --title and the number of satellites per orbit type, with a table that sorts by
+the clicked column. Select fields with --columns or --wide, as for table output.
+The csv format is for moving data rather than reading it: every field, with custom attributes as
+custom.<key> columns and numbers at full precision, in the layout 'satcli import' reads back
+(--columns and --wide do not apply).
+The protobuf format is a compact binary form of the same data for machine interchange, lossless and
+much smaller than JSON; 'satcli schema --format proto' prints its message definitions, and
+'satcli import' reads it back.
+More than --max-results records (default 100000) are refused unless --force is given; --limit
+exports only the first records by name. --redact replaces the values of the named text fields with
+REDACTED, keeping the columns, so data can be shared without organizational detail.
+The ndjson format writes one JSON record per line, as 'satcli list --output ndjson' does.
+
+--since exports only records whose updatedAt (set by 'satcli refresh-tle') is at or after the given
+RFC3339 time or YYYY-MM-DD date; records never refreshed are left out. --append, for ndjson only,
+adds to the end of an existing --file instead of replacing it, skipping records whose name it
+already contains, so a pipeline can collect new records run after run. A file that is not NDJSON
+satellite records is refused rather than mixed with, and if writing fails the file is restored to
+its previous length. --file - writes the export to standard output, with the summary line on stderr
+so a pipeline gets only the data; binary formats (xlsx, protobuf) are refused on a terminal, and
+--append needs a real file.
+
+Examples:
+  satcli export --format xlsx --file satellites.xlsx
+  satcli export --format xlsx --file report.xlsx --columns name,operator,status,altitude
+  satcli export --format html --file fleet.html --title "Fleet status, Q3"
+  satcli export --format csv --file backup.csv
+  satcli export --format csv --file public.csv --redact operator,missionObjective
+  satcli export --format protobuf --file fleet.pb
+  satcli export --format ndjson --file log.ndjson --append --since 2024-06-01
+  satcli export --format csv --file - | gzip > satellites.csv.gz`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		format, _ := cmd.Flags().GetString("format")
+		path, _ := cmd.Flags().GetString("file")
+		title, _ := cmd.Flags().GetString("title")
+		appendTo, _ := cmd.Flags().GetBool("append")
+		since, _ := cmd.Flags().GetString("since")
+		cmd.SilenceUsage = true
+		format = strings.ToLower(format)
+		if !containsString(exportFormats, format) {
+			return fmt.Errorf("invalid value for --format: '%s'. Use one of: %s", format, strings.Join(exportFormats, ", "))
+		}
+		if path == "" {
+			return fmt.Errorf("--file is required")
+		}
+		if appendTo && format != "ndjson" {
+			return fmt.Errorf("--append requires --format ndjson; %s files cannot be appended to", format)
+		}
+		toStdout := path == stdioPath
+		if toStdout && appendTo {
+			return fmt.Errorf("--append needs a file to append to, not --file -")
+		}
+		if out, ok := cmd.OutOrStdout().(*os.File); toStdout && (format == "xlsx" || format == "protobuf") && ok && term.IsTerminal(int(out.Fd())) {
+			return fmt.Errorf("refusing to write binary %s data to a terminal; redirect standard output or give a --file path", format)
+		}
+		var sinceTime time.Time
+		if since != "" {
+			var err error
+			if sinceTime, err = parseSince(since); err != nil {
+				return err
+			}
+		}
+		columns, err := tableColumnsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		var present map[string]bool
+		var presentSize int64
+		if appendTo {
+			if present, presentSize, err = readNDJSONNames(path); err != nil {
+				return err
+			}
+		}
+		sats := make([]types.Satellite, 0, len(satsMap))
+		skipped := 0
+		for _, sat := range satsMap {
+			if since != "" {
+				updated, err := time.Parse(time.RFC3339, sat.UpdatedAt)
+				if err != nil || updated.Before(sinceTime) {
+					continue
+				}
+			}
+			if present[sat.Name] {
+				skipped++
+				continue
+			}
+			sats = append(sats, sat)
+		}
+		sort.Slice(sats, func(i, j int) bool { return sats[i].Name < sats[j].Name })
+		if sats, err = limitResults(cmd, sats, false, "use --limit, 'satcli list --output ndjson' to stream the records instead, or --force"); err != nil {
+			return err
+		}
+
+		if sats, err = redactFromFlags(cmd, sats); err != nil {
+			return err
+		}
+
+		if appendTo {
+			return appendNDJSON(cmd, path, presentSize, sats, skipped)
+		}
+		f, err := createOutputFile(path, cmd.OutOrStdout())
+		if err != nil {
+			return fmt.Errorf("cannot write export file '%s': %w", path, err)
+		}
+		rows := satelliteRows(sats, columns, false)
+		switch format {
+		case "ndjson":
+			err = writeNDJSON(f, sats)
+		case "csv":
+			err = writeCSV(f, sats)
+		case "protobuf":
+			err = writeProtobuf(f, sats)
+		case "html":
+			err = writeHTML(f, title, columns, rows, sats)
+		default:
+			err = writeXLSX(f, columns, rows)
+		}
+		if toStdout {
+			if err != nil {
+				return fmt.Errorf("failed to write export to stdout: %w", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d record(s) to stdout.\n", len(sats))
+			return nil
+		}
+		if err != nil {
+			f.Close()
+			os.Remove(path)
+			return fmt.Errorf("failed to write export file '%s': %w", path, err)
+		}
+		if err := f.Close(); err != nil {
+			os.Remove(path)
+			return fmt.Errorf("failed to write export file '%s': %w", path, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Exported %d record(s) to %s.\n", len(sats), path)
+		return nil
+	},
+}
+
+// parseSince parses --since as an RFC3339 time or a YYYY-MM-DD date (midnight UTC).
+func parseSince(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(config.DateFormat, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid value for --since: '%s'. Use an RFC3339 time or YYYY-MM-DD", s)
+}
+
+// writeNDJSON writes sats as one JSON record per line.
+func writeNDJSON(w io.Writer, sats []types.Satellite) error {
+	enc := json.NewEncoder(w)
+	for _, sat := range sats {
+		if err := enc.Encode(sat); err != nil {
+			return fmt.Errorf("satellite '%s': %w", sat.Name, err)
+		}
+	}
+	return nil
+}
+
+// readNDJSONNames returns the names of the records in the NDJSON export at path and its size, for
+// --append. A missing file has no records. Any line that is not a JSON satellite record is an error,
+// so a CSV, xlsx or JSON array export is never appended to.
+func readNDJSONNames(path string) (map[string]bool, int64, error) {
+	names := make(map[string]bool)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return names, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot read export file '%s': %w", path, err)
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var size int64
+	for lineNo := 1; ; lineNo++ {
+		line, err := r.ReadBytes('\n')
+		size += int64(len(line))
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var record struct {
+				Name *string `json:"name"`
+			}
+			if errJson := json.Unmarshal(trimmed, &record); errJson != nil || record.Name == nil {
+				return nil, 0, fmt.Errorf("cannot append to '%s': line %d is not an NDJSON satellite record; use a new --file", path, lineNo)
+			}
+			names[*record.Name] = true
+		}
+		if err == io.EOF {
+			return names, size, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("cannot read export file '%s': %w", path, err)
+		}
+	}
+}
+
+// appendNDJSON appends sats to the NDJSON export at path, which is size bytes long, and truncates it
+// back to that length if writing fails.
+func appendNDJSON(cmd *cobra.Command, path string, size int64, sats []types.Satellite, skipped int) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("cannot write export file '%s': %w", path, err)
+	}
+	w := bufio.NewWriter(f)
+	if size > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, size-1); err == nil && last[0] != '\n' {
+			w.WriteByte('\n') // Start on a new line after a file without a trailing newline
+		}
+	}
+	err = writeNDJSON(w, sats)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if err != nil {
+		f.Truncate(size)
+		f.Close()
+		return fmt.Errorf("failed to append to export file '%s': %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to append to export file '%s': %w", path, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Appended %d record(s) to %s (%d already present).\n", len(sats), path, skipped)
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	exportCmd.Flags().String("format", "xlsx", "Export format: "+strings.Join(exportFormats, ", "))
+	exportCmd.Flags().String("file", "", "Path of the file to write, or - for standard output (required)")
+	exportCmd.Flags().String("title", defaultHTMLTitle, "Heading and page title of html exports")
+	exportCmd.Flags().Bool("append", false, "Append records not already in --file instead of replacing it (ndjson only)")
+	exportCmd.Flags().String("since", "", "Only records updated at or after this RFC3339 time or YYYY-MM-DD date")
+	addTableColumnFlags(exportCmd)
+	addResultCapFlags(exportCmd)
+	addRedactFlag(exportCmd)
+	rootCmd.AddCommand(exportCmd)
+}
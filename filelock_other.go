@@ -0,0 +1,11 @@
+// internal/datastore/filelock_other.go
+
+//go:build !unix
+
+package datastore
+
+// lockFile is a no-op where flock is unavailable: concurrent saves from other processes are then
+// caught only by Write's version check.
+func lockFile(path string, exclusive bool) (func(), error) {
+	return func() {}, nil
+}
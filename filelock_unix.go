@@ -0,0 +1,45 @@
+// internal/datastore/filelock_unix.go
+
+//go:build unix
+
+package datastore
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// lockFile takes an advisory flock on the sidecar lock file for path (see lockPath): shared for
+// readers, exclusive for a writer. It blocks until the lock is granted; the returned function
+// releases it. A reader that cannot open the lock file, e.g. on a read-only share, reads unlocked.
+func lockFile(path string, exclusive bool) (func(), error) {
+	how, mode := syscall.LOCK_SH, "shared"
+	if exclusive {
+		how, mode = syscall.LOCK_EX, "exclusive"
+	}
+	f, err := os.OpenFile(lockPath(path), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil && !exclusive {
+		f, err = os.Open(lockPath(path))
+		if err != nil {
+			logger.Debug("datastore file lock unavailable, reading unlocked", "path", lockPath(path), "err", err)
+			return func() {}, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	for {
+		err = syscall.Flock(int(f.Fd()), how)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, &os.PathError{Op: "flock", Path: lockPath(path), Err: err}
+	}
+	logger.Debug("datastore file lock acquired", "mode", mode, "wait", time.Since(start))
+	return func() { f.Close() }, nil
+}
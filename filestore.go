@@ -2,15 +2,26 @@
 package datastore
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"io"
 	"io/ioutil"
+	"log/slog"
+	"maps"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
 	"sync"
+	"syscall"
+	"time"
 	// Adjust import paths based on your go.mod module name
 	"github.com/yackko/satcom-code/internal/config"
 	"github.com/yackko/satcom-code/internal/crypto" // Ensure this path is correct
@@ -21,16 +32,179 @@ import (
 
 var (
 	satellitesData     = make(map[string]types.Satellite) // Renamed to avoid conflict if types.Satellite was just Satellite
-	dataFileLock       sync.Mutex
+	dataFileLock       sync.RWMutex
 	dataPath           string
 	passphraseProvided bool   // Indicates if a valid passphrase was used to unlock/init
 	sessionKey         []byte // The key derived from the passphrase for the current session
+	sessionKDF         crypto.KeyDeriver = crypto.DefaultKDF // KDF of the loaded store, or the one chosen for a new store
+	sessionCipher      crypto.Cipher     = crypto.DefaultCipher // Cipher of the loaded store, or the one chosen for a new store
+	tempDir            string // Directory for Save's temporary file; empty means the datastore's directory
+	sessionPassphrase  string // Passphrase that unlocked the store; lets Reload re-derive the key after external saves
+	noticeOut          io.Writer = os.Stderr // Destination for non-error notices; see SetNoticeWriter
+	passphraseAttempts = 3 // Prompted passphrase tries for an existing store; see SetPassphraseAttempts
+	logger             = slog.New(slog.DiscardHandler) // Debug/diagnostic events; see SetLogger
+	store              Backend // Where the encrypted bytes are kept; opened from dataPath unless SetBackend was called
+	storeVersion       string  // Backend version of the store as last loaded or saved; empty if there was none
+	forceSave          bool   // Save even if the file changed on disk since it was loaded; see SetForceSave
+	dryRun             bool   // Saves write nothing; see SetDryRun
+	savedData          map[string]types.Satellite // Records as last loaded or saved, kept only for dry runs
 )
 
+// Hooks for frontends that have no terminal, such as GUIs. Set them before Init.
+var (
+	// OnPassphraseNeeded, when set, supplies the passphrase instead of the terminal prompt; the
+	// environment variable still takes precedence. It is called again after a wrong passphrase.
+	// New datastores are not confirmed through it, so a frontend should confirm on its own.
+	OnPassphraseNeeded func() (string, error)
+	// OnNotice, when set, receives each notice and warning (without the trailing newline) instead
+	// of the SetNoticeWriter destination.
+	OnNotice func(string)
+	// OnConfirm, when set, asks a yes/no question, such as whether to recover the datastore from an
+	// interrupted save. Without it, or on an error, the answer is no.
+	OnConfirm func(question string) (bool, error)
+	// OnDryRunSave, when set, receives the records as last loaded or saved and as they would be
+	// written, each time SetDryRun makes a save write nothing. Neither map may be modified.
+	OnDryRunSave func(saved, pending map[string]types.Satellite)
+)
+
+var (
+	// ErrNotFound is returned (wrapped) by Verify when the datastore file does not exist.
+	ErrNotFound = errors.New("datastore file not found")
+	// ErrCorrupted is returned (wrapped) when the datastore file cannot be parsed, before or after decryption,
+	// including ciphertext too short to decrypt (crypto.ErrCorrupted). Other tampered or truncated
+	// ciphertext is reported as crypto.ErrWrongPassphrase, since an AEAD cannot tell the two apart.
+	ErrCorrupted = errors.New("datastore is corrupted")
+	// ErrModifiedExternally is returned (wrapped) by Save when another process changed the file after it was loaded.
+	ErrModifiedExternally = errors.New("datastore modified externally, reload required")
+)
+
+// SetNoticeWriter redirects the package's "Notice:" and "Warning:" messages, e.g. to io.Discard
+// for --quiet. Errors are returned to the caller and are not affected; passphrase prompts still use stderr.
+func SetNoticeWriter(w io.Writer) {
+	noticeOut = w
+}
+
+// SetForceSave makes Save overwrite the datastore file even if another process changed it since it
+// was loaded, discarding that process's changes.
+func SetForceSave(force bool) {
+	forceSave = force
+}
+
+// SetDryRun makes Save, Create and ChangePassphrase write nothing, reporting the records they would
+// have written to OnDryRunSave instead. Call it before Init; stale temporary files are then left alone too.
+func SetDryRun(on bool) {
+	dryRun = on
+}
+
+// SetLogger sets the logger for load, save and lock events. Nothing is logged by default.
+func SetLogger(l *slog.Logger) {
+	logger = l
+}
+
+// lockDatastore acquires dataFileLock, logging how long op waited for it.
+func lockDatastore(op string) {
+	start := time.Now()
+	dataFileLock.Lock()
+	logger.Debug("datastore lock acquired", "op", op, "wait", time.Since(start))
+}
+
+// rlockDatastore acquires dataFileLock for reading, so concurrent readers don't wait on each other.
+func rlockDatastore(op string) {
+	start := time.Now()
+	dataFileLock.RLock()
+	logger.Debug("datastore read lock acquired", "op", op, "wait", time.Since(start))
+}
+
+// noticef writes a non-error message to OnNotice, or else to the notice writer.
+func noticef(format string, args ...interface{}) {
+	if OnNotice != nil {
+		OnNotice(strings.TrimRight(fmt.Sprintf(format, args...), "\n"))
+		return
+	}
+	fmt.Fprintf(noticeOut, format, args...)
+}
+
+// promptf writes a message that accompanies passphrase entry: to stderr next to the terminal
+// prompt, or as a notice when OnPassphraseNeeded supplies the passphrase.
+func promptf(format string, args ...interface{}) {
+	if OnPassphraseNeeded != nil {
+		noticef(format, args...)
+		return
+	}
+	fmt.Fprintf(os.Stderr, format, args...)
+}
+
+// SetNewStoreKDF selects the key derivation function used when a new datastore is created.
+// Existing datastores keep the KDF recorded in their file header.
+func SetNewStoreKDF(name string) error {
+	kdf, err := crypto.KDFByName(name)
+	if err != nil {
+		return err
+	}
+	sessionKDF = kdf
+	return nil
+}
+
+// SetNewStoreArgon2Params sets the Argon2id cost parameters of a new datastore, which requires the
+// argon2id KDF; nil keeps the built-in parameters. They are recorded in the file header, and
+// existing datastores keep the parameters they were created with.
+func SetNewStoreArgon2Params(params *crypto.Argon2Params) error {
+	if params == nil {
+		return nil
+	}
+	if sessionKDF.ID() != crypto.KDFArgon2id {
+		return fmt.Errorf("Argon2id parameters require the argon2id KDF, not %s", sessionKDF.Name())
+	}
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	sessionKDF = crypto.Argon2idParamsKDF{Params: *params}
+	return nil
+}
+
+// SetPassphraseAttempts sets how many times an interactively entered passphrase may be tried
+// against an existing datastore before loading fails. n must be at least 1.
+func SetPassphraseAttempts(n int) error {
+	if n < 1 {
+		return fmt.Errorf("passphrase attempts must be at least 1, got %d", n)
+	}
+	passphraseAttempts = n
+	return nil
+}
+
+// SetNewStoreCipher selects the cipher used when a new datastore is created.
+// Existing datastores keep the cipher recorded in their file header.
+func SetNewStoreCipher(name string) error {
+	c, err := crypto.CipherByName(name)
+	if err != nil {
+		return err
+	}
+	sessionCipher = c
+	return nil
+}
+
 // getPassphrase securely gets the passphrase, preferring env var, then prompting.
+// With promptForCreation, the passphrase is for a new store and must pass checkPassphraseStrength.
 func getPassphrase(promptForCreation bool) (string, error) {
 	passphrase := os.Getenv(config.PassphraseEnvVar)
 	if passphrase != "" {
+		if promptForCreation {
+			if err := checkPassphraseStrength(passphrase); err != nil {
+				return "", fmt.Errorf("%s: %w", config.PassphraseEnvVar, err)
+			}
+		}
+		return passphrase, nil
+	}
+	if OnPassphraseNeeded != nil {
+		passphrase, err := OnPassphraseNeeded()
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if promptForCreation && passphrase != "" {
+			if err := checkPassphraseStrength(passphrase); err != nil {
+				return "", err
+			}
+		}
 		return passphrase, nil
 	}
 	if !term.IsTerminal(int(os.Stdin.Fd())) {
@@ -44,6 +218,9 @@ func getPassphrase(promptForCreation bool) (string, error) {
 	}
 	passphrase = string(bytePassphrase)
 	if promptForCreation && passphrase != "" {
+		if err := checkPassphraseStrength(passphrase); err != nil {
+			return "", err
+		}
 		fmt.Fprint(os.Stderr, "Confirm passphrase: ")
 		bytePassphraseConfirm, err := term.ReadPassword(int(os.Stdin.Fd()))
 		fmt.Fprintln(os.Stderr)
@@ -57,28 +234,76 @@ func getPassphrase(promptForCreation bool) (string, error) {
 	return passphrase, nil
 }
 
-// Init initializes the datastore path and attempts to load data.
-func Init() error {
+// SetTempDir places the temporary file written by Save in dir instead of next to the datastore.
+func SetTempDir(dir string) {
+	tempDir = dir
+}
+
+// SetPath overrides the datastore location: a file path, or an s3://bucket/key URL (see OpenBackend).
+// It must be called before Init.
+func SetPath(path string) {
+	dataPath = path
+}
+
+// Path returns the datastore file location without loading it: the SetPath value,
+// or config.DataFileName next to the executable.
+func Path() (string, error) {
+	if dataPath != "" {
+		return dataPath, nil
+	}
 	exePath, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("failed to get executable path: %w", err)
+		return "", fmt.Errorf("failed to get executable path: %w", err)
 	}
 	exeDir := filepath.Dir(exePath)
-	dataPath = filepath.Join(exeDir, config.DataFileName)
+	return filepath.Join(exeDir, config.DataFileName), nil
+}
+
+// Init initializes the datastore path and attempts to load data.
+// Unless SetPath was called, the datastore lives next to the executable.
+func Init() error {
+	return InitCtx(context.Background())
+}
 
-	err = load() // load will handle passphrase and decryption
+// InitCtx is Init with cancellation: ctx is honored around key derivation.
+func InitCtx(ctx context.Context) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	dataPath = path
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	if fb, ok := b.(*fileBackend); ok && !dryRun {
+		fb.recoverStaleTemp()
+	}
+
+	start := time.Now()
+	logger.Debug("datastore load started", "path", dataPath)
+	err = loadCtx(ctx)
+	if err != nil {
+		logger.Debug("datastore load failed", "path", dataPath, "error", err, "duration", time.Since(start))
+	} else {
+		logger.Info("datastore load completed", "path", dataPath, "records", len(satellitesData),
+			"kdf", sessionKDF.Name(), "cipher", sessionCipher.Name(), "unlocked", IsUnlocked(), "duration", time.Since(start))
+	} // loadCtx will handle passphrase and decryption
 	if err != nil {
 		// Check for specific, non-fatal errors related to passphrase or file not existing
 		// These allow the CLI to start for commands that don't need datastore access (like 'help' or 'explain')
-		if os.IsNotExist(err) {
+		if os.IsNotExist(err) || errors.Is(err, ErrNotFound) {
 			// File doesn't exist: this is fine for initial load, passphrase will be requested on first save.
 			// load() function might have already printed a notice.
 			passphraseProvided = false // No data loaded, no key derived yet
 			sessionKey = nil
 			return nil
 		}
+		if errors.Is(err, ErrCorrupted) {
+			return err // Not a passphrase problem: fail loudly rather than carry on as if locked
+		}
 		if strings.Contains(err.Error(), "passphrase") || strings.Contains(err.Error(), "decrypt") {
-			fmt.Fprintf(os.Stderr, "Warning: Could not unlock datastore: %v\n", err)
+			noticef("Warning: Could not unlock datastore: %v\n", err)
 			passphraseProvided = false // Mark as not unlocked
 			sessionKey = nil
 			return nil // Allow CLI to proceed for non-data commands
@@ -89,6 +314,21 @@ func Init() error {
 	return nil
 }
 
+// Exists reports whether the datastore is present. A missing store is locked until its first save,
+// so callers use this to tell "nothing stored yet" apart from a wrong or missing passphrase.
+func Exists() bool {
+	path, err := Path()
+	if err != nil {
+		return false
+	}
+	b, err := OpenBackend(path)
+	if err != nil {
+		return false
+	}
+	version, err := b.Version(context.Background())
+	return err != nil || version != ""
+}
+
 // IsUnlocked returns true if the datastore is considered unlocked.
 func IsUnlocked() bool {
 	return passphraseProvided && len(sessionKey) > 0
@@ -96,28 +336,56 @@ func IsUnlocked() bool {
 
 // GetSatellites returns a copy of all satellite data.
 func GetSatellites() (map[string]types.Satellite, error) {
+	return GetSatellitesCtx(context.Background())
+}
+
+// GetSatellitesCtx is GetSatellites, returning ctx.Err() if ctx is done before or while waiting for the lock.
+func GetSatellitesCtx(ctx context.Context) (map[string]types.Satellite, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if !IsUnlocked() {
 		return nil, fmt.Errorf("datastore is locked or not initialized. Please set %s or provide passphrase.", config.PassphraseEnvVar)
 	}
-	dataFileLock.Lock()
-	defer dataFileLock.Unlock()
+	rlockDatastore("get")
+	defer dataFileLock.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	// Return a copy to prevent external modification
 	satsCopy := make(map[string]types.Satellite, len(satellitesData))
 	for k, v := range satellitesData {
-		satsCopy[k] = v
+		satsCopy[k] = v.Clone()
 	}
 	return satsCopy, nil
 }
 
+// Fingerprint returns the hex SHA-256 of the canonical plaintext (see encodeSatellites) of the
+// in-memory store. Unlike the file, which gets a new salt and nonce on every save, it changes
+// only when the data does.
+func Fingerprint() (string, error) {
+	if !IsUnlocked() {
+		return "", fmt.Errorf("datastore is locked or not initialized. Please set %s or provide passphrase.", config.PassphraseEnvVar)
+	}
+	rlockDatastore("fingerprint")
+	defer dataFileLock.RUnlock()
+	plaintext, err := encodeSatellites(satellitesData)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode satellite data: %w", err)
+	}
+	sum := sha256.Sum256(plaintext)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // AddSatellite adds or updates a satellite in the in-memory store.
 // Save() must be called to persist.
 func AddSatellite(sat types.Satellite) error {
 	if !IsUnlocked() {
 		return fmt.Errorf("datastore is locked. Cannot add/update satellite.")
 	}
-	dataFileLock.Lock()
+	lockDatastore("add")
 	defer dataFileLock.Unlock()
-	satellitesData[sat.Name] = sat
+	satellitesData[sat.Name] = sat.Clone()
 	return nil
 }
 
@@ -127,7 +395,7 @@ func DeleteSatellite(name string) error {
 	if !IsUnlocked() {
 		return fmt.Errorf("datastore is locked. Cannot delete satellite.")
 	}
-	dataFileLock.Lock()
+	lockDatastore("delete")
 	defer dataFileLock.Unlock()
 	if _, exists := satellitesData[name]; !exists {
 		return fmt.Errorf("satellite '%s' not found for deletion", name)
@@ -136,15 +404,43 @@ func DeleteSatellite(name string) error {
 	return nil
 }
 
+// RenameSatellite re-keys the satellite stored under oldName to newName and updates its Name field.
+// Save() must be called to persist.
+func RenameSatellite(oldName, newName string) error {
+	if !IsUnlocked() {
+		return fmt.Errorf("datastore is locked. Cannot rename satellite.")
+	}
+	lockDatastore("rename")
+	defer dataFileLock.Unlock()
+	sat, exists := satellitesData[oldName]
+	if !exists {
+		return fmt.Errorf("satellite '%s' not found for rename", oldName)
+	}
+	if _, taken := satellitesData[newName]; taken {
+		return fmt.Errorf("cannot rename '%s': a satellite named '%s' already exists", oldName, newName)
+	}
+	delete(satellitesData, oldName)
+	sat.Name = newName
+	satellitesData[newName] = sat
+	return nil
+}
 
-// load attempts to load and decrypt the datastore.
-func load() error {
+// loadCtx attempts to load and decrypt the datastore.
+func loadCtx(ctx context.Context) error {
 	// This function is called with dataFileLock already held by Init if restructuring
 	// For now, let's assume it needs its own lock or is called carefully.
 	// Simpler: init calls this, this handles its own lock.
 
-	_, statErr := os.Stat(dataPath)
-	fileExists := !os.IsNotExist(statErr)
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	encryptedFileBytes, version, readErr := b.Read(ctx)
+	fileExists := !errors.Is(readErr, ErrNotFound)
+	if readErr != nil && fileExists {
+		passphraseProvided = false; sessionKey = nil
+		return readErr
+	}
 
 	// Try to get passphrase. Prompt for creation (confirmation) only if file does NOT exist.
 	currentPassphrase, passErr := getPassphrase(!fileExists)
@@ -152,7 +448,12 @@ func load() error {
 	if passErr != nil {
 		passphraseProvided = false; sessionKey = nil
 		if fileExists { return fmt.Errorf("passphrase acquisition failed for existing datastore: %w", passErr) }
-		fmt.Fprintf(os.Stderr, "Notice: Datastore file '%s' not found. Passphrase prompt failed or was skipped. First save will require a valid passphrase.\n", dataPath)
+		if errors.Is(passErr, ErrWeakPassphrase) {
+			noticef("Warning: Datastore file '%s' not found and the passphrase cannot be used for a new datastore: %v\n", dataPath, passErr)
+			satellitesData = make(map[string]types.Satellite)
+			return nil
+		}
+		noticef("Notice: Datastore file '%s' not found. Passphrase prompt failed or was skipped. First save will require a valid passphrase.\n", dataPath)
 		satellitesData = make(map[string]types.Satellite)
 		return nil
 	}
@@ -160,7 +461,7 @@ func load() error {
 	if currentPassphrase == "" {
 		passphraseProvided = false; sessionKey = nil
 		if fileExists { return fmt.Errorf("passphrase not provided for existing datastore '%s'", dataPath) }
-		fmt.Fprintf(os.Stderr, "Notice: Datastore file '%s' not found and no passphrase provided. First save will require a valid passphrase.\n", dataPath)
+		noticef("Notice: Datastore file '%s' not found and no passphrase provided. First save will require a valid passphrase.\n", dataPath)
 		satellitesData = make(map[string]types.Satellite)
 		return nil
 	}
@@ -168,7 +469,7 @@ func load() error {
 	passphraseProvided = true // A non-empty passphrase was obtained
 
 	if !fileExists {
-		fmt.Fprintf(os.Stderr, "Notice: Datastore file '%s' not found. Will be created and encrypted on first save with the provided passphrase.\n", dataPath)
+		noticef("Notice: Datastore file '%s' not found. Will be created and encrypted on first save with the provided passphrase.\n", dataPath)
 		satellitesData = make(map[string]types.Satellite)
 		// Key will be derived with a new salt during the first save using currentPassphrase
 		// We store the passphrase (conceptually, by setting passphraseProvided) but derive key on save with new salt.
@@ -177,57 +478,211 @@ func load() error {
 	}
 
 	// File exists, proceed with decryption
-	encryptedFileBytes, err := ioutil.ReadFile(dataPath)
+	storeVersion = version
+
+	tempSatellites, format, key, err := decryptStore(ctx, encryptedFileBytes, currentPassphrase)
+	// A mistyped passphrase at the prompt is retried; one from the environment cannot change, so it is not.
+	for attempt := 1; errors.Is(err, crypto.ErrWrongPassphrase) && attempt < passphraseAttempts && os.Getenv(config.PassphraseEnvVar) == ""; attempt++ {
+		promptf("Incorrect passphrase, try again (%d attempt(s) left).\n", passphraseAttempts-attempt)
+		currentPassphrase, passErr = getPassphrase(false)
+		if passErr != nil {
+			passphraseProvided = false; sessionKey = nil
+			return fmt.Errorf("passphrase acquisition failed for existing datastore: %w", passErr)
+		}
+		tempSatellites, format, key, err = decryptStore(ctx, encryptedFileBytes, currentPassphrase)
+	}
 	if err != nil {
 		passphraseProvided = false; sessionKey = nil
-		return fmt.Errorf("failed to read encrypted datastore %s: %w", dataPath, err)
+		if errors.Is(err, ErrCorrupted) {
+			return fmt.Errorf("%w; no passphrase can recover it, restore %s from a backup", err, dataPath)
+		}
+		return err
 	}
 
-	if len(encryptedFileBytes) < (config.Argon2SaltSize + config.AESGCMNonceSize) {
-		passphraseProvided = false; sessionKey = nil
-		return fmt.Errorf("encrypted datastore file is too short or corrupted (salt+nonce sections missing)")
+	sessionKey = key // Store derived key for the session if decryption successful
+	sessionKDF, sessionCipher = format.KDF, format.Cipher // Keep the store's algorithms for subsequent saves
+	sessionPassphrase = currentPassphrase
+	satellitesData = tempSatellites
+	if dryRun {
+		savedData = maps.Clone(satellitesData) // Values are never modified in place, so a shallow copy suffices
 	}
+	return nil
+}
 
-	salt := encryptedFileBytes[:config.Argon2SaltSize]
-	nonceAndCiphertext := encryptedFileBytes[config.Argon2SaltSize:]
+// Reload re-reads the datastore, replacing the in-memory data with what is on disk.
+// It reuses the passphrase that unlocked the store, so it never prompts. Unsaved in-memory changes are discarded.
+func Reload() error {
+	if !IsUnlocked() {
+		return fmt.Errorf("datastore is locked. Cannot reload.")
+	}
+	lockDatastore("reload")
+	defer dataFileLock.Unlock()
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	encryptedFileBytes, version, err := b.Read(context.Background())
+	if err != nil {
+		return err
+	}
+	sats, format, key, err := decryptStore(context.Background(), encryptedFileBytes, sessionPassphrase)
+	if err != nil {
+		return err
+	}
+	satellitesData, sessionKDF, sessionCipher, sessionKey = sats, format.KDF, format.Cipher, key
+	storeVersion = version
+	if dryRun {
+		savedData = maps.Clone(satellitesData)
+	}
+	return nil
+}
+
+// fileSum identifies one version of the datastore file's bytes.
+func fileSum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
 
-	key, keyErr := crypto.DeriveKeyWithArgon2id(currentPassphrase, salt)
+// checkUnchanged fails with ErrModifiedExternally if the store is not the one last loaded or saved
+// by this process (including being created or deleted since), unless SetForceSave is on. It runs
+// before a passphrase is asked for; Backend.Write checks again when it commits. dataFileLock must be held.
+func checkUnchanged(ctx context.Context, b Backend) error {
+	if forceSave {
+		return nil
+	}
+	current, err := b.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if current != storeVersion {
+		return modifiedExternally(b.Location())
+	}
+	return nil
+}
+
+// decryptStore decrypts the raw bytes of a datastore file with passphrase.
+// It has no side effects, so it can be used to inspect a store without unlocking it.
+func decryptStore(ctx context.Context, encryptedFileBytes []byte, passphrase string) (map[string]types.Satellite, fileFormat, []byte, error) {
+	format, salt, nonceAndCiphertext, err := parseHeader(encryptedFileBytes)
+	if err != nil {
+		return nil, fileFormat{}, nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	key, keyErr := crypto.DeriveKeyCtx(ctx, format.KDF, passphrase, salt)
 	if keyErr != nil {
-		passphraseProvided = false; sessionKey = nil
-		return fmt.Errorf("key derivation failed during load: %w", keyErr)
+		return nil, fileFormat{}, nil, fmt.Errorf("key derivation failed during load: %w", keyErr)
+	}
+
+	plaintext, err := format.Cipher.Decrypt(nonceAndCiphertext, key)
+	if errors.Is(err, crypto.ErrCorrupted) {
+		return nil, fileFormat{}, nil, fmt.Errorf("%w: %w", ErrCorrupted, err)
 	}
-	
-	plaintext, err := crypto.Decrypt(nonceAndCiphertext, key)
 	if err != nil {
-		passphraseProvided = false; sessionKey = nil 
-		return err // Decrypt already provides a good error message (passphrase/integrity)
+		return nil, fileFormat{}, nil, err // Decrypt already provides a good error message (passphrase/integrity)
 	}
 
-	sessionKey = key // Store derived key for the session if decryption successful
+	sats, err := decodeSatellites(plaintext)
+	if err != nil {
+		return nil, fileFormat{}, nil, fmt.Errorf("%w: failed to unmarshal decrypted satellite data: %v", ErrCorrupted, err)
+	}
+	return sats, format, key, nil
+}
 
-	tempSatellites := make(map[string]types.Satellite)
-	if err := json.Unmarshal(plaintext, &tempSatellites); err != nil {
-		passphraseProvided = false; sessionKey = nil // Data corrupted after decryption
-		return fmt.Errorf("failed to unmarshal decrypted satellite data: %w (data may be corrupt)", err)
+// decodeSatellites parses the decrypted JSON object of name -> satellite. A JSON null yields an
+// empty map. json.Unmarshal is used rather than a streaming json.Decoder: the cipher already
+// holds the whole plaintext in memory, and decoding it record by record allocates more (see
+// BenchmarkLoad50k), so streaming only pays off once decryption is streamed too.
+func decodeSatellites(plaintext []byte) (map[string]types.Satellite, error) {
+	sats := make(map[string]types.Satellite)
+	if err := json.Unmarshal(plaintext, &sats); err != nil {
+		return nil, err
 	}
-	satellitesData = tempSatellites
-	if satellitesData == nil { // Should not occur if JSON was valid, even "{}"
-		satellitesData = make(map[string]types.Satellite)
+	if sats == nil { // The document was null
+		sats = make(map[string]types.Satellite)
 	}
-	return nil
+	return sats, nil
+}
+
+// encodeSatellites is the inverse of decodeSatellites: an indented JSON object with entries
+// written in name order, so identical data always produces byte-identical plaintext. The
+// output matches json.MarshalIndent(sats, "", "  "), but the ordering does not depend on it.
+func encodeSatellites(sats map[string]types.Satellite) ([]byte, error) {
+	if len(sats) == 0 {
+		return []byte("{}"), nil
+	}
+	names := make([]string, 0, len(sats))
+	for name := range sats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, name := range names {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.MarshalIndent(sats[name], "  ", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("record '%s': %w", name, err)
+		}
+		buf.WriteString("  ")
+		buf.Write(key)
+		buf.WriteString(": ")
+		buf.Write(value)
+		if i < len(names)-1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
 }
 
 // Save encrypts and writes the current state of satellites.
 func Save() error {
-	dataFileLock.Lock()
+	return SaveCtx(context.Background())
+}
+
+// SaveCtx is Save with cancellation. ctx is checked before and after key derivation and
+// before the file is written; once the write starts the save runs to completion.
+func SaveCtx(ctx context.Context) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	lockDatastore("save")
 	defer dataFileLock.Unlock()
 
+	start := time.Now()
+	logger.Debug("datastore save started", "path", dataPath, "records", len(satellitesData))
+	defer func() {
+		if err != nil {
+			logger.Debug("datastore save failed", "path", dataPath, "error", err, "duration", time.Since(start))
+			return
+		}
+		logger.Info("datastore save completed", "path", dataPath, "records", len(satellitesData),
+			"kdf", sessionKDF.Name(), "cipher", sessionCipher.Name(), "duration", time.Since(start))
+	}()
+
+	// Fail early with a clear message rather than deep inside the temp-file write or rename.
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	if err := b.CheckWritable(ctx); err != nil {
+		return err
+	}
+	if err := checkUnchanged(ctx, b); err != nil {
+		return err
+	}
+
 	if !passphraseProvided {
 		// This state implies that InitDataStore/load might have failed to get a passphrase,
 		// or the user is trying to save without having unlocked an existing store
 		// or without having provided a passphrase for a new store.
 		// Re-attempt to get passphrase, this time it's definitively for creation/overwrite.
-		fmt.Fprintln(os.Stderr, "Passphrase required to save datastore.")
+		promptf("Passphrase required to save datastore.\n")
 		currentPassphrase, passErr := getPassphrase(true) // true for confirmation if new
 		if passErr != nil || currentPassphrase == "" {
 			return fmt.Errorf("passphrase is required to save encrypted datastore: %w (or set %s)", passErr, config.PassphraseEnvVar)
@@ -247,15 +702,101 @@ func Save() error {
         // This is not ideal UX if they just entered it.
         // A better way would be to store the raw passphrase in memory IF obtained via prompt for the session.
         // For this iteration, we will re-prompt for save if not in ENV.
-        fmt.Fprintln(os.Stderr, "Re-enter passphrase to confirm save operation:")
+        promptf("Re-enter passphrase to confirm save operation:\n")
         currentPassphrase, errPass = getPassphrase(false) // false = don't need double confirm, just get it.
         if errPass != nil || currentPassphrase == "" {
             return fmt.Errorf("passphrase re-confirmation failed for saving: %w", errPass)
         }
     }
+	return saveLocked(ctx, currentPassphrase)
+}
 
+// Create writes a new, empty datastore encrypted under a newly entered passphrase (confirmed when
+// prompted), using the KDF and cipher chosen for new stores. An existing file is an error unless
+// force is set, in which case it is replaced without being decrypted. The session is left unlocked.
+func Create(ctx context.Context, force bool) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	dataPath = path
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	version, err := b.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if version != "" && !force {
+		return fmt.Errorf("a datastore already exists at %s (use --force to replace it)", path)
+	}
+	if err := b.CheckWritable(ctx); err != nil {
+		return err
+	}
+	passphrase, err := getPassphrase(true)
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return fmt.Errorf("passphrase cannot be empty")
+	}
+
+	lockDatastore("create")
+	defer dataFileLock.Unlock()
+	satellitesData, storeVersion = make(map[string]types.Satellite), version
+	if err := saveLocked(ctx, passphrase); err != nil {
+		return err
+	}
+	passphraseProvided, sessionPassphrase = true, passphrase
+	logger.Info("datastore created", "path", dataPath, "kdf", sessionKDF.Name(), "cipher", sessionCipher.Name())
+	return nil
+}
+
+// ChangePassphrase re-encrypts the unlocked datastore under newPassphrase, which must pass the
+// strength policy. The KDF and cipher are kept, and a fresh salt is used as for every save. The
+// session then uses the new passphrase.
+func ChangePassphrase(ctx context.Context, newPassphrase string) error {
+	if !IsUnlocked() {
+		return fmt.Errorf("datastore must be unlocked with the current passphrase before it can be changed")
+	}
+	if err := checkPassphraseStrength(newPassphrase); err != nil {
+		return err
+	}
+	lockDatastore("change passphrase")
+	defer dataFileLock.Unlock()
+
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	if err := b.CheckWritable(ctx); err != nil {
+		return err
+	}
+	if err := checkUnchanged(ctx, b); err != nil {
+		return err
+	}
+	if err := saveLocked(ctx, newPassphrase); err != nil {
+		return err
+	}
+	sessionPassphrase = newPassphrase
+	logger.Info("datastore passphrase changed", "path", dataPath, "records", len(satellitesData))
+	return nil
+}
 
-	plaintext, err := json.MarshalIndent(satellitesData, "", "  ")
+// saveLocked encrypts satellitesData under passphrase with a fresh salt and writes it to the backend,
+// which replaces the store atomically. dataFileLock must be held.
+func saveLocked(ctx context.Context, currentPassphrase string) error {
+	if dryRun {
+		if OnDryRunSave != nil {
+			OnDryRunSave(savedData, satellitesData)
+		}
+		savedData = maps.Clone(satellitesData) // A later save reports only what it adds
+		logger.Info("datastore save skipped (dry run)", "path", dataPath, "records", len(satellitesData))
+		noticef("Notice: dry run: %s was not written.\n", dataPath)
+		return nil
+	}
+	plaintext, err := encodeSatellites(satellitesData)
 	if err != nil {
 		return fmt.Errorf("failed to marshal satellite data for encryption: %w", err)
 	}
@@ -267,35 +808,90 @@ func Save() error {
 	}
 
 	// Derive key with the current passphrase and the NEW salt
-	keyForSave, keyErr := crypto.DeriveKeyWithArgon2id(currentPassphrase, salt)
+	keyForSave, keyErr := crypto.DeriveKeyCtx(ctx, sessionKDF, currentPassphrase, salt)
 	if keyErr != nil {
 		return fmt.Errorf("key derivation for save failed: %w", keyErr)
 	}
 	// Update the session key. This is the key corresponding to the current file state.
 	sessionKey = keyForSave
 
-	// Encrypt using the derived key (nonce will be generated by crypto.Encrypt)
-	nonceAndCiphertext, err := crypto.Encrypt(plaintext, keyForSave)
+	// Encrypt using the derived key (nonce will be generated by the cipher)
+	nonceAndCiphertext, err := sessionCipher.Encrypt(plaintext, keyForSave)
 	if err != nil {
 		return fmt.Errorf("encryption failed: %w", err)
 	}
 
-	// Prepend salt to the (nonce + ciphertext) payload
-	encryptedFileBytes := append(salt, nonceAndCiphertext...)
+	// Prepend header and salt to the (nonce + ciphertext) payload
+	encryptedFileBytes := append(buildHeader(fileFormat{KDF: sessionKDF, Cipher: sessionCipher}), salt...)
+	encryptedFileBytes = append(encryptedFileBytes, nonceAndCiphertext...)
 
-	// Write to a temporary file first for atomicity
-	tempDataPath := dataPath + ".tmp"
-	if err := ioutil.WriteFile(tempDataPath, encryptedFileBytes, 0600); err != nil { // 0600 for restricted permissions
-		return fmt.Errorf("failed to write temporary encrypted datastore %s: %w", tempDataPath, err)
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	// Atomically replace the old file with the new one
-	if err := os.Rename(tempDataPath, dataPath); err != nil {
-		// Attempt to clean up temp file if rename fails
-		_ = os.Remove(tempDataPath)
-		return fmt.Errorf("failed to commit encrypted datastore from %s to %s: %w", tempDataPath, dataPath, err)
+	// SIGINT/SIGTERM are held back until the temp file has been written and committed,
+	// so Ctrl-C cannot orphan the temp file or leave the store half-replaced.
+	defer holdSignals()()
+
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	version, err := b.Write(ctx, encryptedFileBytes, storeVersion, forceSave)
+	if err != nil {
+		return err
 	}
+	storeVersion = version
 	return nil
 }
 
+// holdSignals captures SIGINT and SIGTERM until the returned release function is called.
+// release restores the previous handling and re-delivers the first captured signal, so the
+// process still exits (or a watching caller still stops) once the critical section is done.
+func holdSignals() (release func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	return func() {
+		signal.Stop(sigs)
+		select {
+		case sig := <-sigs:
+			if p, err := os.FindProcess(os.Getpid()); err == nil {
+				_ = p.Signal(sig)
+			}
+		default:
+		}
+	}
+}
+
+// checkDirWritable verifies that files can be created in dir.
+func checkDirWritable(dir string) error {
+	f, err := ioutil.TempFile(dir, ".satcli-write-check-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
 
+// copyFile copies src over dst with 0600 permissions and syncs it to disk.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
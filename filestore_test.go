@@ -0,0 +1,276 @@
+// internal/datastore/filestore_test.go
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/crypto"
+	"github.com/yackko/satcom-code/types"
+)
+
+// testPassphrase passes the strength policy for new stores.
+const testPassphrase = "Correct-Horse-9-battery"
+
+// testArgon2Params are cheap Argon2id costs, so the tests do not spend time deriving keys.
+var testArgon2Params = crypto.Argon2Params{Time: 1, MemoryKiB: 8 * 1024, Threads: 1}
+
+// sealTestStore returns the file bytes saveLocked writes for sats in format under passphrase.
+func sealTestStore(t testing.TB, format fileFormat, passphrase string, sats map[string]types.Satellite) []byte {
+	t.Helper()
+	plaintext, err := encodeSatellites(sats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt := bytes.Repeat([]byte{9}, config.Argon2SaltSize)
+	key, err := format.KDF.DeriveKey(passphrase, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := format.Cipher.Encrypt(plaintext, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(append(buildHeader(format), salt...), sealed...)
+}
+
+// testSatellites returns n distinct records with every kind of field set.
+func testSatellites(n int) map[string]types.Satellite {
+	sats := make(map[string]types.Satellite, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("SAT-%05d", i)
+		sats[name] = types.Satellite{
+			Name:             name,
+			OrbitType:        []string{"LEO", "MEO", "GEO", "HEO"}[i%4],
+			Altitude:         500 + float64(i%30000) + 0.25,
+			Eccentricity:     float64(i%700) / 1000,
+			Inclination:      float64(i%180) + 0.5,
+			PowerSystem:      "Solar",
+			Communication:    "Ku-band",
+			Size:             2.5,
+			Weight:           float64(100 + i%5000),
+			Constellation:    i%3 == 0,
+			LaunchDate:       fmt.Sprintf("%04d-%02d-%02d", 1990+i%35, 1+i%12, 1+i%28),
+			Operator:         fmt.Sprintf("Operator %d", i%40),
+			MissionObjective: "Communications",
+			Status:           "active",
+			Tags:             []string{"fleet", fmt.Sprintf("batch-%d", i%10)},
+			Custom:           map[string]string{"noradId": fmt.Sprint(40000 + i)},
+		}
+	}
+	return sats
+}
+
+// streamDecodeSatellites is the record-by-record json.Decoder loader that decodeSatellites
+// replaced, kept for comparison in BenchmarkLoad50k.
+func streamDecodeSatellites(plaintext []byte) (map[string]types.Satellite, error) {
+	sats := make(map[string]types.Satellite)
+	dec := json.NewDecoder(bytes.NewReader(plaintext))
+	if _, err := dec.Token(); err != nil { // opening '{'
+		return nil, err
+	}
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var sat types.Satellite
+		if err := dec.Decode(&sat); err != nil {
+			return nil, err
+		}
+		sats[keyTok.(string)] = sat
+	}
+	_, err := dec.Token() // closing '}'
+	return sats, err
+}
+
+// BenchmarkLoad50k measures decrypting and decoding a 50k-record store, without key derivation.
+// Compare the allocations of the unmarshal case, which is what load does, with the stream case,
+// e.g. with go test -run '^$' -bench Load50k -benchmem ./internal/datastore.
+func BenchmarkLoad50k(b *testing.B) {
+	plaintext, err := encodeSatellites(testSatellites(50000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	key := bytes.Repeat([]byte{7}, 32)
+	cipher := crypto.AESGCMCipher{}
+	sealed, err := cipher.Encrypt(plaintext, key)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, bc := range []struct {
+		name   string
+		decode func([]byte) (map[string]types.Satellite, error)
+	}{
+		{"unmarshal", decodeSatellites},
+		{"stream", streamDecodeSatellites},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(plaintext)))
+			for i := 0; i < b.N; i++ {
+				decrypted, err := cipher.Decrypt(sealed, key)
+				if err != nil {
+					b.Fatal(err)
+				}
+				sats, err := bc.decode(decrypted)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(sats) != 50000 {
+					b.Fatalf("decoded %d records, want 50000", len(sats))
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeSatellites(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"empty object", `{}`, 0, false},
+		{"null", `null`, 0, false},
+		{"records", `{"A": {"name": "A"}, "B": {"name": "B"}}`, 2, false},
+		{"duplicate keys", `{"A": {"name": "A"}, "A": {"name": "A2"}}`, 1, false},
+		{"trailing data", `{} {}`, 0, true},
+		{"not an object", `[]`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sats, err := decodeSatellites([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && (sats == nil || len(sats) != tt.want) {
+				t.Errorf("got %d records (nil map: %t), want %d", len(sats), sats == nil, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeSatellitesIsDeterministic(t *testing.T) {
+	for _, n := range []int{0, 1, 500} {
+		t.Run(fmt.Sprintf("%d records", n), func(t *testing.T) {
+			sats := testSatellites(n)
+			reordered := make(map[string]types.Satellite, n) // Same data, different insertion order
+			names := make([]string, 0, n)
+			for name := range sats {
+				names = append(names, name)
+			}
+			for i := len(names) - 1; i >= 0; i-- {
+				reordered[names[i]] = sats[names[i]].Clone()
+			}
+
+			first, err := encodeSatellites(sats)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := encodeSatellites(reordered)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(first, second) {
+				t.Error("two saves of identical data produced different plaintext")
+			}
+			want, err := json.MarshalIndent(sats, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(first, want) {
+				t.Errorf("plaintext differs from json.MarshalIndent:\n got %.200s\nwant %.200s", first, want)
+			}
+		})
+	}
+}
+
+func TestDecryptStoreRoundTripsEachKDF(t *testing.T) {
+	sats := testSatellites(3)
+	for _, kdf := range []crypto.KeyDeriver{crypto.Argon2idKDF{}, crypto.Argon2idParamsKDF{Params: testArgon2Params}, crypto.ScryptKDF{}} {
+		t.Run(fmt.Sprintf("%s id %d", kdf.Name(), kdf.ID()), func(t *testing.T) {
+			file := sealTestStore(t, fileFormat{kdf, crypto.AESGCMCipher{}}, testPassphrase, sats)
+			got, format, _, err := decryptStore(context.Background(), file, testPassphrase)
+			if err != nil {
+				t.Fatalf("decryptStore: %v", err)
+			}
+			if format.KDF != kdf {
+				t.Errorf("KDF = %#v, want %#v", format.KDF, kdf)
+			}
+			if !reflect.DeepEqual(got, sats) {
+				t.Errorf("records differ after the round trip")
+			}
+			if _, _, _, err := decryptStore(context.Background(), file, testPassphrase+"!"); !errors.Is(err, crypto.ErrWrongPassphrase) {
+				t.Errorf("wrong passphrase: err = %v, want ErrWrongPassphrase", err)
+			}
+		})
+	}
+}
+
+func TestDecryptStoreDamagedFiles(t *testing.T) {
+	sats := testSatellites(2)
+	tests := []struct {
+		name          string
+		damage        func(file []byte, payloadStart int) []byte
+		wantErr       error
+		wantCorrupted bool // Whether err is also a datastore ErrCorrupted
+	}{
+		{"flipped ciphertext byte", func(file []byte, start int) []byte { file[start+20] ^= 0x01; return file }, crypto.ErrWrongPassphrase, false},
+		{"flipped last byte", func(file []byte, _ int) []byte { file[len(file)-1] ^= 0x01; return file }, crypto.ErrWrongPassphrase, false},
+		{"payload below the minimum size", func(file []byte, start int) []byte { return file[:start+12+16-1] }, crypto.ErrCorrupted, true},
+	}
+	for _, cipher := range []crypto.Cipher{crypto.AESGCMCipher{}, crypto.ChaCha20Poly1305Cipher{}} {
+		format := fileFormat{crypto.Argon2idParamsKDF{Params: testArgon2Params}, cipher}
+		file := sealTestStore(t, format, testPassphrase, sats)
+		payloadStart := len(buildHeader(format)) + config.Argon2SaltSize
+		for _, tt := range tests {
+			t.Run(cipher.Name()+" "+tt.name, func(t *testing.T) {
+				damaged := tt.damage(bytes.Clone(file), payloadStart)
+				_, _, _, err := decryptStore(context.Background(), damaged, testPassphrase)
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+				if errors.Is(err, ErrCorrupted) != tt.wantCorrupted {
+					t.Errorf("errors.Is(err, ErrCorrupted) = %t, want %t (err = %v)", !tt.wantCorrupted, tt.wantCorrupted, err)
+				}
+			})
+		}
+	}
+}
+
+func TestDecryptStoreReadsHeaderlessFilesAsArgon2id(t *testing.T) {
+	sats := testSatellites(2)
+	plaintext, err := encodeSatellites(sats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt := bytes.Repeat([]byte{3}, config.Argon2SaltSize)
+	key, err := crypto.DeriveKeyWithArgon2id(testPassphrase, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := crypto.Encrypt(plaintext, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := append(salt, sealed...) // Written before the header existed: salt | nonce+ciphertext
+
+	got, format, _, err := decryptStore(context.Background(), legacy, testPassphrase)
+	if err != nil {
+		t.Fatalf("decryptStore: %v", err)
+	}
+	if format.KDF != (crypto.Argon2idKDF{}) || format.Cipher != (crypto.AESGCMCipher{}) {
+		t.Errorf("format = %#v, want Argon2id with AES-GCM", format)
+	}
+	if !reflect.DeepEqual(got, sats) {
+		t.Errorf("records differ after loading the legacy file")
+	}
+}
@@ -0,0 +1,330 @@
+// cmd/satcli/filter.go
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Filter holds the criteria 'satcli query' selects satellites by. Zero values do not filter;
+// altitudes, perigee, apogee and semi-major axis are in km.
+type Filter struct {
+	Operator           string
+	NormalizeOperators bool // Match Operator through the config file's operator-aliases
+	Status             string
+	OrbitType          string
+	LaunchAfter        time.Time
+	LaunchBefore       time.Time
+	StrictDates        bool  // Exclude, rather than include, unparsable launch dates when dates are filtered
+	Constellation      *bool // nil matches both
+	Archived           *bool // nil matches both
+	MinAltitude        float64
+	MaxAltitude        float64
+	MinPerigee         float64
+	MaxApogee          float64
+	MinSMA             float64
+	MaxSMA             float64
+	LongitudeFiltered  bool // Only GEO/GSO satellites between MinLongitude and MaxLongitude
+	MinLongitude       float64
+	MaxLongitude       float64
+	MinAge             float64
+	MaxAge             float64
+	Custom             map[string]string
+	Tags               []string
+	AllTags            bool // Require every tag rather than any
+}
+
+// FilterSatellites returns the satellites in sats that match every criterion of f, in their order.
+func FilterSatellites(sats []types.Satellite, f Filter) []types.Satellite {
+	var matched []types.Satellite
+	for _, sat := range sats {
+		if f.matches(sat) {
+			matched = append(matched, sat)
+		}
+	}
+	return matched
+}
+
+// FilterReport counts the records whose launch date could not be parsed, which the caller warns about.
+type FilterReport struct {
+	SkippedDates int      // Excluded by StrictDates
+	UndatedNames []string // Included without date filtering, sorted
+	SkippedAges  int      // Excluded by MinAge/MaxAge
+}
+
+// unparsableDateReport reports the satellites in sats that every criterion of f not reading the
+// launch date matches, but whose launch date the date or age criteria could not parse.
+func unparsableDateReport(sats []types.Satellite, f Filter) FilterReport {
+	var report FilterReport
+	for _, sat := range sats {
+		if !f.matchesIgnoringDates(sat) {
+			continue
+		}
+		dateOK, dateUnparsable := f.matchesLaunchDate(sat)
+		if !dateOK {
+			if dateUnparsable {
+				report.SkippedDates++
+			}
+			continue
+		}
+		ageOK, ageUnparsable := f.matchesAge(sat)
+		switch {
+		case ageUnparsable:
+			report.SkippedAges++
+		case ageOK && dateUnparsable:
+			report.UndatedNames = append(report.UndatedNames, sat.Name)
+		}
+	}
+	sort.Strings(report.UndatedNames)
+	return report
+}
+
+// matches reports whether sat meets every criterion of f.
+func (f Filter) matches(sat types.Satellite) bool {
+	if !f.matchesIgnoringDates(sat) {
+		return false
+	}
+	dateOK, _ := f.matchesLaunchDate(sat)
+	ageOK, _ := f.matchesAge(sat)
+	return dateOK && ageOK
+}
+
+// matchesIgnoringDates reports whether sat meets the criteria of f that do not read the launch date.
+func (f Filter) matchesIgnoringDates(sat types.Satellite) bool {
+	if f.Archived != nil && sat.Archived != *f.Archived {
+		return false
+	}
+	if f.Operator != "" {
+		operator := sat.Operator
+		if f.NormalizeOperators {
+			operator = types.NormalizeOperator(operator)
+		}
+		if !strings.EqualFold(operator, f.Operator) {
+			return false
+		}
+	}
+	if (f.Status != "" && !strings.EqualFold(sat.Status, f.Status)) ||
+		(f.OrbitType != "" && !strings.EqualFold(sat.OrbitType, f.OrbitType)) {
+		return false
+	}
+	if f.Constellation != nil && sat.Constellation != *f.Constellation {
+		return false
+	}
+	if (f.MinAltitude > 0 && sat.Altitude < f.MinAltitude) || (f.MaxAltitude > 0 && sat.Altitude > f.MaxAltitude) ||
+		(f.MinSMA > 0 && sat.SemiMajorAxis() < f.MinSMA) || (f.MaxSMA > 0 && sat.SemiMajorAxis() > f.MaxSMA) {
+		return false
+	}
+	if f.LongitudeFiltered && !inLongitudeRange(sat, f.MinLongitude, f.MaxLongitude) {
+		return false
+	}
+	if !matchesCustom(sat, f.Custom) || !matchesTags(sat, f.Tags, f.AllTags) {
+		return false
+	}
+	if f.MinPerigee > 0 || f.MaxApogee > 0 {
+		apo, peri, err := sat.ApogeePerigeeKm()
+		if err != nil || (f.MinPerigee > 0 && peri < f.MinPerigee) || (f.MaxApogee > 0 && apo > f.MaxApogee) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesLaunchDate applies LaunchAfter and LaunchBefore to sat. A launch date that can't be
+// parsed is unparsable, and matches unless StrictDates.
+func (f Filter) matchesLaunchDate(sat types.Satellite) (ok, unparsable bool) {
+	if f.LaunchAfter.IsZero() && f.LaunchBefore.IsZero() {
+		return true, false
+	}
+	launchDate, err := parseLaunchDate(sat.LaunchDate)
+	if err != nil {
+		return !f.StrictDates, true
+	}
+	return (f.LaunchAfter.IsZero() || !launchDate.Before(f.LaunchAfter)) &&
+		(f.LaunchBefore.IsZero() || !launchDate.After(f.LaunchBefore)), false
+}
+
+// matchesAge applies MinAge and MaxAge to sat. An age that can't be computed is unparsable and
+// never matches.
+func (f Filter) matchesAge(sat types.Satellite) (ok, unparsable bool) {
+	if f.MinAge <= 0 && f.MaxAge <= 0 {
+		return true, false
+	}
+	age, err := sat.YearsInOrbit()
+	if err != nil {
+		return false, true
+	}
+	return (f.MinAge <= 0 || age >= f.MinAge) && (f.MaxAge <= 0 || age <= f.MaxAge), false
+}
+
+// addQueryMatchFlags registers the query filters that profiles do not save.
+func addQueryMatchFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("custom", nil, "Filter by custom attribute as key=value (repeatable; all must match, value case-insensitive)")
+	cmd.Flags().StringArray("tag", nil, "Filter by tag (repeatable; case-insensitive; see --tag-mode)")
+	cmd.Flags().String("tag-mode", "any", "How repeated --tag filters combine: any or all")
+	cmd.Flags().Bool("strict-dates", false, "Exclude records whose launch date cannot be parsed from date-filtered results (default: include them with a warning)")
+}
+
+// filterFromFlags builds and checks the Filter given by the flags of addQueryFilterFlags,
+// addQueryMatchFlags and addAltitudeUnitFlag, converting altitudes from --altitude-unit to km.
+func filterFromFlags(cmd *cobra.Command) (Filter, error) {
+	var f Filter
+	flags := cmd.Flags()
+	f.Operator, _ = flags.GetString("operator")
+	f.NormalizeOperators, _ = flags.GetBool("normalize-operators")
+	if f.NormalizeOperators && f.Operator != "" {
+		f.Operator = types.NormalizeOperator(f.Operator)
+	}
+	f.Status, _ = flags.GetString("status")
+	f.OrbitType, _ = flags.GetString("orbit-type")
+	f.StrictDates, _ = flags.GetBool("strict-dates")
+	f.MinAltitude, _ = flags.GetFloat64("min-altitude")
+	f.MaxAltitude, _ = flags.GetFloat64("max-altitude")
+	f.MinPerigee, _ = flags.GetFloat64("min-perigee")
+	f.MaxApogee, _ = flags.GetFloat64("max-apogee")
+	f.MinSMA, _ = flags.GetFloat64("min-sma")
+	f.MaxSMA, _ = flags.GetFloat64("max-sma")
+	f.MinLongitude, _ = flags.GetFloat64("min-longitude")
+	f.MaxLongitude, _ = flags.GetFloat64("max-longitude")
+	f.LongitudeFiltered = flags.Changed("min-longitude") || flags.Changed("max-longitude")
+	if !flags.Changed("min-longitude") {
+		f.MinLongitude = -180
+	}
+	if !flags.Changed("max-longitude") {
+		f.MaxLongitude = 180
+	}
+	f.MinAge, _ = flags.GetFloat64("min-age")
+	f.MaxAge, _ = flags.GetFloat64("max-age")
+	altitudeBand, _ := flags.GetString("altitude-band")
+	launchAfter, _ := flags.GetString("launch-after")
+	launchBefore, _ := flags.GetString("launch-before")
+
+	var err error
+	customPairs, _ := flags.GetStringArray("custom")
+	if f.Custom, err = parseCustomPairs("--custom", customPairs); err != nil {
+		return f, err
+	}
+	f.Tags, _ = flags.GetStringArray("tag")
+	tagMode, _ := flags.GetString("tag-mode")
+	tagMode = strings.ToLower(tagMode)
+	if !containsString(tagModes, tagMode) {
+		return f, fmt.Errorf("invalid value for --tag-mode: '%s'. Use one of: %s", tagMode, strings.Join(tagModes, ", "))
+	}
+	f.AllTags = tagMode == "all"
+	if f.Constellation, err = constellationFilterFromFlags(cmd); err != nil {
+		return f, err
+	}
+	if f.Archived, err = archivedFilterFromFlags(cmd); err != nil {
+		return f, err
+	}
+
+	if launchAfter != "" {
+		if f.LaunchAfter, err = time.Parse(config.DateFormat, launchAfter); err != nil {
+			return f, fmt.Errorf("invalid format for --launch-after: '%s'. Use YYYY-MM-DD. (Details: %w)", launchAfter, err)
+		}
+	}
+	if launchBefore != "" {
+		if f.LaunchBefore, err = time.Parse(config.DateFormat, launchBefore); err != nil {
+			return f, fmt.Errorf("invalid format for --launch-before: '%s'. Use YYYY-MM-DD. (Details: %w)", launchBefore, err)
+		}
+	}
+	if !f.LaunchAfter.IsZero() && !f.LaunchBefore.IsZero() && f.LaunchAfter.After(f.LaunchBefore) {
+		return f, fmt.Errorf("--launch-after date (%s) cannot be after --launch-before date (%s)", launchAfter, launchBefore)
+	}
+	if altitudeBand != "" {
+		if f.MinAltitude != 0 || f.MaxAltitude != 0 {
+			return f, fmt.Errorf("--altitude-band cannot be combined with --min-altitude or --max-altitude")
+		}
+		if f.MinAltitude, f.MaxAltitude, err = altitudeBandRange(altitudeBand); err != nil {
+			return f, err
+		}
+	}
+	if f.MinAltitude > 0 && f.MaxAltitude > 0 && f.MinAltitude > f.MaxAltitude {
+		return f, fmt.Errorf("--min-altitude (%.0f) cannot be greater than --max-altitude (%.0f)", f.MinAltitude, f.MaxAltitude)
+	}
+	if f.MinSMA > 0 && f.MaxSMA > 0 && f.MinSMA > f.MaxSMA {
+		return f, fmt.Errorf("--min-sma (%.0f) cannot be greater than --max-sma (%.0f)", f.MinSMA, f.MaxSMA)
+	}
+	if f.MinLongitude < -180 || f.MinLongitude > 180 || f.MaxLongitude < -180 || f.MaxLongitude > 180 {
+		return f, fmt.Errorf("--min-longitude and --max-longitude must be between -180 and 180 degrees")
+	}
+	if f.MinAge < 0 || f.MaxAge < 0 {
+		return f, fmt.Errorf("--min-age and --max-age cannot be negative")
+	}
+	if f.MinAge > 0 && f.MaxAge > 0 && f.MinAge > f.MaxAge {
+		return f, fmt.Errorf("--min-age (%g) cannot be greater than --max-age (%g)", f.MinAge, f.MaxAge)
+	}
+	unit, err := altitudeUnitFromFlags(cmd)
+	if err != nil {
+		return f, err
+	}
+	if altitudeBand == "" {
+		f.MinAltitude, f.MaxAltitude = f.MinAltitude/unit.PerKm, f.MaxAltitude/unit.PerKm // Bands are already in km
+	}
+	f.MinPerigee, f.MaxApogee = f.MinPerigee/unit.PerKm, f.MaxApogee/unit.PerKm
+	return f, nil
+}
+
+// filterClauses describes each active criterion of f for --explain-query.
+func filterClauses(f Filter) []string {
+	clauses := []string{}
+	if f.Operator != "" {
+		clause := fmt.Sprintf("operator is %q", f.Operator)
+		if f.NormalizeOperators {
+			clause += " (spellings normalized through operator-aliases)"
+		}
+		clauses = append(clauses, clause)
+	}
+	for _, c := range []struct{ field, value string }{{"status", f.Status}, {"orbitType", f.OrbitType}} {
+		if c.value != "" {
+			clauses = append(clauses, fmt.Sprintf("%s is %q", c.field, c.value))
+		}
+	}
+	if f.Constellation != nil {
+		clauses = append(clauses, fmt.Sprintf("constellation is %t", *f.Constellation))
+	}
+	if f.Archived == nil {
+		clauses = append(clauses, "archived records included")
+	} else if *f.Archived {
+		clauses = append(clauses, "archived is true")
+	}
+	launchClause := rangeClause("launchDate", formatFilterDate(f.LaunchAfter), formatFilterDate(f.LaunchBefore), "")
+	if launchClause != "" {
+		if f.StrictDates {
+			launchClause += " (unparsable dates excluded)"
+		} else {
+			launchClause += " (unparsable dates included)"
+		}
+	}
+	clauses = append(clauses, launchClause,
+		rangeClause("altitude", bound(f.MinAltitude), bound(f.MaxAltitude), "km"),
+		rangeClause("perigee", bound(f.MinPerigee), "", "km"), rangeClause("apogee", "", bound(f.MaxApogee), "km"),
+		rangeClause("semiMajorAxis", bound(f.MinSMA), bound(f.MaxSMA), "km"),
+		longitudeClause(f.LongitudeFiltered, f.MinLongitude, f.MaxLongitude),
+		rangeClause("age", bound(f.MinAge), bound(f.MaxAge), "years"))
+	for _, key := range sortedKeys(f.Custom) {
+		clauses = append(clauses, fmt.Sprintf("custom.%s is %q", key, f.Custom[key]))
+	}
+	if len(f.Tags) > 0 {
+		mode := "any"
+		if f.AllTags {
+			mode = "all"
+		}
+		clauses = append(clauses, fmt.Sprintf("tags include %s of %q", mode, f.Tags))
+	}
+	return clauses
+}
+
+// formatFilterDate formats a launch date bound, or returns "" if unset.
+func formatFilterDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(config.DateFormat)
+}
@@ -0,0 +1,170 @@
+// cmd/satcli/filter_test.go
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yackko/satcom-code/types"
+)
+
+// filterTestFleet has one satellite per kind of record the criteria tell apart.
+var filterTestFleet = []types.Satellite{
+	{Name: "ISS", OrbitType: "LEO", Altitude: 420, Eccentricity: 0.0005, Operator: "NASA", Status: "active",
+		LaunchDate: "1998-11-20", Tags: []string{"watchlist"}, Custom: map[string]string{"noradId": "25544", "crew": "Yes"}},
+	{Name: "STARLINK-1", OrbitType: "LEO", Altitude: 550, Operator: "Space Exploration Technologies", Status: "active",
+		LaunchDate: "2023-05-01", Constellation: true, Tags: []string{"fleet"}},
+	{Name: "GPS-IIF", OrbitType: "MEO", Altitude: 20200, SemiMajorAxisKm: 26560, Operator: "USSF", Status: "active",
+		LaunchDate: "2014-05-17", Constellation: true},
+	{Name: "ASTRA-1KR", OrbitType: "GEO", Altitude: 35786, Longitude: 19.2, Operator: "SES", Status: "inactive",
+		LaunchDate: "2006-04-20", Tags: []string{"fleet", "watchlist"}},
+	{Name: "INTELSAT-18", OrbitType: "gso", Altitude: 35786, Longitude: -179.5, Operator: "Intelsat", Status: "active",
+		LaunchDate: "2011-10-05"},
+	{Name: "MOLNIYA-1", OrbitType: "HEO", Altitude: 26600, Eccentricity: 0.74, Operator: "Roscosmos", Status: "decayed",
+		LaunchDate: "unknown", Archived: true},
+	{Name: "SLASH-DATE", OrbitType: "LEO", Altitude: 800, Operator: "ESA", Status: "active", LaunchDate: "2010/06/01"},
+}
+
+// filterTestNames returns the names of sats, in order.
+func filterTestNames(sats []types.Satellite) []string {
+	names := []string{}
+	for _, sat := range sats {
+		names = append(names, sat.Name)
+	}
+	return names
+}
+
+func filterTestDate(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestFilterSatellites(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"no criteria", Filter{}, []string{"ISS", "STARLINK-1", "GPS-IIF", "ASTRA-1KR", "INTELSAT-18", "MOLNIYA-1", "SLASH-DATE"}},
+		{"operator, case-insensitive", Filter{Operator: "nasa"}, []string{"ISS"}},
+		{"operator without aliases", Filter{Operator: "SpaceX"}, []string{}},
+		{"status", Filter{Status: "ACTIVE"}, []string{"ISS", "STARLINK-1", "GPS-IIF", "INTELSAT-18", "SLASH-DATE"}},
+		{"orbit type", Filter{OrbitType: "leo"}, []string{"ISS", "STARLINK-1", "SLASH-DATE"}},
+		{"launch after, unparsable included", Filter{LaunchAfter: filterTestDate("2011-01-01")}, []string{"STARLINK-1", "GPS-IIF", "INTELSAT-18", "MOLNIYA-1"}},
+		{"launch before, other layouts parsed", Filter{LaunchBefore: filterTestDate("2010-06-01")}, []string{"ISS", "ASTRA-1KR", "MOLNIYA-1", "SLASH-DATE"}},
+		{"launch range, strict dates", Filter{LaunchAfter: filterTestDate("2006-04-20"), LaunchBefore: filterTestDate("2014-05-17"), StrictDates: true}, []string{"GPS-IIF", "ASTRA-1KR", "INTELSAT-18", "SLASH-DATE"}},
+		{"constellation only", Filter{Constellation: &yes}, []string{"STARLINK-1", "GPS-IIF"}},
+		{"no constellation", Filter{Constellation: &no}, []string{"ISS", "ASTRA-1KR", "INTELSAT-18", "MOLNIYA-1", "SLASH-DATE"}},
+		{"archived only", Filter{Archived: &yes}, []string{"MOLNIYA-1"}},
+		{"archived excluded", Filter{Archived: &no}, []string{"ISS", "STARLINK-1", "GPS-IIF", "ASTRA-1KR", "INTELSAT-18", "SLASH-DATE"}},
+		{"min altitude", Filter{MinAltitude: 20200}, []string{"GPS-IIF", "ASTRA-1KR", "INTELSAT-18", "MOLNIYA-1"}},
+		{"max altitude", Filter{MaxAltitude: 550}, []string{"ISS", "STARLINK-1"}},
+		{"min perigee", Filter{MinPerigee: 2000}, []string{"GPS-IIF", "ASTRA-1KR", "INTELSAT-18", "MOLNIYA-1"}},
+		{"max apogee", Filter{MaxApogee: 40000}, []string{"ISS", "STARLINK-1", "GPS-IIF", "ASTRA-1KR", "INTELSAT-18", "SLASH-DATE"}},
+		{"semi-major axis, recorded or derived", Filter{MinSMA: 26000, MaxSMA: 27000}, []string{"GPS-IIF"}},
+		{"longitude", Filter{LongitudeFiltered: true, MinLongitude: 0, MaxLongitude: 30}, []string{"ASTRA-1KR"}},
+		{"longitude across the antimeridian", Filter{LongitudeFiltered: true, MinLongitude: 170, MaxLongitude: -170}, []string{"INTELSAT-18"}},
+		{"longitude, full range is GEO/GSO only", Filter{LongitudeFiltered: true, MinLongitude: -180, MaxLongitude: 180}, []string{"ASTRA-1KR", "INTELSAT-18"}},
+		{"custom, value case-insensitive", Filter{Custom: map[string]string{"crew": "yes"}}, []string{"ISS"}},
+		{"custom, missing key", Filter{Custom: map[string]string{"crew": "yes", "noradId": "1"}}, []string{}},
+		{"any tag", Filter{Tags: []string{"WATCHLIST", "fleet"}}, []string{"ISS", "STARLINK-1", "ASTRA-1KR"}},
+		{"all tags", Filter{Tags: []string{"watchlist", "fleet"}, AllTags: true}, []string{"ASTRA-1KR"}},
+
+		{"status and orbit type", Filter{Status: "active", OrbitType: "LEO"}, []string{"ISS", "STARLINK-1", "SLASH-DATE"}},
+		{"altitude band and constellation", Filter{MinAltitude: 500, MaxAltitude: 2000, Constellation: &yes}, []string{"STARLINK-1"}},
+		{"tag and status", Filter{Tags: []string{"watchlist"}, Status: "inactive"}, []string{"ASTRA-1KR"}},
+		{"launch range and orbit type", Filter{LaunchAfter: filterTestDate("2000-01-01"), OrbitType: "GEO"}, []string{"ASTRA-1KR"}},
+		{"archived and unparsable dates", Filter{Archived: &yes, LaunchAfter: filterTestDate("2000-01-01"), StrictDates: true}, []string{}},
+		{"every criterion", Filter{
+			Operator: "ses", Status: "inactive", OrbitType: "geo", LaunchAfter: filterTestDate("2000-01-01"),
+			LaunchBefore: filterTestDate("2010-01-01"), StrictDates: true, Constellation: &no, Archived: &no,
+			MinAltitude: 35000, MaxAltitude: 36000, MinPerigee: 35000, MaxApogee: 36000, MinSMA: 42000, MaxSMA: 42500,
+			LongitudeFiltered: true, MinLongitude: 19, MaxLongitude: 20,
+			Custom: map[string]string{}, Tags: []string{"watchlist", "fleet"}, AllTags: true,
+		}, []string{"ASTRA-1KR"}},
+		{"contradictory criteria", Filter{Operator: "NASA", OrbitType: "GEO"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterTestNames(FilterSatellites(filterTestFleet, tt.filter)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// yearsAgo returns the YYYY-MM-DD date about years before today.
+func yearsAgo(years float64) string {
+	return time.Now().AddDate(0, -int(years*12), 0).Format("2006-01-02")
+}
+
+func TestFilterSatellitesByAge(t *testing.T) {
+	fleet := []types.Satellite{
+		{Name: "NEW", OrbitType: "LEO", LaunchDate: yearsAgo(2)},
+		{Name: "MIDDLE", OrbitType: "GEO", LaunchDate: yearsAgo(12)},
+		{Name: "OLD", OrbitType: "GEO", LaunchDate: yearsAgo(25)},
+		{Name: "UNDATED", OrbitType: "LEO", LaunchDate: "unknown"},
+		{Name: "OTHER-LAYOUT", OrbitType: "LEO", LaunchDate: time.Now().AddDate(-5, 0, 0).Format("2006/01/02")},
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"min age", Filter{MinAge: 10}, []string{"MIDDLE", "OLD"}},
+		{"max age", Filter{MaxAge: 10}, []string{"NEW"}},
+		{"age range", Filter{MinAge: 5, MaxAge: 20}, []string{"MIDDLE"}},
+		{"age and orbit type", Filter{MinAge: 1, OrbitType: "LEO"}, []string{"NEW"}},
+		{"age and launch range", Filter{MaxAge: 30, LaunchAfter: time.Now().AddDate(-20, 0, 0)}, []string{"NEW", "MIDDLE"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterTestNames(FilterSatellites(fleet, tt.filter)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterSatellitesNormalizesOperators(t *testing.T) {
+	if err := types.SetOperatorAliases(map[string][]string{"SpaceX": {"Space Exploration Technologies"}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { types.SetOperatorAliases(nil) })
+	got := filterTestNames(FilterSatellites(filterTestFleet, Filter{Operator: "SpaceX", NormalizeOperators: true}))
+	if want := []string{"STARLINK-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("matched %v, want %v", got, want)
+	}
+	if got := FilterSatellites(filterTestFleet, Filter{Operator: "SpaceX"}); len(got) != 0 {
+		t.Errorf("matched %v without NormalizeOperators", filterTestNames(got))
+	}
+}
+
+func TestUnparsableDateReport(t *testing.T) {
+	yes := true
+	tests := []struct {
+		name   string
+		filter Filter
+		want   FilterReport
+	}{
+		{"no date criteria", Filter{}, FilterReport{}},
+		{"dates included", Filter{LaunchAfter: filterTestDate("2000-01-01")}, FilterReport{UndatedNames: []string{"MOLNIYA-1"}}},
+		{"dates excluded", Filter{LaunchAfter: filterTestDate("2000-01-01"), StrictDates: true}, FilterReport{SkippedDates: 1}},
+		{"only records the other criteria match", Filter{LaunchAfter: filterTestDate("2000-01-01"), Constellation: &yes}, FilterReport{}},
+		{"ages need YYYY-MM-DD", Filter{MinAge: 1}, FilterReport{SkippedAges: 2}},
+		{"age after the date range", Filter{LaunchBefore: filterTestDate("2009-01-01"), MaxAge: 100}, FilterReport{SkippedAges: 1}},
+		{"strict dates before ages", Filter{LaunchAfter: filterTestDate("2000-01-01"), StrictDates: true, MinAge: 1}, FilterReport{SkippedDates: 1, SkippedAges: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unparsableDateReport(filterTestFleet, tt.filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("report = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
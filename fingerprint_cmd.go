@@ -0,0 +1,60 @@
+// cmd/satcli/fingerprint_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+var fingerprintCmd = &cobra.Command{
+	Use:   "fingerprint",
+	Short: "Print a SHA-256 fingerprint of the datastore contents",
+	Long: `Prints the SHA-256 of the decrypted records in canonical (name-sorted) form. The encrypted file
+changes on every save because of its fresh salt and nonce; the fingerprint changes only when the
+data does, so automation can compare it to detect real changes. No satellite data is printed.
+
+Examples:
+  satcli fingerprint
+  satcli fingerprint --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+		fingerprint, err := datastore.Fingerprint()
+		if err != nil {
+			return err
+		}
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+
+		if strings.ToLower(outputFormat) == "json" {
+			output, errJson := json.MarshalIndent(struct {
+				Algorithm   string `json:"algorithm"`
+				Fingerprint string `json:"fingerprint"`
+				Records     int    `json:"records"`
+			}{"sha256", fingerprint, len(satsMap)}, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal fingerprint to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), fingerprint)
+		return nil
+	},
+}
+
+func init() {
+	fingerprintCmd.Flags().StringP("output", "O", "hex", "Output format: hex or json")
+	rootCmd.AddCommand(fingerprintCmd)
+}
@@ -0,0 +1,70 @@
+// cmd/satcli/get_cmd.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get [name...]",
+	Short: "Show one or more satellite records from the secure datastore",
+	Long: `Retrieves the named satellites and renders them together in the chosen output format.
+Names are matched ignoring case unless that matches several records, which is an error.
+Names that are not found are reported on stderr and skipped, unless --strict is set.
+--output kv prints one key=value line per field (custom attributes as custom.<key>=value, tags
+joined with ";"), with a blank line between records, for reading with shell tools; backslashes and
+line breaks in values are escaped as \\, \n and \r.
+
+Examples:
+  satcli get ISS
+  satcli get ISS Hubble Starlink-1007 --output table
+  satcli get ISS --output kv | grep '^altitude='`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		strict, _ := cmd.Flags().GetBool("strict")
+		cmd.SilenceUsage = true
+
+		var found []types.Satellite
+		var missing []string
+		for _, name := range args {
+			sat, ok, err := lookupSatellite(satsMap, name)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				missing = append(missing, name)
+				continue
+			}
+			found = append(found, sat)
+		}
+		for _, name := range missing {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Not found: %s\n", name)
+		}
+		if strict && len(missing) > 0 {
+			return &exitCodeError{exitNotFound, fmt.Errorf("%d of %d satellite(s) not found", len(missing), len(args))}
+		}
+		if len(found) == 0 {
+			return fmt.Errorf("no matching satellites found")
+		}
+		return renderSatellites(cmd, found)
+	},
+}
+
+func init() {
+	getCmd.Flags().StringP("output", "O", "json", "Output format: json, ndjson, kv, table, markdown, tree, or tui")
+	getCmd.Flags().Bool("strict", false, "Fail if any named satellite is not found")
+	addTableColumnFlags(getCmd)
+	rootCmd.AddCommand(getCmd)
+}
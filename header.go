@@ -0,0 +1,111 @@
+// internal/datastore/header.go
+package datastore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/crypto"
+)
+
+// File layouts:
+//
+//	version 1: magic "SATC" | 1 | KDF id | salt | nonce+ciphertext            (AES-GCM)
+//	version 2: magic "SATC" | 2 | KDF id | cipher id | [Argon2id params] | salt | nonce+ciphertext
+//
+// The Argon2id params (time and memory in KiB as big-endian uint32s, then threads) are present
+// only for KDF id 3. AES-GCM stores with a fixed-parameter KDF are still written as version 1 so
+// older satcli builds can read them.
+// Files written before the header existed start directly with the salt and are
+// always Argon2id with AES-GCM.
+var fileMagic = []byte("SATC")
+
+const (
+	headerVersion1   = 1
+	headerVersion2   = 2
+	headerSizeV1     = 6 // magic + version + KDF id
+	headerSizeV2     = 7 // magic + version + KDF id + cipher id
+	argon2ParamsSize = 9 // time + memory + threads
+)
+
+// fileFormat is the pair of algorithms recorded in a datastore file header.
+type fileFormat struct {
+	KDF    crypto.KeyDeriver
+	Cipher crypto.Cipher
+}
+
+// buildHeader returns the header bytes recording f.
+func buildHeader(f fileFormat) []byte {
+	h := make([]byte, 0, headerSizeV2+argon2ParamsSize)
+	h = append(h, fileMagic...)
+	params, hasParams := f.KDF.(crypto.Argon2idParamsKDF)
+	if f.Cipher.ID() == crypto.CipherAESGCM && !hasParams {
+		return append(h, headerVersion1, byte(f.KDF.ID()))
+	}
+	h = append(h, headerVersion2, byte(f.KDF.ID()), byte(f.Cipher.ID()))
+	if hasParams {
+		h = binary.BigEndian.AppendUint32(h, params.Params.Time)
+		h = binary.BigEndian.AppendUint32(h, params.Params.MemoryKiB)
+		h = append(h, params.Params.Threads)
+	}
+	return h
+}
+
+// parseHeader splits an encrypted datastore file into its format, salt and
+// nonce+ciphertext payload, falling back to the legacy headerless layout.
+func parseHeader(fileBytes []byte) (fileFormat, []byte, []byte, error) {
+	format := fileFormat{KDF: crypto.Argon2idKDF{}, Cipher: crypto.AESGCMCipher{}} // legacy files
+	body := fileBytes
+	if len(fileBytes) >= headerSizeV1 && bytes.Equal(fileBytes[:len(fileMagic)], fileMagic) {
+		version := fileBytes[len(fileMagic)]
+		kdfID := crypto.KDFID(fileBytes[len(fileMagic)+1])
+		var err error
+		if kdfID != crypto.KDFArgon2idParams {
+			format.KDF, err = crypto.KDFByID(kdfID)
+			if err != nil {
+				return fileFormat{}, nil, nil, err
+			}
+		}
+		switch version {
+		case headerVersion1:
+			if kdfID == crypto.KDFArgon2idParams {
+				return fileFormat{}, nil, nil, fmt.Errorf("encrypted datastore file header is invalid: version 1 cannot hold Argon2id parameters")
+			}
+			body = fileBytes[headerSizeV1:]
+		case headerVersion2:
+			if len(fileBytes) < headerSizeV2 {
+				return fileFormat{}, nil, nil, fmt.Errorf("encrypted datastore file header is truncated")
+			}
+			format.Cipher, err = crypto.CipherByID(crypto.CipherID(fileBytes[len(fileMagic)+2]))
+			if err != nil {
+				return fileFormat{}, nil, nil, err
+			}
+			body = fileBytes[headerSizeV2:]
+			if kdfID == crypto.KDFArgon2idParams {
+				if len(body) < argon2ParamsSize {
+					return fileFormat{}, nil, nil, fmt.Errorf("encrypted datastore file header is truncated")
+				}
+				params := crypto.Argon2Params{
+					Time:      binary.BigEndian.Uint32(body[0:4]),
+					MemoryKiB: binary.BigEndian.Uint32(body[4:8]),
+					Threads:   body[8],
+				}
+				if err := params.Validate(); err != nil {
+					return fileFormat{}, nil, nil, fmt.Errorf("encrypted datastore file header is invalid: %w", err)
+				}
+				format.KDF = crypto.Argon2idParamsKDF{Params: params}
+				body = body[argon2ParamsSize:]
+			}
+		default:
+			return fileFormat{}, nil, nil, fmt.Errorf("unsupported datastore file version %d", version)
+		}
+	}
+
+	// AES-GCM and ChaCha20-Poly1305 both use 12-byte nonces.
+	if len(body) < (config.Argon2SaltSize + config.AESGCMNonceSize) {
+		return fileFormat{}, nil, nil, fmt.Errorf("encrypted datastore file is too short or corrupted (salt+nonce sections missing)")
+	}
+	return format, body[:config.Argon2SaltSize], body[config.Argon2SaltSize:], nil
+}
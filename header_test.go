@@ -0,0 +1,74 @@
+// internal/datastore/header_test.go
+package datastore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/crypto"
+)
+
+func TestHeaderRoundTrip(t *testing.T) {
+	params := crypto.Argon2Params{Time: 3, MemoryKiB: 256 * 1024, Threads: 4}
+	tests := []struct {
+		name        string
+		format      fileFormat
+		wantVersion byte
+	}{
+		{"argon2id aes-gcm", fileFormat{crypto.Argon2idKDF{}, crypto.AESGCMCipher{}}, headerVersion1},
+		{"scrypt chacha20", fileFormat{crypto.ScryptKDF{}, crypto.ChaCha20Poly1305Cipher{}}, headerVersion2},
+		{"argon2id params aes-gcm", fileFormat{crypto.Argon2idParamsKDF{Params: params}, crypto.AESGCMCipher{}}, headerVersion2},
+		{"argon2id params chacha20", fileFormat{crypto.Argon2idParamsKDF{Params: params}, crypto.ChaCha20Poly1305Cipher{}}, headerVersion2},
+	}
+	salt := bytes.Repeat([]byte{1}, config.Argon2SaltSize)
+	payload := bytes.Repeat([]byte{2}, config.AESGCMNonceSize+16)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := append(append(buildHeader(tt.format), salt...), payload...)
+			if got := file[len(fileMagic)]; got != tt.wantVersion {
+				t.Errorf("version = %d, want %d", got, tt.wantVersion)
+			}
+			format, gotSalt, gotPayload, err := parseHeader(file)
+			if err != nil {
+				t.Fatalf("parseHeader: %v", err)
+			}
+			if format.KDF != tt.format.KDF || format.Cipher.ID() != tt.format.Cipher.ID() {
+				t.Errorf("format = %+v, want %+v", format, tt.format)
+			}
+			if !bytes.Equal(gotSalt, salt) || !bytes.Equal(gotPayload, payload) {
+				t.Errorf("salt or payload not split at the header's end")
+			}
+		})
+	}
+}
+
+func TestParseHeaderRejectsBadArgon2Params(t *testing.T) {
+	header := func(timeCost, memoryKiB uint32, threads byte) []byte {
+		h := append([]byte{}, fileMagic...)
+		h = append(h, headerVersion2, byte(crypto.KDFArgon2idParams), byte(crypto.CipherAESGCM))
+		h = binary.BigEndian.AppendUint32(h, timeCost)
+		h = binary.BigEndian.AppendUint32(h, memoryKiB)
+		return append(h, threads)
+	}
+	body := make([]byte, config.Argon2SaltSize+config.AESGCMNonceSize+16)
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{"zero time", append(header(0, 65536, 1), body...)},
+		{"zero threads", append(header(1, 65536, 0), body...)},
+		{"memory below 8 KiB per thread", append(header(1, 16, 4), body...)},
+		{"memory above the limit", append(header(1, 1<<31, 1), body...)},
+		{"truncated params", header(1, 65536, 1)[:headerSizeV2+4]},
+		{"params in version 1", append([]byte{'S', 'A', 'T', 'C', headerVersion1, byte(crypto.KDFArgon2idParams)}, body...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := parseHeader(tt.file); err == nil {
+				t.Errorf("parseHeader accepted %s", tt.name)
+			}
+		})
+	}
+}
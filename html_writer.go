@@ -0,0 +1,132 @@
+// cmd/satcli/html_writer.go
+package main
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/yackko/satcom-code/types"
+)
+
+// defaultHTMLTitle is the report heading when --title is not given.
+const defaultHTMLTitle = "Satellite fleet report"
+
+// htmlReportTemplate is a self-contained page: inline styles, and a small script that sorts the
+// table by the clicked header (numerically for data-numeric columns), toggling the direction.
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+h1 { margin-bottom: 0.25rem; }
+.generated { color: #666; margin-top: 0; }
+.summary { display: flex; flex-wrap: wrap; gap: 0.5rem; margin: 1rem 0; padding: 0; list-style: none; }
+.summary li { background: #eef2f7; border-radius: 4px; padding: 0.25rem 0.75rem; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.35rem 0.6rem; text-align: left; }
+th { background: #d9d9d9; cursor: pointer; user-select: none; position: sticky; top: 0; }
+th[aria-sort="ascending"]::after { content: " \25B2"; }
+th[aria-sort="descending"]::after { content: " \25BC"; }
+td.num { text-align: right; }
+tbody tr:nth-child(even) { background: #f7f7f7; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="generated">{{.Total}} satellite(s), generated {{.Generated}}</p>
+<ul class="summary">
+{{- range .OrbitCounts}}
+<li><strong>{{.OrbitType}}</strong>: {{.Count}}</li>
+{{- end}}
+</ul>
+<table id="satellites">
+<thead><tr>
+{{- range .Columns}}
+<th{{if .Numeric}} data-numeric="1"{{end}}>{{.Header}}</th>
+{{- end}}
+</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{range .}}<td{{if .Numeric}} class="num"{{end}}>{{.Text}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+document.querySelectorAll("#satellites th").forEach(function (th, col) {
+  th.addEventListener("click", function () {
+    var asc = th.getAttribute("aria-sort") !== "ascending";
+    var numeric = th.hasAttribute("data-numeric");
+    var body = th.closest("table").tBodies[0];
+    var rows = Array.prototype.slice.call(body.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[col].textContent, y = b.cells[col].textContent;
+      var d = numeric ? (parseFloat(x) || 0) - (parseFloat(y) || 0) : x.localeCompare(y, undefined, {numeric: true});
+      return asc ? d : -d;
+    });
+    rows.forEach(function (r) { body.appendChild(r); });
+    th.parentNode.querySelectorAll("th").forEach(function (h) { h.removeAttribute("aria-sort"); });
+    th.setAttribute("aria-sort", asc ? "ascending" : "descending");
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+type htmlCell struct {
+	Text    string
+	Numeric bool
+}
+
+type htmlColumn struct {
+	Header  string
+	Numeric bool
+}
+
+type orbitCount struct {
+	OrbitType string
+	Count     int
+}
+
+// writeHTML writes a self-contained HTML report: title, per-orbit-type counts of sats, and a sortable
+// table of columns and rows. Field values are escaped by html/template.
+func writeHTML(w io.Writer, title string, columns []tableColumn, rows [][]string, sats []types.Satellite) error {
+	if title == "" {
+		title = defaultHTMLTitle
+	}
+	counts := make(map[string]int)
+	for _, sat := range sats {
+		orbitType := sat.OrbitType
+		if orbitType == "" {
+			orbitType = "(none)"
+		}
+		counts[orbitType]++
+	}
+	data := struct {
+		Title       string
+		Generated   string
+		Total       int
+		OrbitCounts []orbitCount
+		Columns     []htmlColumn
+		Rows        [][]htmlCell
+	}{Title: title, Generated: time.Now().Format("2006-01-02 15:04 MST"), Total: len(sats)}
+	for _, orbitType := range sortedKeys(counts) {
+		data.OrbitCounts = append(data.OrbitCounts, orbitCount{orbitType, counts[orbitType]})
+	}
+	sort.SliceStable(data.OrbitCounts, func(i, j int) bool { return data.OrbitCounts[i].Count > data.OrbitCounts[j].Count })
+	for _, col := range columns {
+		data.Columns = append(data.Columns, htmlColumn{col.Header, numericColumnKeys[col.Key]})
+	}
+	for _, row := range rows {
+		cells := make([]htmlCell, len(row))
+		for i, text := range row {
+			cells[i] = htmlCell{text, numericColumnKeys[columns[i].Key]}
+		}
+		data.Rows = append(data.Rows, cells)
+	}
+	return htmlReportTemplate.Execute(w, data)
+}
@@ -0,0 +1,389 @@
+// cmd/satcli/import_cmd.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import satellite records from a JSON, CSV, YAML, TLE or protobuf file",
+	Long: `Reads satellite records from --file and adds them in a single save. The file may be a JSON array of
+satellite objects (the format of 'satcli list'), a YAML list with the same keys, CSV with a header row
+of those keys (custom.<key> columns set custom attributes), a protobuf file from 'satcli export
+--format protobuf', or TLE text, applied to stored records as 'satcli import-tle' does. The format is taken from the file extension or, failing that, detected
+from the content; --format overrides both, and --log-level info reports the choice. --file - reads
+standard input, whose format is always detected from the content unless --format is given.
+Records with a semiMajorAxis (km) but no altitude get the altitude it implies; when both are given the
+altitude is kept, with a warning if they disagree by more than 5 km.
+Operator names are normalized through the config file's operator-aliases (see 'satcli operators --aliases').
+Records with an existing name replace the stored record. Nothing is stored if any record is invalid.
+With --validate-schema, each record is also checked against 'satcli schema' and every violation is reported.
+Keys that are not satellite fields are ignored unless --strict-fields is given, which rejects the record
+and names the key, catching typos such as "altidude".
+
+With --dedupe-on-import, only one record per name is kept across the file and the datastore, chosen
+by --prefer: 'newer' keeps the later launch date, 'existing' keeps the record seen first (the stored
+one, then the earliest in the file), and 'incoming' keeps the last one in the file. Ties under
+'newer' keep the record seen first. The number of collapsed duplicates is reported.
+
+--json-stream reads a JSON array one record at a time and adds each to the datastore as soon as
+it is checked, so neither the file nor a second copy of its records is held in memory; with
+--log-level info, progress is logged every 10000 records. A file whose top level is not an array
+is read as usual. It implies --format json and cannot be combined with --dedupe-on-import, which
+needs every record before choosing. Nothing is saved unless every record is valid. The datastore
+is still saved as one encrypted document, which takes memory in proportion to all stored records.
+
+Examples:
+  satcli import --file satellites.json
+  satcli import --file fleet.csv
+  satcli import --file export.dat --format yaml
+  satcli import --file satellites.json --validate-schema
+  satcli import --file satellites.json --strict-fields
+  satcli import --file more.json --dedupe-on-import --prefer existing
+  satcli import --file huge.json --json-stream
+  curl -s https://example.org/fleet.csv | satcli import --file -`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		path, _ := cmd.Flags().GetString("file")
+		validateSchema, _ := cmd.Flags().GetBool("validate-schema")
+		strictFields, _ := cmd.Flags().GetBool("strict-fields")
+		dedupe, _ := cmd.Flags().GetBool("dedupe-on-import")
+		prefer, _ := cmd.Flags().GetString("prefer")
+		format, _ := cmd.Flags().GetString("format")
+		stream, _ := cmd.Flags().GetBool("json-stream")
+		cmd.SilenceUsage = true
+		format = strings.ToLower(format)
+		if format != "" && !containsString(importFormats, format) {
+			return fmt.Errorf("invalid value for --format: '%s'. Use one of: %s", format, strings.Join(importFormats, ", "))
+		}
+		if cmd.Flags().Changed("prefer") && !dedupe {
+			return fmt.Errorf("--prefer requires --dedupe-on-import")
+		}
+		if !containsString(dedupePreferences, prefer) {
+			return fmt.Errorf("invalid value for --prefer: '%s'. Use one of: %s", prefer, strings.Join(dedupePreferences, ", "))
+		}
+		if stream {
+			if format != "" && format != "json" {
+				return fmt.Errorf("--json-stream reads JSON only, not --format %s", format)
+			}
+			if dedupe {
+				return fmt.Errorf("--json-stream cannot be combined with --dedupe-on-import")
+			}
+		}
+
+		in, source, size, closeInput, err := openInputFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read import file: %w", err)
+		}
+		defer closeInput()
+
+		schema := types.SatelliteSchema()
+		failed, normalized := 0, 0
+		// checkRecord decodes and validates record i, reporting any problem on stderr.
+		checkRecord := func(p *progress, i int, raw json.RawMessage) (types.Satellite, bool) {
+			if validateSchema {
+				var generic interface{}
+				_ = json.Unmarshal(raw, &generic) // already valid JSON as part of the array
+				if violations := schema.Validate(generic); len(violations) > 0 {
+					p.Clear()
+					for _, v := range violations {
+						fmt.Fprintf(cmd.ErrOrStderr(), "record %d: %v\n", i, v)
+					}
+					failed++
+					return types.Satellite{}, false
+				}
+			}
+			sat, err := decodeImportRecord(raw, strictFields)
+			if err != nil {
+				p.Clear()
+				fmt.Fprintf(cmd.ErrOrStderr(), "record %d: %v\n", i, err)
+				failed++
+				return sat, false
+			}
+			if operator := types.NormalizeOperator(sat.Operator); operator != sat.Operator {
+				sat.Operator = operator
+				normalized++
+			}
+			if err := validateSatellite(sat); err != nil {
+				p.Clear()
+				fmt.Fprintf(cmd.ErrOrStderr(), "record %d: %v\n", i, err)
+				failed++
+				return sat, false
+			}
+			sat, drift := sat.AltitudeFromSemiMajorAxis()
+			if math.Abs(drift) > types.SemiMajorAxisToleranceKm {
+				p.Clear()
+				noticef("Warning: record %d ('%s'): altitude %.1f km differs by %.1f km from the %.1f km implied by semiMajorAxis; keeping the altitude.\n",
+					i, sat.Name, sat.Altitude, drift, sat.Altitude-drift)
+			}
+			return sat, true
+		}
+
+		detectedBy := "--format"
+		if stream {
+			imported, rest, err := streamImportJSON(cmd, in, size, source, func(p *progress, i int, raw json.RawMessage) error {
+				sat, ok := checkRecord(p, i, raw)
+				if !ok || failed > 0 {
+					return nil // Keep reporting invalid records; nothing is saved
+				}
+				return datastore.AddSatellite(sat)
+			})
+			if err != nil {
+				return err
+			}
+			if rest == nil {
+				if failed > 0 {
+					return fmt.Errorf("%d record(s) failed validation; no records were imported", failed)
+				}
+				if imported == 0 {
+					return fmt.Errorf("no satellite records found in %s", source)
+				}
+				if normalized > 0 {
+					noticef("Notice: normalized the operator of %d record(s) using operator-aliases.\n", normalized)
+				}
+				if err := datastore.SaveCtx(cmd.Context()); err != nil {
+					return fmt.Errorf("failed to save %d imported record(s): %w", imported, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Records imported: %d (encrypted in datastore)\n", imported)
+				return nil
+			}
+			logger.Info("import file is not a JSON array; reading it in batch mode", "file", source)
+			format, detectedBy, in = "json", "--json-stream", rest
+		}
+
+		data, err := io.ReadAll(in)
+		if err != nil {
+			return fmt.Errorf("failed to read import file: %w", err)
+		}
+		if format == "" {
+			if format, detectedBy, err = detectImportFormat(path, data); err != nil {
+				return err
+			}
+		}
+		logger.Info("import format selected", "file", source, "format", format, "by", detectedBy)
+
+		var rawRecords []json.RawMessage
+		switch format {
+		case "json":
+			if err := json.Unmarshal(data, &rawRecords); err != nil {
+				return fmt.Errorf("import file %s is not a JSON array of satellites: %w", source, err)
+			}
+		case "yaml":
+			if rawRecords, err = yamlToRecords(data); err != nil {
+				return fmt.Errorf("import file %s: %w", source, err)
+			}
+		case "csv":
+			if rawRecords, err = csvToRecords(data); err != nil {
+				return fmt.Errorf("import file %s: %w", source, err)
+			}
+		case "protobuf":
+			if rawRecords, err = protobufToRecords(data); err != nil {
+				return fmt.Errorf("import file %s: %w", source, err)
+			}
+		case "tle":
+			existing, err := datastore.GetSatellitesCtx(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get satellites: %w", err)
+			}
+			rawRecords, failed, err = tleToRecords(data, existing, func(at tleLine, reason error) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "skipped block at line %d (byte %d): %v\n", at.Line, at.Offset, reason)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to read TLEs from %s: %w", source, err)
+			}
+		}
+
+		var sats []types.Satellite
+		p := newProgress(cmd.ErrOrStderr(), "Importing", int64(len(rawRecords)))
+		for i, raw := range rawRecords {
+			p.Update(i+1, int64(i+1))
+			if sat, ok := checkRecord(p, i, raw); ok {
+				sats = append(sats, sat)
+			}
+		}
+		p.Clear()
+		if failed > 0 {
+			return fmt.Errorf("%d record(s) failed validation; no records were imported", failed)
+		}
+		if len(sats) == 0 {
+			return fmt.Errorf("no satellite records found in %s", source)
+		}
+		if normalized > 0 {
+			noticef("Notice: normalized the operator of %d record(s) using operator-aliases.\n", normalized)
+		}
+		collapsed := 0
+		if dedupe {
+			existing, err := datastore.GetSatellitesCtx(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get satellites: %w", err)
+			}
+			sats, collapsed = dedupeByName(sats, existing, prefer)
+			fmt.Fprintf(cmd.OutOrStdout(), "Duplicates collapsed: %d (--prefer %s)\n", collapsed, prefer)
+			if len(sats) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "Records imported: 0 (stored records kept)")
+				return nil
+			}
+		}
+		for _, sat := range sats {
+			if err := datastore.AddSatellite(sat); err != nil {
+				return err
+			}
+		}
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to save %d imported record(s): %w", len(sats), err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Records imported: %d (encrypted in datastore)\n", len(sats))
+		return nil
+	},
+}
+
+// streamLogInterval is how many records --json-stream reads between progress log lines.
+const streamLogInterval = 10000
+
+// streamImportJSON reads the JSON array from in (size bytes, if known) one element at a time,
+// passing each to add with its index, and returns how many it read. If the top level is not an
+// array it reads no record and returns a reader of the whole input, so the caller can fall back
+// to reading it as usual; even standard input is not lost. An error from add stops it.
+func streamImportJSON(cmd *cobra.Command, in io.Reader, size int64, source string, add func(p *progress, i int, raw json.RawMessage) error) (int, io.Reader, error) {
+	counter := &countingReader{r: in}
+	br := bufio.NewReader(counter)
+	var skipped bytes.Buffer // Leading whitespace, given back with the rest of the input
+	first, err := br.Peek(1)
+	for err == nil && strings.IndexByte(" \t\r\n", first[0]) >= 0 {
+		skipped.WriteByte(first[0])
+		br.Discard(1)
+		first, err = br.Peek(1)
+	}
+	if err != nil || first[0] != '[' {
+		return 0, io.MultiReader(&skipped, br), nil
+	}
+	dec := json.NewDecoder(br)
+	dec.Token() // The '[' peeked above
+	logger.Info("import format selected", "file", source, "format", "json", "by", "--json-stream")
+
+	p := newProgress(cmd.ErrOrStderr(), "Importing", size)
+	defer p.Clear()
+	count := 0
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			p.Clear()
+			return count, nil, fmt.Errorf("import file %s is not a JSON array of satellites: record %d: %w", source, count, err)
+		}
+		if err := add(p, count, raw); err != nil {
+			return count, nil, err
+		}
+		count++
+		p.Update(count, counter.n)
+		if count%streamLogInterval == 0 {
+			logger.Info("import progress", "records", count, "bytes", counter.n, "size", size)
+		}
+	}
+	if _, err := dec.Token(); err != nil { // closing ']'
+		p.Clear()
+		return count, nil, fmt.Errorf("import file %s is not a JSON array of satellites: %w", source, err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		p.Clear()
+		return count, nil, fmt.Errorf("import file %s has data after the JSON array", source)
+	}
+	return count, nil, nil
+}
+
+// decodeImportRecord decodes one import record. With strict, a key that is not a Satellite field is
+// an error naming the key rather than being dropped.
+func decodeImportRecord(raw json.RawMessage, strict bool) (types.Satellite, error) {
+	var sat types.Satellite
+	if !strict {
+		return sat, json.Unmarshal(raw, &sat)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&sat); err != nil {
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return sat, fmt.Errorf("unknown field %s (rejected by --strict-fields)", field)
+		}
+		return sat, err
+	}
+	return sat, nil
+}
+
+// dedupePreferences lists the accepted --prefer values.
+var dedupePreferences = []string{"newer", "existing", "incoming"}
+
+// dedupeByName keeps one record per name across existing and incoming (in file order) according to
+// prefer, returning the incoming records that win, in file order, and the number of duplicates dropped.
+func dedupeByName(incoming []types.Satellite, existing map[string]types.Satellite, prefer string) ([]types.Satellite, int) {
+	held := make(map[string]types.Satellite)
+	winner := make(map[string]int) // index into incoming, or -1 when the stored record wins
+	collapsed := 0
+	for i, sat := range incoming {
+		current, seen := held[sat.Name]
+		if !seen {
+			if current, seen = existing[sat.Name]; seen {
+				winner[sat.Name] = -1
+			}
+		}
+		if seen {
+			collapsed++
+			if !preferIncoming(prefer, current, sat) {
+				held[sat.Name] = current
+				continue
+			}
+		}
+		held[sat.Name] = sat
+		winner[sat.Name] = i
+	}
+
+	var kept []types.Satellite
+	for i, sat := range incoming {
+		if winner[sat.Name] == i {
+			kept = append(kept, sat)
+		}
+	}
+	return kept, collapsed
+}
+
+// preferIncoming reports whether candidate replaces current under the --prefer rule. For 'newer', a
+// parsable launch date beats a missing or unparsable one, and ties keep current.
+func preferIncoming(prefer string, current, candidate types.Satellite) bool {
+	switch prefer {
+	case "existing":
+		return false
+	case "incoming":
+		return true
+	}
+	currentDate, errCurrent := parseLaunchDate(current.LaunchDate)
+	candidateDate, errCandidate := parseLaunchDate(candidate.LaunchDate)
+	if errCandidate != nil {
+		return false
+	}
+	return errCurrent != nil || candidateDate.After(currentDate)
+}
+
+func init() {
+	importCmd.Flags().String("file", "", "Path to a JSON, CSV, YAML or TLE file of satellite records, or - for standard input")
+	importCmd.Flags().String("format", "", "File format: "+strings.Join(importFormats, ", ")+" (default: from the extension or content)")
+	importCmd.Flags().Bool("validate-schema", false, "Validate each record against the satellite JSON Schema (see 'satcli schema')")
+	importCmd.Flags().Bool("strict-fields", false, "Reject records with keys that are not satellite fields instead of ignoring them")
+	importCmd.Flags().Bool("dedupe-on-import", false, "Keep only one record per name across the file and the datastore (see --prefer)")
+	importCmd.Flags().String("prefer", "newer", "Which duplicate to keep with --dedupe-on-import: "+strings.Join(dedupePreferences, ", "))
+	importCmd.Flags().Bool("json-stream", false, "Read a JSON array one record at a time to import very large files with bounded memory")
+	_ = importCmd.MarkFlagRequired("file")
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,280 @@
+// cmd/satcli/import_cmd_test.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/crypto"
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// streamRecords runs streamImportJSON over input, returning the records passed to add.
+func streamRecords(input string) (records []string, n int, rest io.Reader, err error) {
+	n, rest, err = streamImportJSON(&cobra.Command{}, strings.NewReader(input), int64(len(input)), "test.json",
+		func(_ *progress, i int, raw json.RawMessage) error {
+			records = append(records, string(raw))
+			return nil
+		})
+	return records, n, rest, err
+}
+
+func TestStreamImportJSONReadsArrays(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{`[{"name":"A"},{"name":"B"}]`, []string{`{"name":"A"}`, `{"name":"B"}`}},
+		{" \n\t[ {\"name\":\"A\"} ]\n\n", []string{`{"name":"A"}`}},
+		{`[]`, nil},
+	}
+	for _, tt := range tests {
+		records, n, rest, err := streamRecords(tt.input)
+		if err != nil || rest != nil {
+			t.Errorf("%q: rest = %v, err = %v; want an array read to the end", tt.input, rest, err)
+			continue
+		}
+		if n != len(tt.want) || strings.Join(records, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%q: read %d record(s) %q, want %q", tt.input, n, records, tt.want)
+		}
+	}
+}
+
+func TestStreamImportJSONFallsBackForNonArrays(t *testing.T) {
+	for _, input := range []string{
+		`{"name":"A"}`,
+		" \r\n\t{\"name\":\"A\"}\n",
+		"name,orbitType\nA,LEO\n",
+		"   ",
+		"",
+	} {
+		records, n, rest, err := streamRecords(input)
+		if err != nil || rest == nil || n != 0 || records != nil {
+			t.Errorf("%q: read %d record(s), rest = %v, err = %v; want a fallback", input, n, rest, err)
+			continue
+		}
+		if got, err := io.ReadAll(rest); err != nil || string(got) != input {
+			t.Errorf("%q: rest reads %q, %v; want the whole input", input, got, err)
+		}
+	}
+}
+
+func TestStreamImportJSONRejectsMalformedArrays(t *testing.T) {
+	tests := []struct {
+		name, input, wantErr string
+	}{
+		{"data after the array", `[{"name":"A"}] {"name":"B"}`, "data after the JSON array"},
+		{"second array", `[{"name":"A"}][]`, "data after the JSON array"},
+		{"truncated record", `[{"name":"A"},{"name":`, "record 1"},
+		{"missing closing bracket", `[{"name":"A"}`, "not a JSON array"},
+		{"trailing comma", `[{"name":"A"},`, "not a JSON array"},
+		{"invalid record", `[{"name":"A"},nope]`, "record 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, rest, err := streamRecords(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want one containing %q", err, tt.wantErr)
+			}
+			if rest != nil {
+				t.Error("returned a fallback reader for an array")
+			}
+		})
+	}
+}
+
+func TestStreamImportJSONStopsOnAddError(t *testing.T) {
+	stop := errors.New("stop")
+	input := `[{"name":"A"},{"name":"B"},{"name":"C"}]`
+	calls := 0
+	n, _, err := streamImportJSON(&cobra.Command{}, strings.NewReader(input), 0, "test.json",
+		func(_ *progress, i int, _ json.RawMessage) error {
+			calls++
+			if i == 1 {
+				return stop
+			}
+			return nil
+		})
+	if !errors.Is(err, stop) || n != 1 || calls != 2 {
+		t.Errorf("n = %d after %d call(s), err = %v; want 1 after 2, stop", n, calls, err)
+	}
+}
+
+// newImportTestStore creates a datastore in a temporary directory holding the FIRST satellite,
+// with cheap key derivation, and returns its path.
+func newImportTestStore(t *testing.T) string {
+	t.Helper()
+	t.Setenv(config.PassphraseEnvVar, "Correct-Horse-9-battery")
+	datastore.SetNoticeWriter(io.Discard)
+	out := noticeOut
+	noticeOut = io.Discard
+	t.Cleanup(func() {
+		noticeOut = out
+		datastore.SetNewStoreKDF("argon2id")
+		datastore.SetPath("")
+	})
+	path := filepath.Join(t.TempDir(), "satellites.dat")
+	datastore.SetPath(path)
+	datastore.SetNewStoreKDF("argon2id")
+	if err := datastore.SetNewStoreArgon2Params(&crypto.Argon2Params{Time: 1, MemoryKiB: 8 * 1024, Threads: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := datastore.Create(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := datastore.AddSatellite(roundTripSatellites[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := datastore.Save(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// runImport runs 'satcli import' with flags against the open datastore, restoring the flags after.
+func runImport(t *testing.T, flags map[string]string) (stderr string, err error) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	importCmd.SetOut(&out)
+	importCmd.SetErr(&errOut)
+	importCmd.SetContext(context.Background())
+	t.Cleanup(func() {
+		for name := range flags {
+			importCmd.Flags().Set(name, importCmd.Flags().Lookup(name).DefValue)
+			importCmd.Flags().Lookup(name).Changed = false
+		}
+		importCmd.SetOut(nil)
+		importCmd.SetErr(nil)
+	})
+	for name, value := range flags {
+		if err := importCmd.Flags().Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = importCmd.RunE(importCmd, nil)
+	return errOut.String(), err
+}
+
+func TestImportSavesNothingWhenARecordFails(t *testing.T) {
+	valid := func(name string) types.Satellite {
+		sat := roundTripSatellites[2]
+		sat.Name = name
+		return sat
+	}
+	invalid := valid("BAD")
+	invalid.Altitude = -1
+	for _, stream := range []string{"true", "false"} {
+		t.Run("json-stream="+stream, func(t *testing.T) {
+			path := newImportTestStore(t)
+			before, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			input, err := json.Marshal([]types.Satellite{valid("FIRST"), invalid, valid("LAST")})
+			if err != nil {
+				t.Fatal(err)
+			}
+			file := filepath.Join(t.TempDir(), "import.json")
+			if err := os.WriteFile(file, input, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			stderr, err := runImport(t, map[string]string{"file": file, "json-stream": stream})
+			if err == nil || !strings.Contains(err.Error(), "no records were imported") {
+				t.Fatalf("err = %v, want a failed validation", err)
+			}
+			if !strings.Contains(stderr, "record 1:") {
+				t.Errorf("stderr = %q, want the invalid record 1 reported", stderr)
+			}
+			after, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(before, after) {
+				t.Error("the datastore file changed after a failed import")
+			}
+		})
+	}
+}
+
+// BenchmarkImportJSONStream compares --json-stream with the batch import of a JSON array, on a
+// generated array of about 100 MB; both decode every record. Compare the allocations of each
+// case, e.g. with go test -run '^$' -bench ImportJSONStream -benchmem ./cmd/satcli.
+func BenchmarkImportJSONStream(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	records := 0
+	for buf.Len() < 100<<20 {
+		sat := roundTripSatellites[records%len(roundTripSatellites)]
+		sat.Name = fmt.Sprintf("SAT-%07d", records)
+		data, err := json.Marshal(sat)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if records > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(data)
+		records++
+	}
+	buf.WriteByte(']')
+	data := buf.Bytes()
+
+	for _, bc := range []struct {
+		name   string
+		decode func(in io.Reader) (int, error)
+	}{
+		{"stream", func(in io.Reader) (int, error) {
+			n, _, err := streamImportJSON(&cobra.Command{}, in, int64(len(data)), "bench.json", func(_ *progress, _ int, raw json.RawMessage) error {
+				_, err := decodeImportRecord(raw, false)
+				return err
+			})
+			return n, err
+		}},
+		{"batch", func(in io.Reader) (int, error) {
+			all, err := io.ReadAll(in)
+			if err != nil {
+				return 0, err
+			}
+			var raws []json.RawMessage
+			if err := json.Unmarshal(all, &raws); err != nil {
+				return 0, err
+			}
+			sats := make([]types.Satellite, 0, len(raws))
+			for _, raw := range raws {
+				sat, err := decodeImportRecord(raw, false)
+				if err != nil {
+					return 0, err
+				}
+				sats = append(sats, sat)
+			}
+			return len(sats), nil
+		}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				n, err := bc.decode(bytes.NewReader(data))
+				if err != nil {
+					b.Fatal(err)
+				}
+				if n != records {
+					b.Fatalf("decoded %d records, want %d", n, records)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// cmd/satcli/import_formats.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// importFormats lists the accepted import --format values.
+var importFormats = []string{"json", "csv", "yaml", "tle", "protobuf"}
+
+// importFormatExtensions maps file extensions to import formats.
+var importFormatExtensions = map[string]string{
+	".json": "json", ".csv": "csv", ".yaml": "yaml", ".yml": "yaml", ".tle": "tle",
+	".pb": "protobuf", ".binpb": "protobuf",
+}
+
+// detectImportFormat picks the format of an import file from its extension or, failing that, its
+// content: a leading '[' or '{' is JSON, a "1 " line followed by a "2 " line is TLE, a first line
+// with commas naming a "name" column is CSV, a leading "---" or "- " is YAML, and binary data (a NUL
+// byte or invalid UTF-8) starting with a SatelliteList's first field tag is protobuf. It also returns
+// how the format was found ("extension" or "content"), or an error if nothing matched.
+func detectImportFormat(path string, data []byte) (string, string, error) {
+	if format := importFormatExtensions[strings.ToLower(filepath.Ext(path))]; format != "" {
+		return format, "extension", nil
+	}
+	if len(data) > 0 && data[0] == 1<<3|wireBytes && (bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data)) {
+		return "protobuf", "content", nil
+	}
+	content := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(content) > 0 && (content[0] == '[' || content[0] == '{') {
+		return "json", "content", nil
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() && len(lines) < 50 {
+		if line := strings.TrimRight(scanner.Text(), "\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	for i := 0; i+1 < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "1 ") && strings.HasPrefix(lines[i+1], "2 ") {
+			return "tle", "content", nil
+		}
+	}
+	if len(lines) > 0 {
+		first := lines[0]
+		if strings.Contains(first, ",") && containsString(splitCSVHeader(first), "name") {
+			return "csv", "content", nil
+		}
+		if strings.HasPrefix(first, "---") || strings.HasPrefix(first, "- ") {
+			return "yaml", "content", nil
+		}
+	}
+	return "", "", fmt.Errorf("cannot detect the format of %s; use --format (%s)", path, strings.Join(importFormats, ", "))
+}
+
+func splitCSVHeader(line string) []string {
+	fields, _ := csv.NewReader(strings.NewReader(line)).Read()
+	for i, f := range fields {
+		fields[i] = strings.TrimSpace(f)
+	}
+	return fields
+}
+
+// yamlToRecords converts a YAML sequence of satellite mappings (the keys of 'satcli list' JSON) into
+// JSON records.
+func yamlToRecords(data []byte) ([]json.RawMessage, error) {
+	var docs []map[string]interface{}
+	if err := yaml.Unmarshal(data, &docs); err != nil {
+		return nil, fmt.Errorf("not a YAML list of satellites: %w", err)
+	}
+	records := make([]json.RawMessage, 0, len(docs))
+	for i, doc := range docs {
+		raw, err := json.Marshal(yamlDatesToStrings(doc))
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i, err)
+		}
+		records = append(records, raw)
+	}
+	return records, nil
+}
+
+// yamlDatesToStrings turns the timestamps YAML decodes from unquoted dates (launchDate: 2020-01-02)
+// back into strings, as dates are stored as YYYY-MM-DD text.
+func yamlDatesToStrings(v interface{}) interface{} {
+	switch v := v.(type) {
+	case time.Time:
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			return v.Format(config.DateFormat)
+		}
+		return v.Format(time.RFC3339)
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = yamlDatesToStrings(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = yamlDatesToStrings(value)
+		}
+	}
+	return v
+}
+
+// csvToRecords converts CSV with a header row of satellite JSON field names into JSON records.
+// Columns named custom.<key> set custom attributes; empty cells are left unset. Numeric and boolean
+// fields are parsed according to types.SatelliteSchema.
+func csvToRecords(data []byte) ([]json.RawMessage, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.TrimLeadingSpace = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("not valid CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	properties := types.SatelliteSchema().Properties
+	header := rows[0]
+	for i, column := range header {
+		header[i] = strings.TrimSpace(column)
+		prop, known := properties[header[i]]
+		if !strings.HasPrefix(header[i], "custom.") && (!known || prop.Type == "object") {
+			return nil, fmt.Errorf("unknown CSV column '%s'", header[i])
+		}
+	}
+
+	records := make([]json.RawMessage, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		record := make(map[string]interface{})
+		custom := make(map[string]string)
+		for j, cell := range row {
+			column := header[j]
+			if cell = strings.TrimSpace(cell); cell == "" {
+				continue
+			}
+			if key, ok := strings.CutPrefix(column, "custom."); ok {
+				custom[key] = cell
+				continue
+			}
+			switch properties[column].Type {
+			case "number":
+				v, err := strconv.ParseFloat(cell, 64)
+				if err != nil {
+					return nil, fmt.Errorf("record %d: invalid number '%s' for %s", i, cell, column)
+				}
+				record[column] = v
+			case "boolean":
+				v, err := strconv.ParseBool(cell)
+				if err != nil {
+					return nil, fmt.Errorf("record %d: invalid boolean '%s' for %s", i, cell, column)
+				}
+				record[column] = v
+			case "array":
+				var items []string
+				for _, item := range strings.Split(cell, csvListSeparator) {
+					if item = strings.TrimSpace(item); item != "" {
+						items = append(items, item)
+					}
+				}
+				record[column] = items
+			default:
+				record[column] = cell
+			}
+		}
+		if len(custom) > 0 {
+			record["custom"] = custom
+		}
+		raw, _ := json.Marshal(record) // Only strings, string lists, float64s and bools
+		records = append(records, raw)
+	}
+	return records, nil
+}
+
+// tleToRecords converts TLE text into JSON records, applying each element set to the stored record
+// of the same name as import-tle does. Malformed blocks are reported through skip.
+func tleToRecords(data []byte, existing map[string]types.Satellite, skip func(at tleLine, reason error)) ([]json.RawMessage, int, error) {
+	updates := make(map[string]types.Satellite)
+	var order []string
+	skipped, err := scanTLEBlocks(bytes.NewReader(data), func(t types.TLE) {
+		sat, seen := updates[t.Name]
+		if !seen {
+			sat = existing[t.Name]
+			order = append(order, t.Name)
+		}
+		updates[t.Name] = t.Apply(sat)
+	}, skip)
+	if err != nil {
+		return nil, skipped, err
+	}
+	records := make([]json.RawMessage, 0, len(order))
+	for _, name := range order {
+		raw, err := json.Marshal(updates[name])
+		if err != nil {
+			return nil, skipped, err
+		}
+		records = append(records, raw)
+	}
+	return records, skipped, nil
+}
@@ -0,0 +1,190 @@
+// cmd/satcli/import_tle_cmd.go
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// tleLine is one line of TLE input with its position, for reporting malformed blocks.
+type tleLine struct {
+	Text   string
+	Line   int   // 1-based line number
+	Offset int64 // Byte offset of the line's first character
+}
+
+var importTLECmd = &cobra.Command{
+	Use:   "import-tle",
+	Short: "Import or refresh satellites from two-line element sets (TLEs)",
+	Long: `Reads TLE text (optionally with a title line before each pair of element lines, as published by
+CelesTrak) from --file or, with --file - or --stdin, from standard input. Altitude, inclination, eccentricity and
+orbit type are derived from each element set, and the NORAD catalog number and international
+designator are stored as custom attributes. Satellites already stored under the same name keep
+their other fields; 'satcli refresh-tle' updates only the orbital elements of stored satellites.
+Malformed blocks are reported with their line and byte offsets and skipped; the datastore is saved
+once at the end.
+
+Examples:
+  satcli import-tle --file stations.txt
+  curl -s https://celestrak.org/NORAD/elements/gp.php?GROUP=stations | satcli import-tle --file -`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		cmd.SilenceUsage = true
+		in, source, size, closeInput, err := openTLEInput(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeInput()
+		p := newProgress(cmd.ErrOrStderr(), "Importing TLEs", size)
+
+		existing, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		updates := make(map[string]types.Satellite)
+		var order []string
+		skipped, err := scanTLEBlocks(in, func(t types.TLE) {
+			sat, seen := updates[t.Name]
+			if !seen {
+				sat = existing[t.Name]
+				order = append(order, t.Name)
+			}
+			updates[t.Name] = t.Apply(sat)
+			p.Update(len(updates), in.n)
+		}, func(at tleLine, reason error) {
+			p.Clear()
+			fmt.Fprintf(cmd.ErrOrStderr(), "skipped block at line %d (byte %d): %v\n", at.Line, at.Offset, reason)
+		})
+		p.Clear()
+		if err != nil {
+			return fmt.Errorf("failed to read TLEs from %s: %w", source, err)
+		}
+		if len(updates) == 0 {
+			return fmt.Errorf("no valid TLEs found in %s (%d block(s) skipped)", source, skipped)
+		}
+
+		added := 0
+		for _, name := range order {
+			if _, ok := existing[name]; !ok {
+				added++
+			}
+			if err := datastore.AddSatellite(updates[name]); err != nil {
+				return err
+			}
+		}
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to save %d TLE record(s): %w", len(updates), err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Imported %d TLE record(s): %d added, %d updated, %d skipped.\n", len(updates), added, len(updates)-added, skipped)
+		return nil
+	},
+}
+
+// openTLEInput opens the TLE source selected by --file or --stdin (exactly one is required; --stdin
+// is the same as --file -), returning a reader counting the bytes read, a name for messages, its
+// size if known, and a func to close it.
+func openTLEInput(cmd *cobra.Command) (in *countingReader, source string, size int64, closeInput func() error, err error) {
+	path, _ := cmd.Flags().GetString("file")
+	fromStdin, _ := cmd.Flags().GetBool("stdin")
+	if fromStdin == (path != "") {
+		return nil, "", 0, nil, fmt.Errorf("specify exactly one of --file or --stdin")
+	}
+	if fromStdin {
+		path = stdioPath
+	}
+	r, source, size, closeInput, err := openInputFile(path)
+	if err != nil {
+		return nil, "", 0, nil, fmt.Errorf("failed to read TLE file: %w", err)
+	}
+	return &countingReader{r: r}, source, size, closeInput, nil
+}
+
+// scanTLEBlocks reads r line by line, calling found for each valid element set and skip for each
+// malformed block (at its first line). It returns the number of skipped blocks.
+func scanTLEBlocks(r io.Reader, found func(types.TLE), skip func(at tleLine, reason error)) (int, error) {
+	br := bufio.NewReader(r)
+	var title, line1 *tleLine
+	skipped := 0
+	reject := func(at *tleLine, reason error) {
+		skip(*at, reason)
+		skipped++
+	}
+	// rejectPending skips an unfinished block (a line 1 without its line 2), reported at its title if any.
+	rejectPending := func() {
+		if line1 == nil {
+			return
+		}
+		start := line1
+		if title != nil {
+			start = title
+		}
+		reject(start, errors.New("line 1 is not followed by line 2"))
+		title, line1 = nil, nil
+	}
+
+	lineNo, offset := 0, int64(0)
+	for {
+		text, err := br.ReadString('\n')
+		if text != "" {
+			lineNo++
+			l := &tleLine{Text: strings.TrimRight(text, "\r\n"), Line: lineNo, Offset: offset}
+			offset += int64(len(text))
+			switch {
+			case strings.TrimSpace(l.Text) == "":
+			case strings.HasPrefix(l.Text, "1 "):
+				rejectPending()
+				line1 = l
+			case strings.HasPrefix(l.Text, "2 "):
+				if line1 == nil {
+					reject(l, errors.New("line 2 is not preceded by line 1"))
+					title = nil
+					break
+				}
+				start, name := line1, ""
+				if title != nil {
+					start, name = title, title.Text
+				}
+				if t, errParse := types.ParseTLE(name, line1.Text, l.Text); errParse != nil {
+					reject(start, errParse)
+				} else {
+					found(t)
+				}
+				title, line1 = nil, nil
+			default:
+				rejectPending()
+				if title != nil {
+					reject(title, errors.New("title line is not followed by element lines"))
+				}
+				title = l
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return skipped, err
+		}
+	}
+	rejectPending()
+	if title != nil {
+		reject(title, errors.New("title line is not followed by element lines"))
+	}
+	return skipped, nil
+}
+
+func init() {
+	importTLECmd.Flags().String("file", "", "Path of a TLE text file, or - for standard input")
+	importTLECmd.Flags().Bool("stdin", false, "Read TLE text from stdin instead of --file")
+	rootCmd.AddCommand(importTLECmd)
+}
@@ -0,0 +1,43 @@
+// cmd/satcli/init_cmd.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a new, empty encrypted datastore",
+	Long: `Creates an empty datastore at the resolved path (see --datastore), encrypted under a new passphrase
+that is entered twice at the prompt (or taken from ` + config.PassphraseEnvVar + `). Choose the algorithms with the
+global --kdf and --cipher flags, and the Argon2id cost with --argon2-time, --argon2-memory and
+--argon2-threads (see 'satcli crypto calibrate'). Refuses to touch an existing datastore unless --force is given;
+--force replaces it without decrypting it, so its records are lost.
+
+Examples:
+  satcli init
+  satcli init --kdf scrypt --cipher chacha20-poly1305
+  satcli init --argon2-time 3 --argon2-memory 256 --argon2-threads 4
+  satcli init --datastore ~/sat/new.dat --force`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{skipDatastoreAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		cmd.SilenceUsage = true
+		if err := datastore.Create(cmd.Context(), force); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Created empty datastore at %s.\n", mustDatastorePath())
+		return nil
+	},
+}
+
+func init() {
+	initCmd.Flags().Bool("force", false, "Replace an existing datastore (its records are lost)")
+	rootCmd.AddCommand(initCmd)
+}
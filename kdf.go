@@ -0,0 +1,135 @@
+// internal/crypto/kdf.go
+package crypto
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KDFID identifies a key derivation function in the datastore file header.
+type KDFID byte
+
+const (
+	KDFArgon2id       KDFID = 1
+	KDFScrypt         KDFID = 2
+	KDFArgon2idParams KDFID = 3 // Argon2id with the cost parameters recorded in the header
+)
+
+// scrypt parameters. N=2^15, r=8, p=1 needs ~32 MiB, well below the Argon2id default.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32 // AES-256
+)
+
+// KeyDeriver derives an encryption key from a passphrase and a salt.
+type KeyDeriver interface {
+	ID() KDFID
+	Name() string
+	DeriveKey(passphrase string, salt []byte) ([]byte, error)
+}
+
+// Argon2idKDF is the default KeyDeriver, backed by DeriveKeyWithArgon2id.
+type Argon2idKDF struct{}
+
+func (Argon2idKDF) ID() KDFID    { return KDFArgon2id }
+func (Argon2idKDF) Name() string { return "argon2id" }
+func (Argon2idKDF) DeriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return DeriveKeyWithArgon2id(passphrase, salt)
+}
+
+// Argon2idParamsKDF is Argon2id with chosen cost parameters, e.g. from 'satcli crypto calibrate'.
+// The parameters are recorded in the file header, so the store opens wherever it is copied.
+type Argon2idParamsKDF struct {
+	Params Argon2Params
+}
+
+func (Argon2idParamsKDF) ID() KDFID    { return KDFArgon2idParams }
+func (Argon2idParamsKDF) Name() string { return "argon2id" }
+func (k Argon2idParamsKDF) DeriveKey(passphrase string, salt []byte) ([]byte, error) {
+	if err := k.Params.Validate(); err != nil {
+		return nil, err
+	}
+	return argon2.IDKey([]byte(passphrase), salt, k.Params.Time, k.Params.MemoryKiB, k.Params.Threads, argon2KeyLen), nil
+}
+
+// ScryptKDF is a lower-memory alternative to Argon2id.
+type ScryptKDF struct{}
+
+func (ScryptKDF) ID() KDFID    { return KDFScrypt }
+func (ScryptKDF) Name() string { return "scrypt" }
+func (ScryptKDF) DeriveKey(passphrase string, salt []byte) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("scrypt key derivation failed: %w", err)
+	}
+	return key, nil
+}
+
+// DefaultKDF is used for new datastores unless another KDF is selected.
+var DefaultKDF KeyDeriver = Argon2idKDF{}
+
+var kdfs = []KeyDeriver{Argon2idKDF{}, ScryptKDF{}}
+
+// KDFByID returns the KeyDeriver recorded under id in a file header. KDFArgon2idParams is not
+// returned, as its parameters follow in the header; see Argon2idParamsKDF.
+func KDFByID(id KDFID) (KeyDeriver, error) {
+	for _, k := range kdfs {
+		if k.ID() == id {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key derivation function id %d", id)
+}
+
+// KDFByName returns the KeyDeriver with the given name (case-insensitive).
+func KDFByName(name string) (KeyDeriver, error) {
+	for _, k := range kdfs {
+		if strings.EqualFold(k.Name(), name) {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key derivation function '%s'. Supported: argon2id, scrypt", name)
+}
+
+// logger receives key derivation timing; nothing is logged by default.
+var logger = slog.New(slog.DiscardHandler)
+
+// SetLogger sets the logger for key derivation events.
+func SetLogger(l *slog.Logger) {
+	logger = l
+}
+
+// DeriveKeyCtx runs kdf.DeriveKey but returns ctx.Err() as soon as ctx is done.
+// KDFs cannot be interrupted mid-computation, so an abandoned derivation finishes
+// in the background and its result is discarded.
+func DeriveKeyCtx(ctx context.Context, kdf KeyDeriver, passphrase string, salt []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		key []byte
+		err error
+	}
+	start := time.Now()
+	done := make(chan result, 1)
+	go func() {
+		key, err := kdf.DeriveKey(passphrase, salt)
+		done <- result{key, err}
+	}()
+	select {
+	case <-ctx.Done():
+		logger.Debug("key derivation cancelled", "kdf", kdf.Name(), "after", time.Since(start), "reason", ctx.Err())
+		return nil, ctx.Err()
+	case r := <-done:
+		logger.Debug("key derivation finished", "kdf", kdf.Name(), "duration", time.Since(start), "ok", r.err == nil)
+		return r.key, r.err
+	}
+}
@@ -0,0 +1,89 @@
+// internal/crypto/kdf_test.go
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// testArgon2Params are cheap Argon2id costs, so the tests do not spend time deriving keys.
+var testArgon2Params = Argon2Params{Time: 1, MemoryKiB: 8 * 1024, Threads: 1}
+
+// testKDFs are every KeyDeriver a datastore header can name.
+var testKDFs = []KeyDeriver{Argon2idKDF{}, Argon2idParamsKDF{Params: testArgon2Params}, ScryptKDF{}}
+
+func TestDeriveKeyRoundTrip(t *testing.T) {
+	salt := bytes.Repeat([]byte{1}, 16)
+	otherSalt := bytes.Repeat([]byte{2}, 16)
+	for _, kdf := range testKDFs {
+		t.Run(fmt.Sprintf("%s id %d", kdf.Name(), kdf.ID()), func(t *testing.T) {
+			key, err := kdf.DeriveKey("Correct-Horse-9-battery", salt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(key) != 32 {
+				t.Errorf("key is %d bytes, want 32", len(key))
+			}
+			again, err := kdf.DeriveKey("Correct-Horse-9-battery", salt)
+			if err != nil || !bytes.Equal(key, again) {
+				t.Errorf("same passphrase and salt gave a different key (err %v)", err)
+			}
+			if other, err := kdf.DeriveKey("Correct-Horse-9-batterz", salt); err != nil || bytes.Equal(key, other) {
+				t.Errorf("another passphrase gave the same key (err %v)", err)
+			}
+			if other, err := kdf.DeriveKey("Correct-Horse-9-battery", otherSalt); err != nil || bytes.Equal(key, other) {
+				t.Errorf("another salt gave the same key (err %v)", err)
+			}
+		})
+	}
+}
+
+func TestKDFsDeriveDifferentKeys(t *testing.T) {
+	salt := bytes.Repeat([]byte{1}, 16)
+	seen := make(map[string]KDFID)
+	for _, kdf := range testKDFs {
+		key, err := kdf.DeriveKey("Correct-Horse-9-battery", salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id, dup := seen[string(key)]; dup {
+			t.Errorf("KDF %d derived the same key as KDF %d", kdf.ID(), id)
+		}
+		seen[string(key)] = kdf.ID()
+	}
+}
+
+func TestArgon2idParamsKDFRejectsInvalidParams(t *testing.T) {
+	kdf := Argon2idParamsKDF{Params: Argon2Params{Time: 0, MemoryKiB: 8 * 1024, Threads: 1}}
+	if _, err := kdf.DeriveKey("Correct-Horse-9-battery", make([]byte, 16)); err == nil {
+		t.Error("DeriveKey accepted a zero time cost")
+	}
+}
+
+func TestKDFByIDAndName(t *testing.T) {
+	for _, kdf := range []KeyDeriver{Argon2idKDF{}, ScryptKDF{}} {
+		if got, err := KDFByID(kdf.ID()); err != nil || got != kdf {
+			t.Errorf("KDFByID(%d) = %v, %v", kdf.ID(), got, err)
+		}
+		if got, err := KDFByName(kdf.Name()); err != nil || got != kdf {
+			t.Errorf("KDFByName(%q) = %v, %v", kdf.Name(), got, err)
+		}
+	}
+	if _, err := KDFByID(KDFArgon2idParams); err == nil {
+		t.Error("KDFByID returned a KDF for the id whose parameters follow in the header")
+	}
+	if _, err := KDFByName("pbkdf2"); err == nil {
+		t.Error("KDFByName accepted an unknown name")
+	}
+}
+
+func TestDeriveKeyCtxHonorsCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := DeriveKeyCtx(ctx, ScryptKDF{}, "Correct-Horse-9-battery", make([]byte, 16)); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,37 @@
+// cmd/satcli/kv_writer.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/yackko/satcom-code/types"
+)
+
+// kvEscaper keeps each --output kv value on its line.
+var kvEscaper = strings.NewReplacer("\\", `\\`, "\n", `\n`, "\r", `\r`)
+
+// printSatellitesKV writes each satellite as one key=value line per field, keyed by JSON field name
+// in struct order and formatted as writeCSV does, with custom attributes as custom.<key> lines.
+// Records are separated by a blank line; backslashes and line breaks in values are escaped.
+func printSatellitesKV(out io.Writer, sats []types.Satellite) {
+	t := reflect.TypeOf(types.Satellite{})
+	for n, sat := range sats {
+		if n > 0 {
+			fmt.Fprintln(out)
+		}
+		v := reflect.ValueOf(sat)
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" || t.Field(i).Type.Kind() == reflect.Map {
+				continue
+			}
+			fmt.Fprintf(out, "%s=%s\n", name, kvEscaper.Replace(csvCell(v.Field(i))))
+		}
+		for _, key := range sortedKeys(sat.Custom) {
+			fmt.Fprintf(out, "custom.%s=%s\n", key, kvEscaper.Replace(sat.Custom[key]))
+		}
+	}
+}
@@ -3,52 +3,323 @@ package tui
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	// Ensure this import path correctly points to your types package
 	// based on your go.mod module name.
 	"github.com/yackko/satcom-code/types" 
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // ListModel is a minimal TUI model for testing.
 type ListModel struct {
-	Satellites []types.Satellite // To ensure types package is resolving
-	Message    string
+	Satellites     []types.Satellite // To ensure types package is resolving
+	Message        string
+	ColorOperators bool // Render operator names with tui.OperatorStyle
+	Groups         []types.ConstellationGroup // Constellation roll-ups shown after Satellites; see NewGroupedListModel
+	cursor         int                        // Selected row: Satellites first, then Groups
+	expanded       map[int]bool               // Groups whose members are listed
+	AltitudeUnit   string                     // Unit label for altitudes, which are already converted; "" means km
+	sortKey        int                        // Index into sortKeys of the active sort, or -1 for the order given
+	sortDesc       bool                       // Reverse the active sort
+	PageSize       int                        // Satellite rows shown at once, scrolling with the cursor; 0 means defaultPageSize
+	offset         int                        // First satellite row shown
+	widths         []int                      // Column widths over all of Satellites, so scrolled rows stay aligned
+	width, height  int                        // Terminal size from the last tea.WindowSizeMsg; 0 until one arrives
+}
+
+// defaultPageSize is how many satellite rows are rendered at once unless PageSize is set. Only these
+// rows are formatted and styled, so large lists stay responsive.
+const defaultPageSize = 20
+
+// sortKeys are the orders the 's' key cycles through.
+var sortKeys = []struct {
+	Name string
+	Less func(a, b types.Satellite) bool
+}{
+	{"name", func(a, b types.Satellite) bool { return a.Name < b.Name }},
+	{"altitude", func(a, b types.Satellite) bool { return a.Altitude < b.Altitude }},
+	{"launch date", func(a, b types.Satellite) bool { return a.LaunchDate < b.LaunchDate }},
 }
 
 // NewListModel creates a new minimal model.
 func NewListModel(sats []types.Satellite) ListModel {
 	return ListModel{
 		Satellites: sats,
-		Message:    "Minimal TUI Model Initialized. Press 'q' to quit.",
+		Message:    "Up/down to select, pgup/pgdown to page, s to change the sort, S to reverse it. Press 'q' to quit.",
+		sortKey:    -1,
+		widths:     satelliteColumnWidths(sats),
 	}
 }
 
-// Init is a required method for tea.Model.
+// NewGroupedListModel creates a model listing non-constellation satellites individually and
+// constellation members as one expandable row per operator (up/down to select, enter to toggle).
+func NewGroupedListModel(sats []types.Satellite) ListModel {
+	individuals, groups := types.GroupConstellations(sats)
+	m := NewListModel(individuals)
+	m.Groups = groups
+	m.expanded = make(map[int]bool)
+	m.Message = "Up/down to select, pgup/pgdown to page, enter to expand a constellation, s/S to sort. Press 'q' to quit."
+	return m
+}
+
+// Init is a required method for tea.Model. It enters the alternate screen and asks for the
+// terminal size, so the first page already fits.
 func (m ListModel) Init() tea.Cmd {
-	// This is just for a quick check if Init runs, can be removed later.
-	// fmt.Println("Minimal ListModel Init()") 
-	return nil
+	return tea.Batch(tea.EnterAltScreen, tea.WindowSize())
 }
 
 // Update is a required method for tea.Model.
 func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scrollToCursor()
 	case tea.KeyMsg:
-		if msg.String() == "q" || msg.String() == "ctrl+c" {
+		switch msg.String() {
+		case "q", "ctrl+c":
 			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.Satellites)+len(m.Groups)-1 {
+				m.cursor++
+			}
+		case "pgdown":
+			m.cursor = min(m.cursor+m.pageSize(), max(len(m.Satellites)+len(m.Groups)-1, 0))
+		case "pgup":
+			m.cursor = max(m.cursor-m.pageSize(), 0)
+		case "enter", " ":
+			if g := m.cursor - len(m.Satellites); g >= 0 && g < len(m.Groups) {
+				m.expanded[g] = !m.expanded[g]
+			}
+		case "s":
+			m.sortKey = (m.sortKey + 1) % len(sortKeys)
+			m.sortSatellites()
+		case "S":
+			if m.sortKey < 0 {
+				m.sortKey = 0
+			}
+			m.sortDesc = !m.sortDesc
+			m.sortSatellites()
 		}
+		m.scrollToCursor()
 	}
 	return m, nil
 }
 
+// pageSize is PageSize if set, or else as many satellite rows as fit the terminal beside the rest
+// of the view once its size is known, or else defaultPageSize.
+func (m ListModel) pageSize() int {
+	if m.PageSize > 0 {
+		return m.PageSize
+	}
+	if m.height > 0 {
+		return max(m.height-m.fixedLines(), 1)
+	}
+	return defaultPageSize
+}
+
+// fixedLines counts the lines View renders besides the satellite rows: the headings, the groups,
+// the detail pane of the selection and the help message.
+func (m ListModel) fixedLines() int {
+	lines := 5 // Title, "Showing" line, column headings, the help message and the empty line after it
+	for i, g := range m.Groups {
+		lines++
+		if m.expanded[i] {
+			lines += len(g.Satellites)
+		}
+	}
+	if m.cursor < len(m.Satellites) {
+		lines += strings.Count(m.details(m.Satellites[m.cursor]), "\n")
+	}
+	return lines
+}
+
+// scrollToCursor moves the visible page of satellites just far enough to show the cursor.
+func (m *ListModel) scrollToCursor() {
+	cursor := min(m.cursor, len(m.Satellites)-1) // In the groups, keep the last page in view
+	if cursor < m.offset {
+		m.offset = max(cursor, 0)
+	} else if cursor >= m.offset+m.pageSize() {
+		m.offset = cursor - m.pageSize() + 1
+	}
+}
+
+// sortSatellites re-sorts Satellites in place by the active sort (ties by name), keeping the
+// selected satellite selected.
+func (m *ListModel) sortSatellites() {
+	selected := ""
+	if m.cursor < len(m.Satellites) {
+		selected = m.Satellites[m.cursor].Name
+	}
+	less := sortKeys[m.sortKey].Less
+	sort.SliceStable(m.Satellites, func(i, j int) bool {
+		a, b := m.Satellites[i], m.Satellites[j]
+		if m.sortDesc {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.Name < b.Name
+	})
+	for i, sat := range m.Satellites {
+		if sat.Name == selected {
+			m.cursor = i
+		}
+	}
+}
+
+// sortLabel describes the active sort for the header.
+func (m ListModel) sortLabel() string {
+	if m.sortKey < 0 {
+		return "as listed"
+	}
+	if m.sortDesc {
+		return sortKeys[m.sortKey].Name + " (descending)"
+	}
+	return sortKeys[m.sortKey].Name
+}
+
+func (m ListModel) altitudeUnit() string {
+	if m.AltitudeUnit == "" {
+		return "km"
+	}
+	return m.AltitudeUnit
+}
+
 // View is a required method for tea.Model.
 func (m ListModel) View() string {
 	var s string
 	if len(m.Satellites) > 0 {
-		s = fmt.Sprintf("Minimal TUI: %d satellites loaded. First: %s\n", len(m.Satellites), m.Satellites[0].Name)
-	} else {
+		s = fmt.Sprintf("Minimal TUI: %d satellites loaded. Sort: %s\n", len(m.Satellites), m.sortLabel())
+		first, last := m.offset, min(m.offset+m.pageSize(), len(m.Satellites))
+		if last-first < len(m.Satellites) {
+			s += fmt.Sprintf("Showing %d-%d of %d\n", first+1, last, len(m.Satellites))
+		}
+		rows := [][]string{{"NAME", "OPERATOR", "ALTITUDE (" + m.altitudeUnit() + ")", "LAUNCH DATE"}}
+		for _, sat := range m.Satellites[first:last] {
+			rows = append(rows, satelliteRow(sat, m.operator))
+		}
+		for i, line := range alignColumns(rows, m.widths) {
+			cursor := "  "
+			if first+i-1 == m.cursor {
+				cursor = "> "
+			}
+			s += cursor + line + "\n"
+		}
+	} else if len(m.Groups) == 0 {
 		s = "Minimal TUI: No satellites loaded.\n"
 	}
-	return s + m.Message + "\n"
+	for i, g := range m.Groups {
+		cursor, marker := "  ", "+"
+		if len(m.Satellites)+i == m.cursor {
+			cursor = "> "
+		}
+		if m.expanded[i] {
+			marker = "-"
+		}
+		altitude := fmt.Sprintf("%.0f", g.AltitudeMinKm)
+		if g.AltitudeMaxKm != g.AltitudeMinKm {
+			altitude = fmt.Sprintf("%.0f-%.0f", g.AltitudeMinKm, g.AltitudeMaxKm)
+		}
+		s += fmt.Sprintf("%s[%s] %s constellation: %d members, %s %s, %s\n", cursor, marker, m.operator(g.Operator), g.Members, altitude, m.altitudeUnit(), strings.Join(g.OrbitTypes, "/"))
+		if m.expanded[i] {
+			for _, sat := range g.Satellites {
+				s += fmt.Sprintf("        %s  %.0f %s\n", sat.Name, sat.Altitude, m.altitudeUnit())
+			}
+		}
+	}
+	if m.cursor < len(m.Satellites) {
+		s += m.details(m.Satellites[m.cursor])
+	}
+	s += m.Message + "\n"
+	if m.width > 0 {
+		// Cut lines at the terminal edge rather than letting them wrap and push the title off screen.
+		lines := strings.Split(s, "\n")
+		for i, line := range lines {
+			lines[i] = lipgloss.NewStyle().MaxWidth(m.width).Render(line)
+		}
+		s = strings.Join(lines, "\n")
+	}
+	return s
+}
+
+// details renders the detail pane for the selected satellite, including its tags and custom attributes.
+func (m ListModel) details(sat types.Satellite) string {
+	s := fmt.Sprintf("\n%s\n  Operator: %s\n  Status: %s\n  Orbit: %s, %.0f %s, %.2f deg\n  Launch date: %s\n",
+		sat.Name, m.operator(sat.Operator), sat.Status, sat.OrbitType, sat.Altitude, m.altitudeUnit(), sat.Inclination, sat.LaunchDate)
+	if len(sat.Tags) > 0 {
+		s += "  Tags: " + strings.Join(sat.Tags, ", ") + "\n"
+	}
+	if len(sat.Custom) > 0 {
+		keys := make([]string, 0, len(sat.Custom))
+		for key := range sat.Custom {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		s += "  Custom:\n"
+		for _, key := range keys {
+			s += fmt.Sprintf("    %s: %s\n", key, sat.Custom[key])
+		}
+	}
+	return s + "\n"
+}
+
+// operator renders an operator name, colored when ColorOperators is set.
+func (m ListModel) operator(name string) string {
+	if m.ColorOperators {
+		return OperatorStyle(name).Render(name)
+	}
+	return name
+}
+
+// satelliteRow is the list row of sat, with its operator rendered by operator.
+func satelliteRow(sat types.Satellite, operator func(string) string) []string {
+	return []string{sat.Name, operator(sat.Operator), fmt.Sprintf("%.0f", sat.Altitude), sat.LaunchDate}
+}
+
+// satelliteColumnWidths returns the widths of the unstyled list rows of sats, for alignColumns.
+func satelliteColumnWidths(sats []types.Satellite) []int {
+	widths := make([]int, 4)
+	for _, sat := range sats {
+		for i, cell := range satelliteRow(sat, func(name string) string { return name }) {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+	return widths
+}
+
+// alignColumns pads each cell to its column's display width (ignoring ANSI styling), two spaces apart.
+// minWidths, if given, sets a floor for each column, e.g. the widths of rows not being rendered.
+func alignColumns(rows [][]string, minWidths []int) []string {
+	widths := make([]int, len(rows[0]))
+	copy(widths, minWidths)
+	for _, row := range rows {
+		for i, cell := range row {
+			if w := lipgloss.Width(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	lines := make([]string, len(rows))
+	for r, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+2))
+			}
+		}
+		lines[r] = b.String()
+	}
+	return lines
 }
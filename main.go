@@ -3,204 +3,279 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"sort"
-	"strconv"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	// Adjust module path if different from "satcom-code"
 	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/crypto"
 	"github.com/yackko/satcom-code/internal/datastore"
-	"github.com/yackko/satcom-code/tui" // For the TUI list view
 	"github.com/yackko/satcom-code/types"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
-// orbitExplanations map - specific to explainCmd in this package
-var orbitExplanations = map[string]string{
-	"LEO": "Low Earth Orbit (LEO):\n  Altitude: Typically 160 to 2,000 kilometers (100 to 1,240 miles).\n  Characteristics: Short orbital periods (around 90 minutes to 2 hours). Satellites move quickly relative to the Earth's surface.\n  Uses: Earth observation, remote sensing, communications (e.g., Starlink), International Space Station (ISS).\n  Pros: Lower launch costs, lower signal latency.\n  Cons: Limited coverage from a single satellite (requires constellations for continuous coverage), atmospheric drag can be a factor at lower LEO altitudes.",
-	"MEO": "Medium Earth Orbit (MEO):\n  Altitude: Between LEO and GEO, typically from 2,000 km up to 35,786 km (just below geostationary).\n  Common Altitudes: Around 20,200 km for navigation satellites.\n  Characteristics: Orbital periods of a few hours (e.g., 12 hours for GPS).\n  Uses: Navigation systems (e.g., GPS, GLONASS, Galileo), some communications.\n  Pros: Wider coverage than LEO, lower latency than GEO.\n  Cons: Fewer satellites needed than LEO for global coverage, but more than GEO.",
-	"GEO": "Geostationary Orbit (GEO) / Geosynchronous Equatorial Orbit:\n  Altitude: Precisely 35,786 kilometers (22,236 miles) directly above the Earth's Equator.\n  Characteristics: Orbital period matches Earth's rotation (23 hours, 56 minutes, 4 seconds). Satellites appear stationary from the ground.\n  Uses: Telecommunications (broadcast TV, fixed communications), weather monitoring (e.g., GOES).\n  Pros: Wide coverage area (one satellite can cover about 1/3 of Earth's surface), fixed ground antennas.\n  Cons: Significant signal latency due to high altitude, higher launch costs, poor coverage for polar regions.",
-	"GSO": "Geosynchronous Orbit (GSO):\n  Altitude: Also 35,786 kilometers.\n  Characteristics: Orbital period matches Earth's rotation. However, unlike GEO, GSO orbits can be inclined. A satellite in GSO will return to the same position in the sky at the same time each day, but it will appear to trace a path (an analemma) if inclined.\n  Uses: Similar to GEO; some communications and broadcasting.\n  Note: GEO is a special case of GSO where the inclination is zero.",
-	"HEO": "Highly Elliptical Orbit (HEO):\n  Characteristics: Orbit with a low perigee (closest point to Earth) and a very high apogee (farthest point). Satellites spend most of their time near apogee, moving slowly over a specific region.\n  Uses: Communications and broadcasting for high-latitude regions (e.g., Molniya orbits for Russia, SiriusXM radio satellites using Tundra orbits), some scientific missions.\n  Pros: Long dwell time over specific areas, good for covering regions not well served by GEO.\n  Cons: Requires steerable ground antennas, varying distance to satellite.",
-	"SSO": "Sun-Synchronous Orbit (SSO):\n  Characteristics: A type of polar orbit where the satellite passes over any given point on Earth's surface at the same local solar time. This means lighting conditions are consistent for imaging.\n  Altitude: Typically LEO altitudes (e.g., 600-800 km).\n  Inclination: Near-polar (e.g., around 98 degrees).\n  Uses: Earth observation, environmental monitoring, reconnaissance, weather satellites.\n  Pros: Consistent illumination for imaging and change detection.\n  Cons: Similar to LEO in terms of coverage per satellite.",
-	"HALO": "Halo Orbit:\n  Characteristics: A periodic, three-dimensional orbit near one of the Lagrange points (L1, L2, or L3) in a two-body system (e.g., Earth-Sun or Earth-Moon). These orbits don't orbit a celestial body directly but rather a point in space where gravitational forces balance.\n  Uses: Space telescopes (e.g., James Webb Space Telescope at Sun-Earth L2, SOHO at Sun-Earth L1), scientific observation, potential communication relays.\n  Pros: Provides a stable vantage point for observing the Earth, Sun, or deep space with minimal obstruction or interference. Can offer continuous view of certain regions.\n  Cons: Inherently unstable for some Lagrange points, requiring station-keeping maneuvers.",
-}
-
-// printSatellitesTable formats and prints a list of satellites as a table.
-// This function remains in main.go (or could be moved to table_printer.go).
-func printSatellitesTable(satellitesToPrint []types.Satellite) {
-	if len(satellitesToPrint) == 0 {
-		return // Caller should ideally handle "no results found" message
-	}
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "NAME\tOPERATOR\tSTATUS\tORBIT TYPE\tLAUNCH DATE\tALTITUDE (km)\tCONSTELLATION")
-	fmt.Fprintln(w, "----\t--------\t------\t----------\t-----------\t-------------\t-------------")
-	for _, sat := range satellitesToPrint {
-		constellationStatus := "No"
-		if sat.Constellation {
-			constellationStatus = "Yes"
-		}
-		altitudeStr := fmt.Sprintf("%.0f", sat.Altitude)
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			sat.Name, sat.Operator, sat.Status, sat.OrbitType, sat.LaunchDate, altitudeStr, constellationStatus)
-	}
-	w.Flush()
-}
-
 var rootCmd = &cobra.Command{
 	Use:   "satcli",
 	Short: "Satcli is a CLI tool for managing and querying satellite information.",
 	Long: `Satcli provides a command-line interface to manage a local, secure datastore of Earth satellites.
-If the ` + config.PassphraseEnvVar + ` environment variable is not set, you will be prompted for a passphrase.`,
+If the ` + config.PassphraseEnvVar + ` environment variable is not set, you will be prompted for a passphrase.
+
+` + exitCodesHelp,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		if cmd.Name() == "help" || cmd.CalledAs() == "help" || // Check for 'help' subcommand itself
            (cmd.Parent() != nil && cmd.Parent().Name() == "help") || // Check if parent is 'help' (for subcommands of help)
 			cmd.Name() == "version" || cmd.CalledAs() == "version" ||
-			strings.HasPrefix(cmd.Use, "completion") { // Check Use field for completion
+			strings.HasPrefix(cmd.Use, "completion") || // Check Use field for completion
+			cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd { // Tab completion must not prompt
 			return nil
 		}
-		if err := datastore.Init(); err != nil {
-			if !strings.Contains(err.Error(), "passphrase") && !strings.Contains(err.Error(), "decrypt") && !os.IsNotExist(err) {
-				fmt.Fprintf(os.Stderr, "Critical error during datastore initialization: %v\n", err)
-				return err
+		if err := applySettingDefaults(cmd); err != nil {
+			cmd.SilenceUsage = true; return err
+		}
+		logLevel, _ := cmd.Flags().GetString("log-level")
+		var err error
+		logger, err = newLogger(cmd.ErrOrStderr(), logLevel)
+		if err != nil {
+			cmd.SilenceUsage = true; return usageError(err)
+		}
+		datastore.SetLogger(logger)
+		crypto.SetLogger(logger)
+		quiet, _ = cmd.Flags().GetBool("quiet")
+		noticeOut = cmd.ErrOrStderr()
+		datastore.OnNotice = func(msg string) { noticef("%s\n", msg) } // Honors --quiet
+		datastore.OnConfirm = confirm
+		colorMode, _ := cmd.Flags().GetString("color")
+		if err := applyColorMode(colorMode); err != nil {
+			cmd.SilenceUsage = true; return usageError(err)
+		}
+		operatorColors, _ := cmd.Flags().GetBool("operator-colors")
+		colorOperators = colorEnabled && operatorColors
+		if datastorePath, _ := cmd.Flags().GetString("datastore"); datastorePath != "" {
+			expanded, err := config.ExpandHome(datastorePath)
+			if err != nil {
+				cmd.SilenceUsage = true; return &exitCodeError{exitIO, err}
 			}
-			// Non-critical init errors (like passphrase prompt failed for non-existent file) are handled by datastore.Init printing a notice.
-			// Individual commands will check datastore.IsUnlocked().
+			datastore.SetPath(expanded)
 		}
-		return nil
+		if tempDir, _ := cmd.Flags().GetString("temp-dir"); tempDir != "" {
+			expanded, err := config.ExpandHome(tempDir)
+			if err != nil {
+				cmd.SilenceUsage = true; return &exitCodeError{exitIO, err}
+			}
+			datastore.SetTempDir(expanded)
+		}
+		// New-store settings are applied even for commands that skip loading, such as init.
+		kdfName, _ := cmd.Flags().GetString("kdf")
+		if err := datastore.SetNewStoreKDF(kdfName); err != nil {
+			cmd.SilenceUsage = true; return usageError(fmt.Errorf("invalid value for --kdf: %w", err))
+		}
+		cipherName, _ := cmd.Flags().GetString("cipher")
+		if err := datastore.SetNewStoreCipher(cipherName); err != nil {
+			cmd.SilenceUsage = true; return usageError(fmt.Errorf("invalid value for --cipher: %w", err))
+		}
+		argon2Params, err := argon2ParamsFromFlags(cmd)
+		if err == nil { err = datastore.SetNewStoreArgon2Params(argon2Params) }
+		if err != nil { cmd.SilenceUsage = true; return usageError(err) }
+		minLength, _ := cmd.Flags().GetInt("min-passphrase-length")
+		allowWeak, _ := cmd.Flags().GetBool("allow-weak-passphrase")
+		if err := datastore.SetPassphrasePolicy(minLength, allowWeak); err != nil {
+			cmd.SilenceUsage = true; return usageError(fmt.Errorf("invalid value for --min-passphrase-length: %w", err))
+		}
+		forceSave, _ := cmd.Flags().GetBool("force-save")
+		datastore.SetForceSave(forceSave)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		datastore.SetDryRun(dryRun)
+		datastore.OnDryRunSave = func(saved, pending map[string]types.Satellite) { printDryRunChanges(cmd.OutOrStdout(), saved, pending) }
+		if skipsDatastore(cmd) {
+			return nil
+		}
+		return loadDatastore(cmd)
 	},
 }
 
+// loadDatastore loads and unlocks the datastore as the root pre-run does for every command that
+// does not skip it; commands that only sometimes need the datastore call it themselves.
+func loadDatastore(cmd *cobra.Command) error {
+	attempts, _ := cmd.Flags().GetInt("passphrase-attempts")
+	if err := datastore.SetPassphraseAttempts(attempts); err != nil {
+		cmd.SilenceUsage = true; return usageError(fmt.Errorf("invalid value for --passphrase-attempts: %w", err))
+	}
+	if err := datastore.InitCtx(cmd.Context()); err != nil {
+		if errors.Is(err, datastore.ErrCorrupted) || (!strings.Contains(err.Error(), "passphrase") && !strings.Contains(err.Error(), "decrypt") && !os.IsNotExist(err)) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Critical error during datastore initialization: %v\n", err)
+			if exitCodeFor(err) == 1 { err = &exitCodeError{exitIO, err} } // Failing to open or read the datastore is an I/O error
+			cmd.SilenceUsage = true; return err
+		}
+		// Non-critical init errors (like passphrase prompt failed for non-existent file) are handled by datastore.Init printing a notice.
+		// Individual commands will check datastore.IsUnlocked().
+	}
+	return nil
+}
+
+// noDatastoreMessage is printed by read-only commands when no datastore file exists yet.
+const noDatastoreMessage = "No datastore found. Add your first satellite with 'satcli add ...'."
+
 var queryCmd = &cobra.Command{
 	Use:   "query",
 	Short: "Query satellites based on specified criteria from the secure datastore",
 	Long: `Query satellites from the local, secure datastore using a combination of criteria.
 If ` + config.PassphraseEnvVar + ` is not set, you will be prompted for the passphrase.
-Supports filtering by operator, status, orbit type, launch dates, constellation status, and altitude.
-Output can be formatted as JSON (default), table, or an interactive TUI.
+Supports filtering by operator, status, orbit type, launch dates, age, constellation status, altitude, and semi-major axis.
+Archived records are excluded unless --include-archived or --archived-only is given.
+--min-longitude/--max-longitude select GEO and GSO satellites by slot longitude (degrees east); a
+--max-longitude below --min-longitude selects the range across the antimeridian.
+--tag (repeatable) selects satellites with any of the given tags (see 'satcli tag'), or all of them
+with --tag-mode all.
+Output can be formatted as JSON (default), table, a Markdown table, or an interactive TUI.
 
 Examples:
   satcli query --operator ESA --status active --orbit-type LEO --output tui
-  satcli query --launch-after 2022-01-01 --constellation true --output table`,
+  satcli query --launch-after 2022-01-01 --constellation-only --output table
+  satcli query --profile leo-active --operator SpaceX
+  satcli query --altitude-band meo --output table
+  satcli query --min-sma 6900 --max-sma 7000
+  satcli query --min-longitude -30 --max-longitude 30 --output table
+  satcli query --custom cost-center=ops
+  satcli query --tag watchlist --tag legacy --tag-mode all --output table
+  satcli query --operator ESA --min-altitude 500 --max-altitude 600 --explain-query
+  satcli query --min-age 10 --columns name,operator,launchDate,age --output table`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.Exists() {
+			fmt.Fprintln(cmd.OutOrStdout(), noDatastoreMessage)
+			return nil
+		}
 		if !datastore.IsUnlocked() {
-			return fmt.Errorf("datastore not accessible. Passphrase not provided or was incorrect. Set %s or enter correct passphrase at prompt.", config.PassphraseEnvVar)
+			return errDatastoreLocked()
 		}
-		satsMap, err := datastore.GetSatellites()
-		if err != nil {
-			return fmt.Errorf("failed to get satellites: %w", err)
+		if profileName, _ := cmd.Flags().GetString("profile"); profileName != "" {
+			if err := applyProfile(cmd, profileName); err != nil { cmd.SilenceUsage = true; return err }
 		}
-
-		operatorFilter, _ := cmd.Flags().GetString("operator")
-		statusFilter, _ := cmd.Flags().GetString("status")
-		orbitTypeFilter, _ := cmd.Flags().GetString("orbit-type")
-		launchAfterStr, _ := cmd.Flags().GetString("launch-after")
-		launchBeforeStr, _ := cmd.Flags().GetString("launch-before")
-		constellationStr, _ := cmd.Flags().GetString("constellation")
-		minAltitude, _ := cmd.Flags().GetFloat64("min-altitude")
-		maxAltitude, _ := cmd.Flags().GetFloat64("max-altitude")
+		filter, errFilter := filterFromFlags(cmd)
+		if errFilter != nil { cmd.SilenceUsage = true; return errFilter }
 		outputFormat, _ := cmd.Flags().GetString("output")
+		watchInterval, _ := cmd.Flags().GetDuration("watch")
+		if _, errRedact := redactFromFlags(cmd, nil); errRedact != nil { cmd.SilenceUsage = true; return errRedact }
+		if _, errTruncate := truncateFromFlags(cmd); errTruncate != nil { cmd.SilenceUsage = true; return errTruncate }
 
-		var launchAfterDate, launchBeforeDate time.Time
-		if launchAfterStr != "" {
-			var errParse error
-			launchAfterDate, errParse = time.Parse(config.DateFormat, launchAfterStr)
-			if errParse != nil { cmd.SilenceUsage = true; return fmt.Errorf("invalid format for --launch-after: '%s'. Use YYYY-MM-DD. (Details: %w)", launchAfterStr, errParse) }
-		}
-		if launchBeforeStr != "" {
-			var errParse error
-			launchBeforeDate, errParse = time.Parse(config.DateFormat, launchBeforeStr)
-			if errParse != nil { cmd.SilenceUsage = true; return fmt.Errorf("invalid format for --launch-before: '%s'. Use YYYY-MM-DD. (Details: %w)", launchBeforeStr, errParse) }
+		if watchInterval < 0 {
+			cmd.SilenceUsage = true; return fmt.Errorf("--watch interval must be positive")
 		}
-		if !launchAfterDate.IsZero() && !launchBeforeDate.IsZero() && launchAfterDate.After(launchBeforeDate) {
-			cmd.SilenceUsage = true; return fmt.Errorf("--launch-after date (%s) cannot be after --launch-before date (%s)", launchAfterStr, launchBeforeStr)
+		if watchInterval > 0 && strings.ToLower(outputFormat) != "table" {
+			cmd.SilenceUsage = true; return fmt.Errorf("--watch is only supported with --output table")
 		}
-		if minAltitude > 0 && maxAltitude > 0 && minAltitude > maxAltitude {
-			cmd.SilenceUsage = true; return fmt.Errorf("--min-altitude (%.0f) cannot be greater than --max-altitude (%.0f)", minAltitude, maxAltitude)
+		if explain, _ := cmd.Flags().GetBool("explain-query"); explain {
+			sortBy, _ := cmd.Flags().GetString("sort-by")
+			printQueryExplanation(cmd.ErrOrStderr(), filterClauses(filter), sortBy)
 		}
 
-		var filteredSatellites []types.Satellite
-		for _, sat := range satsMap {
-			matches := true
-			if operatorFilter != "" && !strings.EqualFold(sat.Operator, operatorFilter) { matches = false }
-			if matches && statusFilter != "" && !strings.EqualFold(sat.Status, statusFilter) { matches = false }
-			if matches && orbitTypeFilter != "" && !strings.EqualFold(sat.OrbitType, orbitTypeFilter) { matches = false }
-			if matches && (launchAfterStr != "" || launchBeforeStr != "") {
-				satLaunchDate, errDateParse := time.Parse(config.DateFormat, sat.LaunchDate)
-				if errDateParse != nil { matches = false
-				} else {
-					if !launchAfterDate.IsZero() && satLaunchDate.Before(launchAfterDate) { matches = false }
-					if matches && !launchBeforeDate.IsZero() && satLaunchDate.After(launchBeforeDate) { matches = false }
-				}
+		runQuery := func() error {
+			satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get satellites: %w", err)
 			}
-			if !matches { continue }
-			if constellationStr != "" {
-				constellationFilterVal, errBoolParse := strconv.ParseBool(constellationStr)
-				if errBoolParse != nil { cmd.SilenceUsage = true; return fmt.Errorf("invalid value for --constellation: '%s'. Use 'true' or 'false'", constellationStr) }
-				if sat.Constellation != constellationFilterVal { matches = false }
+
+			satList := make([]types.Satellite, 0, len(satsMap))
+			for _, sat := range satsMap {
+				satList = append(satList, sat)
 			}
-			if !matches { continue }
-			if minAltitude > 0 && sat.Altitude < minAltitude { matches = false }
-			if matches && maxAltitude > 0 && sat.Altitude > maxAltitude { matches = false }
-			if matches { filteredSatellites = append(filteredSatellites, sat) }
-		}
-		sort.Slice(filteredSatellites, func(i, j int) bool { return filteredSatellites[i].Name < filteredSatellites[j].Name })
+			filteredSatellites := FilterSatellites(satList, filter)
+			report := unparsableDateReport(satList, filter)
+			if report.SkippedDates > 0 {
+				noticef("Warning: %d record(s) with unparsable launch dates were excluded by --strict-dates.\n", report.SkippedDates)
+			}
+			if report.SkippedAges > 0 {
+				noticef("Warning: %d record(s) with unparsable launch dates were excluded by --min-age/--max-age.\n", report.SkippedAges)
+			}
+			if len(report.UndatedNames) > 0 {
+				noticef("Warning: %d record(s) with unparsable launch dates were included without date filtering: %s (use --strict-dates to exclude them)\n", len(report.UndatedNames), strings.Join(report.UndatedNames, ", "))
+			}
+			sortBy, _ := cmd.Flags().GetString("sort-by")
+			if err := sortSatellites(filteredSatellites, sortBy); err != nil { cmd.SilenceUsage = true; return err }
 
-		if len(filteredSatellites) == 0 {
-			fmt.Println("No satellites found matching specified criteria.")
-			return nil
-		}
-		
-		fmt.Printf("Found %d matching satellite(s).\n", len(filteredSatellites))
-		switch strings.ToLower(outputFormat) {
-		case "tui":
-			model := tui.NewListModel(filteredSatellites) // From tui package
-			p := tea.NewProgram(model, tea.WithAltScreen())
-			if _, errRun := p.Run(); errRun != nil {
-				return fmt.Errorf("error running TUI: %w", errRun)
+			if len(filteredSatellites) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No satellites found matching specified criteria.")
+				return nil
 			}
-		case "table":
-			printSatellitesTable(filteredSatellites)
-		default: // JSON
-			output, errJson := json.MarshalIndent(filteredSatellites, "", "  ")
-			if errJson != nil { return fmt.Errorf("failed to marshal filtered satellites to JSON: %w", errJson) }
-			fmt.Println(string(output))
+		
+			fmt.Fprintf(cmd.OutOrStdout(), "Found %d matching satellite(s).\n", len(filteredSatellites))
+			return renderSatellites(cmd, filteredSatellites)
 		}
-		return nil
+		if watchInterval > 0 {
+			return watchQuery(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), watchInterval, runQuery)
+		}
+		return runQuery()
 	},
 }
 
 var addCmd = &cobra.Command{
 	Use:   "add [name] [operator] [status] [orbitType]",
 	Short: "Add a new satellite record to the secure datastore",
-	Long:  "Adds a new satellite with essential information. If " + config.PassphraseEnvVar + " is not set, you will be prompted.",
-	Args:  cobra.ExactArgs(4),
+	Long: `Adds a new satellite with essential information. If ` + config.PassphraseEnvVar + ` is not set, you will be prompted.
+With --stdin, reads one or more newline-delimited JSON satellite objects from stdin and adds them all in one save.
+--set attaches organization-specific custom attributes (repeatable; stored under "custom").
+With --prompt-missing-fields in a terminal, asks for the altitude, launch date and inclination instead
+of defaulting them (Enter keeps the default shown); without a terminal the flag is ignored.
+--warn-inconsistent warns when the altitude does not fit the orbit type (see 'satcli validate').
+
+Examples:
+  satcli add ISS NASA active LEO
+  satcli add ISS NASA active LEO --prompt-missing-fields
+  satcli add ISS NASA active LEO --set cost-center=ops --set owner=alice
+  echo '{"name":"X","operator":"ESA","orbitType":"LEO"}' | satcli add --stdin`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if fromStdin, _ := cmd.Flags().GetBool("stdin"); fromStdin {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(4)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !datastore.IsUnlocked() {
-			return fmt.Errorf("datastore not accessible. Passphrase not provided or was incorrect. Set %s or enter correct passphrase at prompt.", config.PassphraseEnvVar)
+			return errDatastoreLocked()
+		}
+		setPairs, _ := cmd.Flags().GetStringArray("set")
+		promptMissing, _ := cmd.Flags().GetBool("prompt-missing-fields")
+		if fromStdin, _ := cmd.Flags().GetBool("stdin"); fromStdin {
+			if len(setPairs) > 0 { cmd.SilenceUsage = true; return fmt.Errorf("--set cannot be combined with --stdin; include a \"custom\" object in the JSON instead") }
+			if promptMissing { cmd.SilenceUsage = true; return fmt.Errorf("--prompt-missing-fields cannot be combined with --stdin") }
+			return addFromStdin(cmd, os.Stdin)
 		}
 		name, operator, status, orbitType := args[0], args[1], args[2], args[3]
 		if name == "" { cmd.SilenceUsage = true; return fmt.Errorf("satellite name cannot be empty") }
+		custom, err := parseCustomPairs("--set", setPairs)
+		if err != nil { cmd.SilenceUsage = true; return err }
 
+		if normalized := types.NormalizeOperator(operator); normalized != operator {
+			noticef("Notice: operator '%s' recorded as '%s' (operator-aliases).\n", operator, normalized)
+			operator = normalized
+		}
 		newSat := types.Satellite{
 			Name: name, Operator: operator, Status: status, OrbitType: orbitType,
 			// Consider prompting for more fields or using flags for a richer 'add' experience
 			LaunchDate: time.Now().Format(config.DateFormat), // Default launch date to today
+			Custom:     custom,
+		}
+		if promptMissing {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				noticef("Notice: --prompt-missing-fields ignored: not running in a terminal.\n")
+			} else if err := promptMissingFields(os.Stdin, cmd.ErrOrStderr(), &newSat); err != nil {
+				cmd.SilenceUsage = true; return err
+			}
 		}
+		warnIfOrbitInconsistent(cmd, newSat)
 		if err := datastore.AddSatellite(newSat); err != nil { // Pass the whole struct
 			cmd.SilenceUsage = true 
 			return err // AddSatellite will give specific error (e.g., duplicate)
 		}
-		if err := datastore.Save(); err != nil {
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
 			return fmt.Errorf("failed to save record for '%s': %w", name, err)
 		}
-		fmt.Printf("Record added: %s (encrypted in datastore)\n", name)
+		fmt.Fprintf(cmd.OutOrStdout(), "Record added: %s (encrypted in datastore)\n", name)
 		return nil
 	},
 }
@@ -208,40 +283,71 @@ var addCmd = &cobra.Command{
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all satellite records from the secure datastore",
-	Long:  "Retrieves and displays all satellite records. If " + config.PassphraseEnvVar + " is not set, you will be prompted.",
+	Long: `Retrieves and displays all satellite records. If ` + config.PassphraseEnvVar + ` is not set, you will be prompted.
+More than --max-results records (default 100000) are refused unless --force is given, as they would be
+built into one document in memory; --limit keeps the first records after sorting, and --output ndjson
+streams one JSON record per line without a cap. --first N and --last N show the first or last N
+records in the --sort-by order, so --sort-by launch-date --last 3 shows the three latest launches.
+--sort-by takes a comma-separated list of keys, each breaking ties in the one before and optionally
+suffixed :desc to reverse it, so operator,altitude:desc groups by operator with the highest first.
+--output summary prints one line per operator
+(records, how many are active, and orbit types), and summary-json the same rollup as JSON.
+Archived records (see 'satcli archive') are left out unless --include-archived or --archived-only
+is given. --redact operator,missionObjective shows those fields as REDACTED in every output format.
+
+Examples:
+  satcli list --output table
+  satcli list --sort-by launch-date --limit 20
+  satcli list --sort-by launch-date --last 3 --output table
+  satcli list --sort-by operator,altitude:desc --output table
+  satcli list --output ndjson > satellites.ndjson
+  satcli list --archived-only --output table
+  satcli list --output summary`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.Exists() {
+			fmt.Fprintln(cmd.OutOrStdout(), noDatastoreMessage)
+			return nil
+		}
 		if !datastore.IsUnlocked() {
-			return fmt.Errorf("datastore not accessible. Passphrase not provided or was incorrect. Set %s or enter correct passphrase at prompt.", config.PassphraseEnvVar)
+			return errDatastoreLocked()
 		}
-		satsMap, err := datastore.GetSatellites()
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("failed to get satellites: %w", err)
 		}
         if len(satsMap) == 0 {
-             fmt.Println("Datastore is accessible but contains no satellite records.")
+             fmt.Fprintln(cmd.OutOrStdout(), "Datastore is accessible but contains no satellite records.")
              return nil
         }
-		outputFormat, _ := cmd.Flags().GetString("output")
+		archivedFilter, err := archivedFilterFromFlags(cmd)
+		if err != nil { cmd.SilenceUsage = true; return err }
 		var satList []types.Satellite
-		for _, sat := range satsMap { satList = append(satList, sat) }
-		sort.Slice(satList, func(i, j int) bool { return satList[i].Name < satList[j].Name })
+		hidden := 0
+		for _, sat := range satsMap {
+			if archivedFilter != nil && sat.Archived != *archivedFilter { hidden++; continue }
+			satList = append(satList, sat)
+		}
+		if hidden > 0 && !*archivedFilter {
+			noticef("Notice: %d archived record(s) hidden; use --include-archived to show them.\n", hidden)
+		}
+		sortBy, _ := cmd.Flags().GetString("sort-by")
+		if err := sortSatellites(satList, sortBy); err != nil { cmd.SilenceUsage = true; return err }
+		if _, err := altitudeUnitFromFlags(cmd); err != nil { cmd.SilenceUsage = true; return err }
+		if _, err := dateFormatFromFlags(cmd); err != nil { cmd.SilenceUsage = true; return err }
+		if _, err := redactFromFlags(cmd, nil); err != nil { cmd.SilenceUsage = true; return err }
+		if _, err := truncateFromFlags(cmd); err != nil { cmd.SilenceUsage = true; return err }
+		satList, err = firstOrLast(cmd, satList)
+		if err != nil { cmd.SilenceUsage = true; return err }
+		outputFormat, _ := cmd.Flags().GetString("output")
+		format := strings.ToLower(outputFormat)
+		streamed := format == "ndjson" || format == "kv" || format == "tui" || format == "summary" || format == "summary-json" // Never one big document
+		satList, err = limitResults(cmd, satList, streamed, "use --limit, --output ndjson to stream the records, or --force")
+		if err != nil { cmd.SilenceUsage = true; return err }
 		
-		fmt.Printf("Total records: %d.\n", len(satList))
-		switch strings.ToLower(outputFormat) {
-		case "tui":
-			model := tui.NewListModel(satList) // From tui package
-			p := tea.NewProgram(model, tea.WithAltScreen())
-			if _, errRun := p.Run(); errRun != nil {
-				return fmt.Errorf("error running TUI: %w", errRun)
-			}
-		case "table":
-			printSatellitesTable(satList)
-		default: // JSON
-			output, errJson := json.MarshalIndent(satList, "", "  ")
-			if errJson != nil { return fmt.Errorf("failed to marshal satellite list to JSON: %w", errJson) }
-			fmt.Println(string(output))
+		if format != "ndjson" && format != "kv" && format != "summary-json" { // Keep NDJSON, kv and the summary parsable
+			fmt.Fprintf(cmd.OutOrStdout(), "Total records: %d.\n", len(satList))
 		}
-		return nil
+		return renderSatellites(cmd, satList)
 	},
 }
 
@@ -249,52 +355,281 @@ var explainCmd = &cobra.Command{
 	Use:   "explain [category] [term]",
 	Short: "Explain a specific term or concept related to satellites.",
 	Long: `Provides a definition or explanation for various terms. Currently supports explaining 'orbit' types.
+Without a term, the supported orbit types are listed with a one-line summary each.
+Use --output json for the structured reference record, and --all to show every orbit type in full.
+Use --raw for one unlabeled line of plain text per orbit type, for embedding in other documents.
+Use --compare for a matrix of altitude, period, latency, coverage and typical uses across all orbit
+types (with --output json, one object per orbit type).
 Examples:
+  satcli explain orbit
   satcli explain orbit LEO
-  satcli explain orbit GEO`,
-	Args: cobra.ExactArgs(2),
+  satcli explain orbit GEO --output json
+  satcli explain orbit LEO --raw
+  satcli explain orbit --all
+  satcli explain orbit --compare --output json`,
+	Args:        cobra.RangeArgs(1, 2),
+	Annotations: map[string]string{skipDatastoreAnnotation: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		category := strings.ToLower(args[0])
-		term := strings.ToUpper(args[1])
-		if category == "orbit" {
-			if explanation, found := orbitExplanations[term]; found {
-				fmt.Println(explanation)
-			} else {
-				fmt.Fprintf(os.Stderr, "Error: Unknown orbit type: %s\n", term)
-				fmt.Fprintln(os.Stderr, "Supported orbit types are:")
-				var supportedOrbits []string
-				for k := range orbitExplanations { supportedOrbits = append(supportedOrbits, k) }
-				sort.Strings(supportedOrbits)
-				for _, t := range supportedOrbits { fmt.Fprintf(os.Stderr, "  - %s\n", t) }
-				cmd.SilenceUsage = true; return fmt.Errorf("explanation not found for orbit type '%s'", args[1])
+		all, _ := cmd.Flags().GetBool("all")
+		outputFormat, _ := cmd.Flags().GetString("output")
+		raw, _ := cmd.Flags().GetBool("raw")
+		compare, _ := cmd.Flags().GetBool("compare")
+		if category != "orbit" {
+			cmd.SilenceUsage = true; return fmt.Errorf("unknown category for explanation: '%s'. Currently, only 'orbit' category is supported", category)
+		}
+		if all && len(args) == 2 {
+			return fmt.Errorf("--all cannot be combined with a term")
+		}
+		if raw && strings.ToLower(outputFormat) == "json" {
+			return fmt.Errorf("--raw cannot be combined with --output json")
+		}
+		if compare {
+			if all || raw || len(args) == 2 {
+				return fmt.Errorf("--compare cannot be combined with a term, --all or --raw")
+			}
+			return printOrbitComparison(cmd.OutOrStdout(), outputFormat)
+		}
+		if !all && len(args) < 2 && strings.ToLower(outputFormat) != "json" && !raw {
+			for _, name := range types.OrbitNames() {
+				info, _ := types.LookupOrbit(name)
+				fmt.Fprintf(cmd.OutOrStdout(), "%-5s %s\n", info.Name, info.Summary())
 			}
+			return nil
+		}
+		all = all || len(args) < 2 // The JSON and raw forms of the listing are those of --all
+
+		var orbits []types.OrbitInfo
+		if all {
+			orbits = types.Orbits
 		} else {
-			cmd.SilenceUsage = true; return fmt.Errorf("unknown category for explanation: '%s'. Currently, only 'orbit' category is supported", category)
+			info, found := types.LookupOrbit(args[1])
+			if !found {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error: Unknown orbit type: %s\n", strings.ToUpper(args[1]))
+				fmt.Fprintln(cmd.ErrOrStderr(), "Supported orbit types are:")
+				for _, t := range types.OrbitNames() { fmt.Fprintf(cmd.ErrOrStderr(), "  - %s\n", t) }
+				cmd.SilenceUsage = true; return &exitCodeError{exitNotFound, fmt.Errorf("explanation not found for orbit type '%s'", args[1])}
+			}
+			orbits = []types.OrbitInfo{info}
+		}
+
+		if strings.ToLower(outputFormat) == "json" {
+			var v interface{} = orbits
+			if !all {
+				v = orbits[0]
+			}
+			output, errJson := json.MarshalIndent(v, "", "  ")
+			if errJson != nil { return fmt.Errorf("failed to marshal orbit data to JSON: %w", errJson) }
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+		for i, info := range orbits {
+			if raw {
+				fmt.Fprintln(cmd.OutOrStdout(), formatOrbitInfoRaw(info))
+				continue
+			}
+			if i > 0 { fmt.Fprintln(cmd.OutOrStdout()) }
+			fmt.Fprintln(cmd.OutOrStdout(), formatOrbitInfo(info))
 		}
 		return nil
 	},
 }
 
+// orbitComparison is one row of 'explain orbit --compare'.
+type orbitComparison struct {
+	Name             string   `json:"name"`
+	AltitudeMinKm    float64  `json:"altitudeMinKm"`
+	AltitudeMaxKm    float64  `json:"altitudeMaxKm"`
+	PeriodMinMinutes float64  `json:"periodMinMinutes"`
+	PeriodMaxMinutes float64  `json:"periodMaxMinutes"`
+	LatencyMinMs     float64  `json:"latencyMinMs"`
+	LatencyMaxMs     float64  `json:"latencyMaxMs"`
+	Coverage         string   `json:"coverage"`
+	TypicalUses      []string `json:"typicalUses"`
+}
+
+// printOrbitComparison writes the comparable figures of every orbit type as a table, or with
+// outputFormat json as an array of orbitComparison.
+func printOrbitComparison(out io.Writer, outputFormat string) error {
+	rows := make([]orbitComparison, 0, len(types.Orbits))
+	for _, o := range types.Orbits {
+		rows = append(rows, orbitComparison{o.Name, o.AltitudeMinKm, o.AltitudeMaxKm, o.PeriodMinMinutes, o.PeriodMaxMinutes,
+			o.LatencyMinMs, o.LatencyMaxMs, o.Coverage, o.TypicalUses})
+	}
+	if strings.ToLower(outputFormat) == "json" {
+		output, errJson := json.MarshalIndent(rows, "", "  ")
+		if errJson != nil { return fmt.Errorf("failed to marshal orbit comparison to JSON: %w", errJson) }
+		fmt.Fprintln(out, string(output))
+		return nil
+	}
+	columns := []tableColumn{{Header: "ORBIT"}, {Header: "ALTITUDE (km)"}, {Header: "PERIOD"}, {Header: "LATENCY (ms)"}, {Header: "COVERAGE"}, {Header: "TYPICAL USES"}}
+	var cells [][]string
+	for _, r := range rows {
+		altitude := "n/a"
+		if r.AltitudeMaxKm > 0 {
+			altitude = formatAltitudeRange(r.AltitudeMinKm, r.AltitudeMaxKm)
+		}
+		period := formatOrbitPeriod(r.PeriodMinMinutes)
+		if r.PeriodMaxMinutes != r.PeriodMinMinutes {
+			period += "-" + formatOrbitPeriod(r.PeriodMaxMinutes)
+		}
+		latency := fmt.Sprintf("%.0f", r.LatencyMinMs)
+		if r.LatencyMaxMs != r.LatencyMinMs {
+			latency += fmt.Sprintf("-%.0f", r.LatencyMaxMs)
+		}
+		cells = append(cells, []string{r.Name, altitude, period, latency, r.Coverage, strings.Join(r.TypicalUses, ", ")})
+	}
+	printTableRows(out, columns, cells)
+	return nil
+}
+
+// formatOrbitPeriod renders a period in minutes, hours or days, whichever reads best.
+func formatOrbitPeriod(minutes float64) string {
+	switch {
+	case minutes < 180:
+		return fmt.Sprintf("%.0f min", minutes)
+	case minutes < 3*24*60:
+		return fmt.Sprintf("%.1f h", minutes/60)
+	}
+	return fmt.Sprintf("%.0f d", minutes/(24*60))
+}
+
+// orbitInfoSections lists an orbit's explanation texts with their labels, in display order.
+func orbitInfoSections(info types.OrbitInfo) []struct{ label, text string } {
+	return []struct{ label, text string }{
+		{"Altitude", info.Altitude},
+		{"Period", info.PeriodRange},
+		{"Characteristics", info.Characteristics},
+		{"Uses", info.Uses},
+		{"Pros", info.Pros},
+		{"Cons", info.Cons},
+		{"Note", info.Note},
+	}
+}
+
+// formatOrbitInfo renders an orbit's reference data as the human-readable explanation.
+func formatOrbitInfo(info types.OrbitInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s):", info.FullName, info.Name)
+	for _, line := range orbitInfoSections(info) {
+		if line.text != "" {
+			fmt.Fprintf(&b, "\n  %s: %s", line.label, line.text)
+		}
+	}
+	return b.String()
+}
+
+// formatOrbitInfoRaw renders the explanation as a single line without labels: the full name, then
+// each text with its whitespace collapsed and a closing period added where missing.
+func formatOrbitInfoRaw(info types.OrbitInfo) string {
+	parts := []string{fmt.Sprintf("%s (%s).", info.FullName, info.Name)}
+	for _, line := range orbitInfoSections(info) {
+		text := strings.Join(strings.Fields(line.text), " ")
+		if text == "" {
+			continue
+		}
+		if !strings.HasSuffix(text, ".") {
+			text += "."
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// queryFilterFlags are the query flags that select satellites, and so can be saved in a profile.
+var queryFilterFlags = []string{"operator", "status", "orbit-type", "launch-after", "launch-before", "constellation", "constellation-only", "exclude-constellation", "normalize-operators", "include-archived", "archived-only", "min-altitude", "max-altitude", "altitude-band", "min-perigee", "max-apogee", "min-sma", "max-sma", "min-longitude", "max-longitude", "min-age", "max-age"}
+
+// addQueryFilterFlags registers queryFilterFlags on cmd.
+func addQueryFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("operator", "o", "", "Filter by satellite operator (case-insensitive)")
+	cmd.Flags().Bool("normalize-operators", false, "Match --operator through the config file's operator-aliases, so any known spelling matches")
+	cmd.Flags().StringP("status", "s", "", "Filter by satellite status (case-insensitive)")
+	cmd.Flags().StringP("orbit-type", "t", "", "Filter by orbit type (e.g., LEO, GEO; case-insensitive)")
+	_ = cmd.RegisterFlagCompletionFunc("orbit-type", completeOrbitTypes)
+	cmd.Flags().String("launch-after", "", "Filter satellites launched after this date (YYYY-MM-DD)")
+	cmd.Flags().String("launch-before", "", "Filter satellites launched before this date (YYYY-MM-DD)")
+	cmd.Flags().String("constellation", "", "Filter by constellation status ('true' or 'false')")
+	addConstellationShorthandFlags(cmd)
+	cmd.Flags().Float64("min-altitude", 0, "Filter by minimum altitude in --altitude-unit, default km (0 means no filter)")
+	cmd.Flags().Float64("max-altitude", 0, "Filter by maximum altitude in --altitude-unit, default km (0 means no filter)")
+	cmd.Flags().Float64("min-perigee", 0, "Filter by minimum perigee altitude in --altitude-unit (0 means no filter)")
+	cmd.Flags().Float64("max-apogee", 0, "Filter by maximum apogee altitude in --altitude-unit (0 means no filter)")
+	cmd.Flags().Float64("min-sma", 0, "Filter by minimum semi-major axis in km, derived from altitude when not recorded (0 means no filter)")
+	cmd.Flags().Float64("max-sma", 0, "Filter by maximum semi-major axis in km, derived from altitude when not recorded (0 means no filter)")
+	cmd.Flags().Float64("min-longitude", -180, "Filter GEO/GSO satellites by minimum slot longitude in degrees east (other orbits never match)")
+	cmd.Flags().Float64("max-longitude", 180, "Filter GEO/GSO satellites by maximum slot longitude in degrees east; below --min-longitude wraps across 180")
+	cmd.Flags().Float64("min-age", 0, "Filter by minimum years in orbit since the launch date (0 means no filter)")
+	cmd.Flags().Float64("max-age", 0, "Filter by maximum years in orbit since the launch date (0 means no filter)")
+	cmd.Flags().String("altitude-band", "", "Filter by the altitude range of an orbit type ("+strings.Join(altitudeBandNames(), ", ")+"); replaces --min/--max-altitude")
+	addArchivedFlags(cmd)
+}
+
 func init() {
-	queryCmd.Flags().StringP("operator", "o", "", "Filter by satellite operator (case-insensitive)")
-	queryCmd.Flags().StringP("status", "s", "", "Filter by satellite status (case-insensitive)")
-	queryCmd.Flags().StringP("orbit-type", "t", "", "Filter by orbit type (e.g., LEO, GEO; case-insensitive)")
-	queryCmd.Flags().String("launch-after", "", "Filter satellites launched after this date (YYYY-MM-DD)")
-	queryCmd.Flags().String("launch-before", "", "Filter satellites launched before this date (YYYY-MM-DD)")
-	queryCmd.Flags().String("constellation", "", "Filter by constellation status ('true' or 'false')")
-	queryCmd.Flags().Float64("min-altitude", 0, "Filter by minimum altitude in km (0 means no filter)")
-	queryCmd.Flags().Float64("max-altitude", 0, "Filter by maximum altitude in km (0 means no filter)")
-	queryCmd.Flags().StringP("output", "O", "json", "Output format: json, table, or tui")
-
-	listCmd.Flags().StringP("output", "O", "json", "Output format: json, table, or tui")
+	rootCmd.PersistentFlags().String("kdf", "argon2id", "Key derivation function for new datastores: argon2id or scrypt (existing datastores keep theirs)")
+	rootCmd.PersistentFlags().Uint32("argon2-time", 0, "Argon2id time cost (passes) for new datastores, with --argon2-memory and --argon2-threads (see 'satcli crypto calibrate'; 0 keeps the built-in cost)")
+	rootCmd.PersistentFlags().Uint32("argon2-memory", 0, "Argon2id memory cost in MiB for new datastores (0 keeps the built-in cost)")
+	rootCmd.PersistentFlags().Uint8("argon2-threads", 0, "Argon2id parallelism for new datastores (0 keeps the built-in cost)")
+	rootCmd.PersistentFlags().String("cipher", "aes-gcm", "Cipher for new datastores: aes-gcm or chacha20-poly1305 (existing datastores keep theirs)")
+	rootCmd.PersistentFlags().Int("min-passphrase-length", datastore.DefaultMinPassphraseLength, "Shortest passphrase accepted when creating a datastore (unlocking is not checked)")
+	rootCmd.PersistentFlags().Bool("allow-weak-passphrase", false, "Accept a short or repetitive passphrase when creating a datastore")
+	rootCmd.PersistentFlags().Int("passphrase-attempts", 3, "Times to prompt for the passphrase of an existing datastore before giving up (not retried when "+config.PassphraseEnvVar+" is set)")
+	rootCmd.PersistentFlags().Bool("force-save", false, "Save even if another process changed the datastore since it was loaded (their changes are lost)")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Make every change in memory and print what saving would change, but write nothing")
+	rootCmd.PersistentFlags().String("datastore", "", "Path to the encrypted datastore file, or s3://bucket/key for S3 (default: next to the satcli executable)")
+	rootCmd.PersistentFlags().String("temp-dir", "", "Directory for the temporary file written during saves (default: the datastore's directory)")
+	rootCmd.PersistentFlags().String("log-level", "warn", "Diagnostic log level on stderr: debug, info, warn, or error (env "+config.LogLevelEnvVar+")")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress non-error notices and warnings on stderr (env "+config.QuietEnvVar+")")
+	rootCmd.PersistentFlags().String("color", "auto", "Color output: auto, always, or never")
+	rootCmd.PersistentFlags().Int("tui-threshold", 5000, "Ask before loading more than this many satellites into --output tui (0 never asks)")
+	rootCmd.PersistentFlags().Bool("operator-colors", true, "Render each operator in a stable color in table and TUI output (requires color)")
+
+	addQueryFilterFlags(queryCmd)
+	queryCmd.Flags().String("profile", "", "Load filter flags from a saved profile (see 'satcli profile'); explicit flags override it")
+	queryCmd.Flags().StringP("output", "O", "json", "Output format: json, ndjson, kv, table, markdown, tree, summary, summary-json, or tui")
+
+	addQueryMatchFlags(queryCmd)
+	queryCmd.Flags().Bool("explain-query", false, "Print a summary of the active filters, match mode and sort order to stderr before running")
+	queryCmd.Flags().Duration("watch", 0, "Re-run the query every interval (e.g. 5s) until interrupted; table output only")
+	queryCmd.Flags().String("sort-by", "name", sortByUsage)
+	addTableColumnFlags(queryCmd)
+	addRedactFlag(queryCmd)
+	addTruncateFlag(queryCmd)
+	addGroupConstellationFlag(queryCmd)
+	addAltitudeUnitFlag(queryCmd)
+	addDateFormatFlag(queryCmd)
+
+	listCmd.Flags().StringP("output", "O", "json", "Output format: json, ndjson, kv, table, markdown, tree, summary, summary-json, or tui")
+	addResultCapFlags(listCmd)
+	addFirstLastFlags(listCmd)
+	addArchivedFlags(listCmd)
+	listCmd.Flags().String("sort-by", "name", sortByUsage)
+	addTableColumnFlags(listCmd)
+	addRedactFlag(listCmd)
+	addTruncateFlag(listCmd)
+	addGroupConstellationFlag(listCmd)
+	addAltitudeUnitFlag(listCmd)
+	addDateFormatFlag(listCmd)
     addCmd.Flags().Bool("encrypt-check", true, "dummy flag to ensure addCmd has one for example")
+	addCmd.Flags().Bool("stdin", false, "Read newline-delimited JSON satellite objects from stdin instead of positional args")
+	addCmd.Flags().StringArray("set", nil, "Set a custom attribute as key=value (repeatable)")
+	addCmd.Flags().Bool("prompt-missing-fields", false, "In a terminal, ask for altitude, launch date and inclination instead of defaulting them")
+	addCmd.Flags().Bool("warn-inconsistent", false, "Warn when the altitude does not fit the orbit type")
+
 
+	explainCmd.Flags().StringP("output", "O", "text", "Output format: text or json")
+	explainCmd.Flags().Bool("all", false, "Explain every term in the category")
+	explainCmd.Flags().Bool("raw", false, "Print each explanation as one unlabeled line of plain text")
+	explainCmd.Flags().Bool("compare", false, "Compare altitude, period, latency, coverage and typical uses across all orbit types")
 
 	rootCmd.AddCommand(queryCmd, addCmd, listCmd, explainCmd)
 }
 
 func main() {
+	markUsageErrors(rootCmd)
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		if isUnknownCommand(rootCmd, os.Args[1:]) {
+			os.Exit(exitUsage)
+		}
+		os.Exit(exitCodeFor(err))
 	}
 }
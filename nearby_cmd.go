@@ -0,0 +1,100 @@
+// cmd/satcli/nearby_cmd.go
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+var nearbyCmd = &cobra.Command{
+	Use:   "nearby [name]",
+	Short: "Find satellites in an orbit similar to the named satellite",
+	Long: `Lists the satellites whose altitude and inclination are both within the given tolerances of the
+named reference satellite, closest first. Closeness combines both differences, each scaled by its tolerance.
+
+Examples:
+  satcli nearby ISS
+  satcli nearby Hubble --altitude-tol 100 --inclination-tol 2 --output table`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		altitudeTol, _ := cmd.Flags().GetFloat64("altitude-tol")
+		inclinationTol, _ := cmd.Flags().GetFloat64("inclination-tol")
+		cmd.SilenceUsage = true
+		if altitudeTol < 0 || inclinationTol < 0 {
+			return fmt.Errorf("tolerances cannot be negative")
+		}
+
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		ref, ok, err := lookupSatellite(satsMap, args[0])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &exitCodeError{exitNotFound, fmt.Errorf("satellite '%s' not found", args[0])}
+		}
+
+		nearby, distances := findNearby(ref, satsMap, altitudeTol, inclinationTol)
+		if len(nearby) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "No satellites within %.0f km and %.2f deg of %s.\n", altitudeTol, inclinationTol, ref.Name)
+			return nil
+		}
+		sort.SliceStable(nearby, func(i, j int) bool {
+			if distances[nearby[i].Name] != distances[nearby[j].Name] {
+				return distances[nearby[i].Name] < distances[nearby[j].Name]
+			}
+			return nearby[i].Name < nearby[j].Name
+		})
+		fmt.Fprintf(cmd.OutOrStdout(), "Found %d satellite(s) near %s (%.0f km, %.2f deg).\n", len(nearby), ref.Name, ref.Altitude, ref.Inclination)
+		return renderSatellites(cmd, nearby)
+	},
+}
+
+// findNearby returns the satellites other than ref within both tolerances, and each one's
+// combined distance: the root sum of squares of the differences divided by their tolerances.
+func findNearby(ref types.Satellite, sats map[string]types.Satellite, altitudeTol, inclinationTol float64) ([]types.Satellite, map[string]float64) {
+	var nearby []types.Satellite
+	distances := make(map[string]float64)
+	for name, sat := range sats {
+		if name == ref.Name {
+			continue
+		}
+		dAlt := math.Abs(sat.Altitude - ref.Altitude)
+		dInc := math.Abs(sat.Inclination - ref.Inclination)
+		if dAlt > altitudeTol || dInc > inclinationTol {
+			continue
+		}
+		nearby = append(nearby, sat)
+		distances[name] = math.Hypot(scaledDiff(dAlt, altitudeTol), scaledDiff(dInc, inclinationTol))
+	}
+	return nearby, distances
+}
+
+// scaledDiff returns diff/tol; a zero tolerance only admits zero differences, which scale to 0.
+func scaledDiff(diff, tol float64) float64 {
+	if tol == 0 {
+		return 0
+	}
+	return diff / tol
+}
+
+func init() {
+	nearbyCmd.Flags().Float64("altitude-tol", 50, "Maximum altitude difference in km")
+	nearbyCmd.Flags().Float64("inclination-tol", 5, "Maximum inclination difference in degrees")
+	nearbyCmd.Flags().StringP("output", "O", "json", "Output format: json, ndjson, kv, table, markdown, tree, summary, summary-json, or tui")
+	addTableColumnFlags(nearbyCmd)
+	addRedactFlag(nearbyCmd)
+	addTruncateFlag(nearbyCmd)
+	rootCmd.AddCommand(nearbyCmd)
+}
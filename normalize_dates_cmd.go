@@ -0,0 +1,102 @@
+// cmd/satcli/normalize_dates_cmd.go
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+var normalizeDatesCmd = &cobra.Command{
+	Use:   "normalize-dates",
+	Short: "Rewrite launch dates in other layouts as YYYY-MM-DD",
+	Long: `Parses each record's launch date with the layouts date filters and sorting accept (YYYY-MM-DD,
+YYYY/MM/DD, YYYY.MM.DD, RFC3339, '2 Jan 2006' and 'Jan 2, 2006') and rewrites those not already
+YYYY-MM-DD in that form. Dates that match no layout are listed for manual correction with
+'satcli update-many' or 'satcli import'; empty dates are left alone.
+
+The changes are always previewed first. --dry-run stops after the preview; otherwise you are asked
+to confirm unless --yes is given.
+
+Examples:
+  satcli normalize-dates --dry-run
+  satcli normalize-dates --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		cmd.SilenceUsage = true
+
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		var updated []types.Satellite
+		var unparsable []string
+		for _, name := range sortedKeys(satsMap) {
+			sat := satsMap[name]
+			if sat.LaunchDate == "" {
+				continue
+			}
+			t, err := parseLaunchDate(sat.LaunchDate)
+			if err != nil {
+				unparsable = append(unparsable, name)
+				continue
+			}
+			if canonical := t.Format(config.DateFormat); canonical != sat.LaunchDate {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: launchDate %q -> %q\n", name, sat.LaunchDate, canonical)
+				sat.LaunchDate = canonical
+				updated = append(updated, sat)
+			}
+		}
+		if len(unparsable) > 0 {
+			sort.Strings(unparsable)
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %d launch date(s) match no known layout and need fixing by hand:\n", len(unparsable))
+			for _, name := range unparsable {
+				fmt.Fprintf(cmd.ErrOrStderr(), "  %s: %q\n", name, satsMap[name].LaunchDate)
+			}
+		}
+		if len(updated) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No changes: every parsable launch date is already YYYY-MM-DD.")
+			return nil
+		}
+		if dryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "Dry run: %d launch date(s) would be rewritten.\n", len(updated))
+			return nil
+		}
+		if !yes {
+			confirmed, err := confirm(fmt.Sprintf("Rewrite %d launch date(s)?", len(updated)))
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Fprintln(cmd.OutOrStdout(), "Aborted; nothing was changed.")
+				return nil
+			}
+		}
+
+		for _, sat := range updated {
+			if err := datastore.AddSatellite(sat); err != nil {
+				return err
+			}
+		}
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to save %d normalized record(s): %w", len(updated), err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Normalized %d launch date(s).\n", len(updated))
+		return nil
+	},
+}
+
+func init() {
+	normalizeDatesCmd.Flags().Bool("yes", false, "Apply the changes without asking for confirmation")
+	rootCmd.AddCommand(normalizeDatesCmd)
+}
@@ -0,0 +1,88 @@
+// types/operator.go
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// operatorAliases maps the normalized key of each known spelling (see operatorKey) to its canonical
+// operator name. Canonical names map to themselves.
+var operatorAliases = map[string]string{}
+
+// operatorKey folds case and runs of whitespace so that "european  space agency" matches
+// "European Space Agency".
+func operatorKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// SetOperatorAliases replaces the alias map with canonical, which lists the alternative spellings
+// of each canonical operator name (as in the config file's operator-aliases). A spelling claimed by
+// two operators is an error, and the previous map is kept.
+func SetOperatorAliases(canonical map[string][]string) error {
+	aliases := make(map[string]string)
+	claim := func(spelling, name string) error {
+		key := operatorKey(spelling)
+		if key == "" {
+			return fmt.Errorf("operator '%s' has an empty alias", name)
+		}
+		if other, ok := aliases[key]; ok && other != name {
+			return fmt.Errorf("'%s' is an alias of both '%s' and '%s'", spelling, other, name)
+		}
+		aliases[key] = name
+		return nil
+	}
+	names := make([]string, 0, len(canonical))
+	for name := range canonical {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := claim(name, strings.TrimSpace(name)); err != nil {
+			return err
+		}
+	}
+	for _, name := range names {
+		for _, alias := range canonical[name] {
+			if err := claim(alias, strings.TrimSpace(name)); err != nil {
+				return err
+			}
+		}
+	}
+	operatorAliases = aliases
+	return nil
+}
+
+// NormalizeOperator returns the canonical name for an operator spelling, matched ignoring case and
+// extra whitespace, or raw with surrounding whitespace trimmed if it has no alias.
+func NormalizeOperator(raw string) string {
+	if name, ok := operatorAliases[operatorKey(raw)]; ok {
+		return name
+	}
+	return strings.TrimSpace(raw)
+}
+
+// OperatorAlias is one entry of the active alias map.
+type OperatorAlias struct {
+	Alias    string `json:"alias"`
+	Operator string `json:"operator"`
+}
+
+// OperatorAliases returns the active alias map ordered by operator, then alias. Aliases are given
+// in their normalized (lowercase) form; canonical names mapping to themselves are left out.
+func OperatorAliases() []OperatorAlias {
+	var list []OperatorAlias
+	for alias, name := range operatorAliases {
+		if alias != operatorKey(name) {
+			list = append(list, OperatorAlias{Alias: alias, Operator: name})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Operator != list[j].Operator {
+			return list[i].Operator < list[j].Operator
+		}
+		return list[i].Alias < list[j].Alias
+	})
+	return list
+}
@@ -0,0 +1,125 @@
+// cmd/satcli/operators_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/tui"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+var operatorsCmd = &cobra.Command{
+	Use:   "operators",
+	Short: "List stored operator names, or the operator aliases in use",
+	Long: `Lists each operator spelling in the datastore with its record count and, when the config file's
+operator-aliases map it to another name, the canonical name 'satcli add' and 'satcli import' would
+now record. With --aliases, prints the alias map itself instead; that needs no passphrase.
+
+Aliases are configured in the config file (see 'satcli config path'):
+  operator-aliases:
+    ESA: [European Space Agency, ESA/ESOC]
+    SpaceX: [Space Exploration Technologies]
+
+Examples:
+  satcli operators
+  satcli operators --aliases --output json`,
+	Args: cobra.NoArgs,
+	// The alias map is read from the config file, so the datastore is only loaded for the listing.
+	Annotations: map[string]string{skipDatastoreAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		showAliases, _ := cmd.Flags().GetBool("aliases")
+		outputFormat, _ := cmd.Flags().GetString("output")
+		asJSON := strings.ToLower(outputFormat) == "json"
+		if showAliases {
+			cmd.SilenceUsage = true
+			aliases := types.OperatorAliases()
+			if asJSON {
+				if aliases == nil {
+					aliases = []types.OperatorAlias{}
+				}
+				output, errJson := json.MarshalIndent(aliases, "", "  ")
+				if errJson != nil {
+					return fmt.Errorf("failed to marshal operator aliases to JSON: %w", errJson)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(output))
+				return nil
+			}
+			if len(aliases) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No operator aliases configured. Add an operator-aliases map to the config file.")
+				return nil
+			}
+			rows := make([][]string, len(aliases))
+			for i, a := range aliases {
+				rows[i] = []string{a.Alias, a.Operator}
+			}
+			printTableRows(cmd.OutOrStdout(), []tableColumn{{Header: "ALIAS"}, {Header: "OPERATOR"}}, rows)
+			return nil
+		}
+
+		if err := loadDatastore(cmd); err != nil {
+			return err
+		}
+		if !datastore.Exists() {
+			fmt.Fprintln(cmd.OutOrStdout(), noDatastoreMessage)
+			return nil
+		}
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		cmd.SilenceUsage = true
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		counts := make(map[string]int)
+		for _, sat := range satsMap {
+			counts[sat.Operator]++
+		}
+
+		type operatorCount struct {
+			Operator   string `json:"operator"`
+			Records    int    `json:"records"`
+			Normalized string `json:"normalized,omitempty"`
+		}
+		list := make([]operatorCount, 0, len(counts))
+		for _, operator := range sortedKeys(counts) {
+			entry := operatorCount{Operator: operator, Records: counts[operator]}
+			if normalized := types.NormalizeOperator(operator); normalized != operator {
+				entry.Normalized = normalized
+			}
+			list = append(list, entry)
+		}
+		if asJSON {
+			output, errJson := json.MarshalIndent(list, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal operators to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+		rows := make([][]string, len(list))
+		for i, entry := range list {
+			name := entry.Operator
+			if strings.TrimSpace(name) == "" {
+				name = "(no operator)"
+			} else if colorOperators {
+				name = tui.OperatorStyle(name).Render(name)
+			}
+			rows[i] = []string{name, strconv.Itoa(entry.Records), entry.Normalized}
+		}
+		printTableRows(cmd.OutOrStdout(), []tableColumn{{Header: "OPERATOR"}, {Header: "RECORDS"}, {Header: "NORMALIZED"}}, rows)
+		return nil
+	},
+}
+
+func init() {
+	operatorsCmd.Flags().Bool("aliases", false, "Print the operator alias map from the config file instead of the stored operators")
+	operatorsCmd.Flags().StringP("output", "O", "table", "Output format: table or json")
+	rootCmd.AddCommand(operatorsCmd)
+}
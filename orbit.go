@@ -0,0 +1,218 @@
+// types/orbit.go
+package types
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OrbitInfo is reference data about an orbit type, used by the explain command.
+// Altitudes are in km above the Earth's surface; zero means not applicable.
+type OrbitInfo struct {
+	Name            string  `json:"name"`
+	FullName        string  `json:"fullName"`
+	AltitudeMinKm   float64 `json:"altitudeMinKm"`
+	AltitudeMaxKm   float64 `json:"altitudeMaxKm"`
+	Altitude        string  `json:"altitude"`
+	PeriodRange     string  `json:"periodRange"`
+	Characteristics string  `json:"characteristics"`
+	Uses            string  `json:"uses"`
+	Pros            string  `json:"pros,omitempty"`
+	Cons            string  `json:"cons,omitempty"`
+	Note            string  `json:"note,omitempty"`
+	// Comparable figures, as listed by 'explain orbit --compare'. Latency is the signal delay from
+	// the ground up to the satellite and back down (a bent-pipe relay), at the altitude range.
+	PeriodMinMinutes float64  `json:"periodMinMinutes"`
+	PeriodMaxMinutes float64  `json:"periodMaxMinutes"`
+	LatencyMinMs     float64  `json:"latencyMinMs"`
+	LatencyMaxMs     float64  `json:"latencyMaxMs"`
+	Coverage         string   `json:"coverage"`
+	TypicalUses      []string `json:"typicalUses"`
+}
+
+// Orbits lists the supported orbit types.
+var Orbits = []OrbitInfo{
+	{
+		Name:             "LEO",
+		FullName:         "Low Earth Orbit",
+		AltitudeMinKm:    160,
+		AltitudeMaxKm:    2000,
+		Altitude:         "Typically 160 to 2,000 kilometers (100 to 1,240 miles).",
+		PeriodRange:      "Around 90 minutes to 2 hours.",
+		Characteristics:  "Short orbital periods. Satellites move quickly relative to the Earth's surface.",
+		Uses:             "Earth observation, remote sensing, communications (e.g., Starlink), International Space Station (ISS).",
+		Pros:             "Lower launch costs, lower signal latency.",
+		Cons:             "Limited coverage from a single satellite (requires constellations for continuous coverage), atmospheric drag can be a factor at lower LEO altitudes.",
+		PeriodMinMinutes: 88,
+		PeriodMaxMinutes: 127,
+		LatencyMinMs:     1,
+		LatencyMaxMs:     13,
+		Coverage:         "Small footprint per satellite; global coverage needs a large constellation",
+		TypicalUses:      []string{"Earth observation", "broadband", "crewed stations"},
+	},
+	{
+		Name:             "MEO",
+		FullName:         "Medium Earth Orbit",
+		AltitudeMinKm:    2000,
+		AltitudeMaxKm:    35786,
+		Altitude:         "Between LEO and GEO, typically from 2,000 km up to 35,786 km (just below geostationary).",
+		PeriodRange:      "A few hours (e.g., 12 hours for GPS).",
+		Characteristics:  "Common altitudes are around 20,200 km for navigation satellites.",
+		Uses:             "Navigation systems (e.g., GPS, GLONASS, Galileo), some communications.",
+		Pros:             "Wider coverage than LEO, lower latency than GEO.",
+		Cons:             "Fewer satellites needed than LEO for global coverage, but more than GEO.",
+		PeriodMinMinutes: 127,
+		PeriodMaxMinutes: 1436,
+		LatencyMinMs:     13,
+		LatencyMaxMs:     240,
+		Coverage:         "Regional footprint; global coverage with a few dozen satellites",
+		TypicalUses:      []string{"navigation", "communications"},
+	},
+	{
+		Name:             "GEO",
+		FullName:         "Geostationary Orbit / Geosynchronous Equatorial Orbit",
+		AltitudeMinKm:    35786,
+		AltitudeMaxKm:    35786,
+		Altitude:         "Precisely 35,786 kilometers (22,236 miles) directly above the Earth's Equator.",
+		PeriodRange:      "23 hours, 56 minutes, 4 seconds (matches Earth's rotation).",
+		Characteristics:  "Satellites appear stationary from the ground.",
+		Uses:             "Telecommunications (broadcast TV, fixed communications), weather monitoring (e.g., GOES).",
+		Pros:             "Wide coverage area (one satellite can cover about 1/3 of Earth's surface), fixed ground antennas.",
+		Cons:             "Significant signal latency due to high altitude, higher launch costs, poor coverage for polar regions.",
+		PeriodMinMinutes: 1436,
+		PeriodMaxMinutes: 1436,
+		LatencyMinMs:     240,
+		LatencyMaxMs:     280,
+		Coverage:         "About a third of the Earth from one fixed position; poor above about 70 degrees latitude",
+		TypicalUses:      []string{"broadcast TV", "fixed communications", "weather"},
+	},
+	{
+		Name:             "GSO",
+		FullName:         "Geosynchronous Orbit",
+		AltitudeMinKm:    35786,
+		AltitudeMaxKm:    35786,
+		Altitude:         "Also 35,786 kilometers.",
+		PeriodRange:      "23 hours, 56 minutes, 4 seconds (matches Earth's rotation).",
+		Characteristics:  "Unlike GEO, GSO orbits can be inclined. A satellite in GSO will return to the same position in the sky at the same time each day, but it will appear to trace a path (an analemma) if inclined.",
+		Uses:             "Similar to GEO; some communications and broadcasting.",
+		Note:             "GEO is a special case of GSO where the inclination is zero.",
+		PeriodMinMinutes: 1436,
+		PeriodMaxMinutes: 1436,
+		LatencyMinMs:     240,
+		LatencyMaxMs:     280,
+		Coverage:         "Like GEO, with the footprint drifting north and south each day",
+		TypicalUses:      []string{"communications", "broadcasting"},
+	},
+	{
+		Name:             "HEO",
+		FullName:         "Highly Elliptical Orbit",
+		AltitudeMinKm:    500,
+		AltitudeMaxKm:    40000,
+		Altitude:         "Low perigee (typically a few hundred km) and very high apogee (up to roughly 40,000 km).",
+		PeriodRange:      "Typically 12 hours (Molniya) to 24 hours (Tundra).",
+		Characteristics:  "Orbit with a low perigee (closest point to Earth) and a very high apogee (farthest point). Satellites spend most of their time near apogee, moving slowly over a specific region.",
+		Uses:             "Communications and broadcasting for high-latitude regions (e.g., Molniya orbits for Russia, SiriusXM radio satellites using Tundra orbits), some scientific missions.",
+		Pros:             "Long dwell time over specific areas, good for covering regions not well served by GEO.",
+		Cons:             "Requires steerable ground antennas, varying distance to satellite.",
+		PeriodMinMinutes: 720,
+		PeriodMaxMinutes: 1436,
+		LatencyMinMs:     3,
+		LatencyMaxMs:     270,
+		Coverage:         "Long dwell over high-latitude regions while near apogee",
+		TypicalUses:      []string{"high-latitude communications", "radio broadcasting", "science"},
+	},
+	{
+		Name:             "SSO",
+		FullName:         "Sun-Synchronous Orbit",
+		AltitudeMinKm:    600,
+		AltitudeMaxKm:    800,
+		Altitude:         "Typically LEO altitudes (e.g., 600-800 km), near-polar inclination (around 98 degrees).",
+		PeriodRange:      "Around 96 to 101 minutes.",
+		Characteristics:  "A type of polar orbit where the satellite passes over any given point on Earth's surface at the same local solar time. This means lighting conditions are consistent for imaging.",
+		Uses:             "Earth observation, environmental monitoring, reconnaissance, weather satellites.",
+		Pros:             "Consistent illumination for imaging and change detection.",
+		Cons:             "Similar to LEO in terms of coverage per satellite.",
+		PeriodMinMinutes: 97,
+		PeriodMaxMinutes: 101,
+		LatencyMinMs:     4,
+		LatencyMaxMs:     5,
+		Coverage:         "Near-global over successive passes, each at the same local solar time",
+		TypicalUses:      []string{"imaging", "environmental monitoring", "weather"},
+	},
+	{
+		Name:             "HALO",
+		FullName:         "Halo Orbit",
+		Altitude:         "Not applicable: orbits a Lagrange point (e.g., about 1.5 million km from Earth for Sun-Earth L1/L2) rather than the Earth.",
+		PeriodRange:      "About 6 months around Sun-Earth L1/L2.",
+		Characteristics:  "A periodic, three-dimensional orbit near one of the Lagrange points (L1, L2, or L3) in a two-body system (e.g., Earth-Sun or Earth-Moon). These orbits don't orbit a celestial body directly but rather a point in space where gravitational forces balance.",
+		Uses:             "Space telescopes (e.g., James Webb Space Telescope at Sun-Earth L2, SOHO at Sun-Earth L1), scientific observation, potential communication relays.",
+		Pros:             "Provides a stable vantage point for observing the Earth, Sun, or deep space with minimal obstruction or interference. Can offer continuous view of certain regions.",
+		Cons:             "Inherently unstable for some Lagrange points, requiring station-keeping maneuvers.",
+		PeriodMinMinutes: 262800,
+		PeriodMaxMinutes: 262800,
+		LatencyMinMs:     10000,
+		LatencyMaxMs:     10000,
+		Coverage:         "Continuous view of the Earth's day side (L1) or of deep space (L2)",
+		TypicalUses:      []string{"space telescopes", "solar observation"},
+	},
+}
+
+// LookupOrbit returns the OrbitInfo for name (case-insensitive).
+func LookupOrbit(name string) (OrbitInfo, bool) {
+	for _, o := range Orbits {
+		if strings.EqualFold(o.Name, name) {
+			return o, true
+		}
+	}
+	return OrbitInfo{}, false
+}
+
+// Summary is a one-line description: the full name and the first sentence of the characteristics.
+func (o OrbitInfo) Summary() string {
+	first, _, _ := strings.Cut(o.Characteristics, ". ")
+	return o.FullName + ": " + strings.TrimSuffix(first, ".") + "."
+}
+
+// OrbitNames returns the supported orbit type names, sorted.
+func OrbitNames() []string {
+	names := make([]string, 0, len(Orbits))
+	for _, o := range Orbits {
+		names = append(names, o.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ErrInconsistentOrbit is wrapped by ValidateOrbitConsistency. It is a warning rather than a
+// validation failure: the record may be unusual rather than wrong.
+var ErrInconsistentOrbit = errors.New("altitude is inconsistent with orbit type")
+
+// orbitConsistencyToleranceKm widens an orbit type's altitude range for ValidateOrbitConsistency.
+// GEO satellites are station-kept close to their altitude, GSO ones may drift further, and a HEO's
+// mean altitude depends on its eccentricity. Types not listed get defaultOrbitToleranceKm.
+var orbitConsistencyToleranceKm = map[string]float64{"GEO": 100, "GSO": 500, "HEO": 2000, "SSO": 200}
+
+const defaultOrbitToleranceKm = 50
+
+// ValidateOrbitConsistency checks the altitude against the range of the orbit type in Orbits, as shown
+// by 'satcli explain', widened by a tolerance per type. It returns an error wrapping
+// ErrInconsistentOrbit if they disagree, and nil if they agree or either one is unknown (an altitude of 0,
+// an unsupported orbit type, or one without an Earth altitude range such as HALO).
+func (s Satellite) ValidateOrbitConsistency() error {
+	info, ok := LookupOrbit(strings.TrimSpace(s.OrbitType))
+	if !ok || info.AltitudeMaxKm == 0 || s.Altitude <= 0 {
+		return nil
+	}
+	tolerance, ok := orbitConsistencyToleranceKm[info.Name]
+	if !ok {
+		tolerance = defaultOrbitToleranceKm
+	}
+	minKm, maxKm := info.AltitudeMinKm-tolerance, info.AltitudeMaxKm+tolerance
+	if s.Altitude >= minKm && s.Altitude <= maxKm {
+		return nil
+	}
+	return fmt.Errorf("%w: %.0f km is outside the %.0f-%.0f km expected for %s",
+		ErrInconsistentOrbit, s.Altitude, max(minKm, 0), maxKm, info.Name)
+}
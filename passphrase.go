@@ -0,0 +1,71 @@
+// internal/datastore/passphrase.go
+package datastore
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// DefaultMinPassphraseLength is the shortest passphrase accepted for a new datastore unless changed
+// with SetPassphrasePolicy.
+const DefaultMinPassphraseLength = 12
+
+// minPassphraseEntropyBits is the least estimated entropy (see estimateEntropyBits) accepted for a
+// new datastore; it rejects long but repetitive passphrases such as "aaaaaaaaaaaa" or "abcabcabcabc".
+const minPassphraseEntropyBits = 36
+
+// ErrWeakPassphrase is returned (wrapped) when a passphrase for a new datastore fails the strength policy.
+var ErrWeakPassphrase = errors.New("passphrase is too weak")
+
+var (
+	minPassphraseLength = DefaultMinPassphraseLength
+	allowWeakPassphrase bool
+)
+
+// SetPassphrasePolicy sets the minimum passphrase length for new datastores, or with allowWeak, turns
+// the length and entropy checks off. Unlocking an existing datastore is never checked.
+func SetPassphrasePolicy(minLength int, allowWeak bool) error {
+	if minLength < 1 {
+		return fmt.Errorf("minimum passphrase length must be at least 1, got %d", minLength)
+	}
+	minPassphraseLength, allowWeakPassphrase = minLength, allowWeak
+	return nil
+}
+
+// checkPassphraseStrength applies the strength policy to the passphrase of a new datastore.
+func checkPassphraseStrength(passphrase string) error {
+	if allowWeakPassphrase {
+		return nil
+	}
+	if n := len([]rune(passphrase)); n < minPassphraseLength {
+		return fmt.Errorf("%w: %d characters, at least %d required (use a longer passphrase, or --allow-weak-passphrase)", ErrWeakPassphrase, n, minPassphraseLength)
+	}
+	if bits := estimateEntropyBits(passphrase); bits < minPassphraseEntropyBits {
+		return fmt.Errorf("%w: too repetitive (about %.0f bits, at least %d required; use more varied characters, or --allow-weak-passphrase)", ErrWeakPassphrase, bits, minPassphraseEntropyBits)
+	}
+	return nil
+}
+
+// CheckPassphraseStrength applies the strength policy for new datastores to passphrase, e.g. to check
+// a replacement before ChangePassphrase asks for the key derivation.
+func CheckPassphraseStrength(passphrase string) error {
+	return checkPassphraseStrength(passphrase)
+}
+
+// estimateEntropyBits is a rough strength estimate: the Shannon entropy of the passphrase's character
+// frequencies times its length. It does not know about dictionary words.
+func estimateEntropyBits(passphrase string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range passphrase {
+		counts[r]++
+		total++
+	}
+	perChar := 0.0
+	for _, c := range counts {
+		p := float64(c) / float64(total)
+		perChar -= p * math.Log2(p)
+	}
+	return perChar * float64(total)
+}
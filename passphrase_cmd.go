@@ -0,0 +1,141 @@
+// cmd/satcli/passphrase_cmd.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// newPassphraseEnvVar supplies the replacement passphrase to 'passphrase change' without a prompt.
+const newPassphraseEnvVar = "SATCLI_NEW_PASSPHRASE"
+
+var passphraseCmd = &cobra.Command{
+	Use:         "passphrase",
+	Short:       "Manage the datastore passphrase",
+	Annotations: map[string]string{skipDatastoreAnnotation: "true"},
+}
+
+var passphraseChangeCmd = &cobra.Command{
+	Use:   "change",
+	Short: "Re-encrypt the datastore under a new passphrase",
+	Long: `Unlocks the datastore with its current passphrase and re-encrypts it under a new one, keeping
+its KDF and cipher. The new passphrase must pass the same strength policy as 'satcli init'
+(see --min-passphrase-length and --allow-weak-passphrase).
+
+In an interactive terminal, with neither ` + config.PassphraseEnvVar + ` nor ` + newPassphraseEnvVar + ` set, a form asks for
+the current passphrase and the new one twice, shows progress while the key is derived and the
+store rewritten, and reports the outcome; a wrong current passphrase returns to the form.
+Otherwise the current passphrase comes from ` + config.PassphraseEnvVar + ` (or the usual prompt) and the new
+one from ` + newPassphraseEnvVar + ` (or a prompt, entered twice).
+
+Examples:
+  satcli passphrase change
+  SATCLI_PASSPHRASE=old SATCLI_NEW_PASSPHRASE=new satcli passphrase change`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if !datastore.Exists() {
+			return fmt.Errorf("no datastore at %s; create one with 'satcli init'", mustDatastorePath())
+		}
+		interactive := term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+		if interactive && os.Getenv(config.PassphraseEnvVar) == "" && os.Getenv(newPassphraseEnvVar) == "" {
+			return runPassphraseChangeTUI(cmd)
+		}
+
+		if err := loadDatastore(cmd); err != nil {
+			return err
+		}
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		newPassphrase, err := readNewPassphrase()
+		if err != nil {
+			return err
+		}
+		if err := datastore.ChangePassphrase(cmd.Context(), newPassphrase); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Passphrase changed; %s re-encrypted.\n", mustDatastorePath())
+		if os.Getenv(config.PassphraseEnvVar) != "" {
+			noticef("Notice: %s still holds the old passphrase; update it before the next command.\n", config.PassphraseEnvVar)
+		}
+		return nil
+	},
+}
+
+// readNewPassphrase returns the replacement passphrase from newPassphraseEnvVar or, in a terminal,
+// from a prompt entered twice.
+func readNewPassphrase() (string, error) {
+	var newPassphrase string
+	if v := os.Getenv(newPassphraseEnvVar); v != "" {
+		newPassphrase = v
+	} else {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return "", fmt.Errorf("%s environment variable not set and not running in a terminal to prompt for the new passphrase", newPassphraseEnvVar)
+		}
+		fmt.Fprint(os.Stderr, "Enter new passphrase: ")
+		entered, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read new passphrase: %w", err)
+		}
+		fmt.Fprint(os.Stderr, "Confirm new passphrase: ")
+		confirmed, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read new passphrase confirmation: %w", err)
+		}
+		if string(entered) != string(confirmed) {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+		newPassphrase = string(entered)
+	}
+	if newPassphrase == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	return newPassphrase, nil
+}
+
+// runPassphraseChangeTUI runs the passphrase form. Its Apply step unlocks the store with the entered
+// passphrase (a single attempt, with notices kept off the screen) and re-encrypts it.
+func runPassphraseChangeTUI(cmd *cobra.Command) error {
+	validate := func(current, next string) error {
+		return datastore.CheckPassphraseStrength(next)
+	}
+	apply := func(current, next string) error {
+		datastore.OnPassphraseNeeded = func() (string, error) { return current, nil }
+		datastore.OnNotice = func(string) {}
+		if err := datastore.SetPassphraseAttempts(1); err != nil {
+			return err
+		}
+		if err := datastore.InitCtx(cmd.Context()); err != nil {
+			return err
+		}
+		if !datastore.IsUnlocked() {
+			return errors.New("current passphrase is incorrect")
+		}
+		return datastore.ChangePassphrase(cmd.Context(), next)
+	}
+	final, err := tea.NewProgram(tui.NewPassphraseChangeModel(validate, apply)).Run()
+	if err != nil {
+		return fmt.Errorf("error running TUI: %w", err)
+	}
+	if m, ok := final.(tui.PassphraseChangeModel); !ok || !m.Done {
+		return fmt.Errorf("passphrase change canceled; the datastore was not modified")
+	}
+	return nil
+}
+
+func init() {
+	passphraseCmd.AddCommand(passphraseChangeCmd)
+	rootCmd.AddCommand(passphraseCmd)
+}
@@ -0,0 +1,175 @@
+// tui/passphrase_view.go
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	passphraseTitleStyle   = lipgloss.NewStyle().Bold(true)
+	passphraseLabelStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	passphraseFocusedLabel = lipgloss.NewStyle().Foreground(lipgloss.Color("69")).Bold(true)
+	passphraseSuccessStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true)
+	passphraseHelpStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+var passphraseFieldLabels = [3]string{"Current passphrase", "New passphrase", "Confirm new passphrase"}
+
+type passphraseAppliedMsg struct{ err error }
+
+type spinnerTickMsg struct{}
+
+// PassphraseChangeModel asks for the current, new and confirmation passphrases in masked fields,
+// then runs Apply in the background with a spinner and reports the outcome. An Apply error returns
+// to the form so the user can correct it; the program quits after success or when canceled.
+type PassphraseChangeModel struct {
+	// Validate checks the entries on submit, after the confirmation is matched; e.g. the strength policy.
+	Validate func(current, next string) error
+	// Apply unlocks and re-encrypts the datastore. It runs outside the update loop.
+	Apply func(current, next string) error
+
+	Done     bool // Apply succeeded
+	Canceled bool // The user quit before a successful change
+
+	fields  [3][]rune
+	focus   int
+	message string // Validation or Apply error shown under the form
+	working bool
+	frame   int
+	started time.Time
+	elapsed time.Duration
+}
+
+// NewPassphraseChangeModel creates the form with the given checks and re-encryption step.
+func NewPassphraseChangeModel(validate, apply func(current, next string) error) PassphraseChangeModel {
+	return PassphraseChangeModel{Validate: validate, Apply: apply}
+}
+
+// Init is a required method for tea.Model.
+func (m PassphraseChangeModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update is a required method for tea.Model.
+func (m PassphraseChangeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case spinnerTickMsg:
+		if !m.working {
+			return m, nil
+		}
+		m.frame = (m.frame + 1) % len(spinnerFrames)
+		return m, spinnerTick()
+	case passphraseAppliedMsg:
+		m.working, m.elapsed = false, time.Since(m.started)
+		if msg.err != nil {
+			// Most likely a wrong current passphrase: clear it and start over from there.
+			m.message, m.focus, m.fields[0] = msg.err.Error(), 0, nil
+			return m, nil
+		}
+		m.Done = true
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC || msg.Type == tea.KeyEsc {
+			if !m.working {
+				m.Canceled = true
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if m.working {
+			return m, nil
+		}
+		switch msg.Type {
+		case tea.KeyTab, tea.KeyDown:
+			m.focus = (m.focus + 1) % len(m.fields)
+		case tea.KeyShiftTab, tea.KeyUp:
+			m.focus = (m.focus + len(m.fields) - 1) % len(m.fields)
+		case tea.KeyEnter:
+			if m.focus < len(m.fields)-1 {
+				m.focus++
+				return m, nil
+			}
+			return m.submit()
+		case tea.KeyBackspace:
+			if f := m.fields[m.focus]; len(f) > 0 {
+				m.fields[m.focus] = f[:len(f)-1]
+			}
+		case tea.KeyCtrlU:
+			m.fields[m.focus] = nil
+		case tea.KeySpace:
+			m.fields[m.focus] = append(m.fields[m.focus], ' ')
+		case tea.KeyRunes:
+			m.fields[m.focus] = append(m.fields[m.focus], msg.Runes...)
+		}
+	}
+	return m, nil
+}
+
+// submit validates the form and, if it passes, starts Apply.
+func (m PassphraseChangeModel) submit() (tea.Model, tea.Cmd) {
+	current, next, confirm := string(m.fields[0]), string(m.fields[1]), string(m.fields[2])
+	switch {
+	case current == "":
+		m.message, m.focus = "Enter the current passphrase.", 0
+		return m, nil
+	case next != confirm:
+		m.message, m.focus = "The new passphrases do not match.", 2
+		m.fields[2] = nil
+		return m, nil
+	case next == current:
+		m.message, m.focus = "The new passphrase is the same as the current one.", 1
+		return m, nil
+	}
+	if m.Validate != nil {
+		if err := m.Validate(current, next); err != nil {
+			m.message, m.focus = err.Error(), 1
+			return m, nil
+		}
+	}
+	m.message, m.working, m.started, m.frame = "", true, time.Now(), 0
+	apply := m.Apply
+	return m, tea.Batch(spinnerTick(), func() tea.Msg {
+		return passphraseAppliedMsg{apply(current, next)}
+	})
+}
+
+func spinnerTick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg { return spinnerTickMsg{} })
+}
+
+// View is a required method for tea.Model.
+func (m PassphraseChangeModel) View() string {
+	var b strings.Builder
+	b.WriteString(passphraseTitleStyle.Render("Change datastore passphrase") + "\n\n")
+	if m.Done {
+		b.WriteString(passphraseSuccessStyle.Render("✓ Passphrase changed.") +
+			" The datastore was re-encrypted in " + m.elapsed.Round(time.Millisecond).String() + ".\n" +
+			"Update any saved copy of the old passphrase, such as SATCLI_PASSPHRASE in your environment.\n")
+		return b.String()
+	}
+	for i, label := range passphraseFieldLabels {
+		box, labelStyle := BlurredStyle, passphraseLabelStyle
+		if i == m.focus && !m.working {
+			box, labelStyle = FocusedStyle, passphraseFocusedLabel
+		}
+		b.WriteString(labelStyle.Render(label) + "\n")
+		b.WriteString(box.Width(36).Render(strings.Repeat("•", len(m.fields[i]))) + "\n")
+	}
+	switch {
+	case m.working:
+		b.WriteString("\n" + spinnerFrames[m.frame] + " Unlocking and re-encrypting the datastore… " +
+			time.Since(m.started).Round(100*time.Millisecond).String() + "\n")
+	case m.message != "":
+		b.WriteString("\n" + ErrorStyle.Render(m.message) + "\n")
+	}
+	if !m.working {
+		b.WriteString("\n" + passphraseHelpStyle.Render("tab/enter: next field • enter on the last field: change • ctrl+u: clear • esc: cancel") + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,168 @@
+// cmd/satcli/profile_cmd.go
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage saved query filter profiles",
+	Long: `Profiles are named sets of query filter flags stored in the config file.
+Recall one with 'satcli query --profile <name>'; flags given on the command line override the profile.
+
+Examples:
+  satcli profile save leo-active --orbit-type LEO --status active
+  satcli query --profile leo-active --operator ESA --output table
+  satcli profile list
+  satcli profile delete leo-active`,
+	Annotations: map[string]string{skipDatastoreAnnotation: "true"},
+}
+
+var profileSaveCmd = &cobra.Command{
+	Use:   "save [name]",
+	Short: "Save the given filter flags as a named profile (replacing any existing one)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		name := args[0]
+		values := map[string]string{}
+		for _, flagName := range queryFilterFlags {
+			if flag := cmd.Flags().Lookup(flagName); flag.Changed {
+				values[flagName] = flag.Value.String()
+			}
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("no filter flags given. Specify at least one of: --%s", strings.Join(queryFilterFlags, ", --"))
+		}
+		path, file, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if file.Profiles == nil {
+			file.Profiles = map[string]map[string]string{}
+		}
+		file.Profiles[name] = values
+		if err := config.Save(path, file); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Saved profile '%s' to %s.\n", name, path)
+		return nil
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved profiles and their filter flags",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, file, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if len(file.Profiles) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No profiles saved.")
+			return nil
+		}
+		names := make([]string, 0, len(file.Profiles))
+		for name := range file.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", name, formatProfileFlags(file.Profiles[name]))
+		}
+		return nil
+	},
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a saved profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		name := args[0]
+		path, file, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if _, ok := file.Profiles[name]; !ok {
+			return &exitCodeError{exitNotFound, fmt.Errorf("profile '%s' not found", name)}
+		}
+		delete(file.Profiles, name)
+		if err := config.Save(path, file); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Deleted profile '%s'.\n", name)
+		return nil
+	},
+}
+
+// applyProfile sets each filter flag stored in the named profile, unless it was given on the command line.
+func applyProfile(cmd *cobra.Command, name string) error {
+	_, file, err := loadConfigFile()
+	if err != nil {
+		return err
+	}
+	values, ok := file.Profiles[name]
+	if !ok {
+		return &exitCodeError{exitNotFound, fmt.Errorf("profile '%s' not found. Use 'satcli profile list' to see saved profiles", name)}
+	}
+	for flagName, value := range values {
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil {
+			return fmt.Errorf("profile '%s' sets unknown flag --%s", name, flagName)
+		}
+		if flag.Changed || (containsString(constellationFlags, flagName) && anyFlagChanged(cmd, constellationFlags)) {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid --%s value '%s' in profile '%s': %w", flagName, value, name, err)
+		}
+	}
+	return nil
+}
+
+// anyFlagChanged reports whether any of names was given on the command line.
+func anyFlagChanged(cmd *cobra.Command, names []string) bool {
+	for _, name := range names {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadConfigFile returns the config file location and its current contents.
+func loadConfigFile() (string, config.File, error) {
+	path, err := config.Path()
+	if err != nil {
+		return "", config.File{}, err
+	}
+	file, err := config.Load(path)
+	return path, file, err
+}
+
+// formatProfileFlags renders profile values as command-line flags, in queryFilterFlags order.
+func formatProfileFlags(values map[string]string) string {
+	var parts []string
+	for _, flagName := range queryFilterFlags {
+		if value, ok := values[flagName]; ok {
+			parts = append(parts, fmt.Sprintf("--%s %s", flagName, value))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func init() {
+	addQueryFilterFlags(profileSaveCmd)
+	profileCmd.AddCommand(profileSaveCmd, profileListCmd, profileDeleteCmd)
+	rootCmd.AddCommand(profileCmd)
+}
@@ -0,0 +1,63 @@
+// cmd/satcli/progress.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// progressInterval limits how often the progress line is redrawn.
+const progressInterval = 100 * time.Millisecond
+
+// progress draws a single, self-overwriting status line on out for long-running imports.
+// It is inert unless out is a terminal and --quiet is not set, so piped output stays clean.
+type progress struct {
+	out     io.Writer
+	label   string
+	total   int64 // Units of work (records or bytes) expected; 0 if unknown
+	enabled bool
+	drawn   time.Time
+}
+
+func newProgress(out io.Writer, label string, total int64) *progress {
+	f, isFile := out.(*os.File)
+	return &progress{out: out, label: label, total: total, enabled: !quiet && isFile && term.IsTerminal(int(f.Fd()))}
+}
+
+// Update reports records processed so far and, when the total is known, done units of it.
+// Redraws are throttled to progressInterval.
+func (p *progress) Update(records int, done int64) {
+	if !p.enabled || time.Since(p.drawn) < progressInterval {
+		return
+	}
+	p.drawn = time.Now()
+	if p.total > 0 {
+		fmt.Fprintf(p.out, "\r\033[K%s: %d record(s) (%d%%)", p.label, records, done*100/p.total)
+		return
+	}
+	fmt.Fprintf(p.out, "\r\033[K%s: %d record(s)", p.label, records)
+}
+
+// Clear erases the progress line, before other stderr output or when finished; the next Update redraws it.
+func (p *progress) Clear() {
+	if p.enabled && !p.drawn.IsZero() {
+		fmt.Fprint(p.out, "\r\033[K")
+		p.drawn = time.Time{}
+	}
+}
+
+// countingReader counts the bytes read through it, for byte-based progress.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,292 @@
+// cmd/satcli/protobuf_codec.go
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/yackko/satcom-code/types"
+)
+
+// protoFieldNumbers gives each satellite field, by JSON name, its number in the protobuf message
+// printed by 'satcli schema --format proto'. Numbers are part of the file format: a new field takes
+// the next free number, and a removed field's number is never reused.
+var protoFieldNumbers = map[string]int{
+	"name": 1, "orbitType": 2, "altitude": 3, "eccentricity": 4, "semiMajorAxis": 5, "inclination": 6,
+	"longitude": 7, "powerSystem": 8, "communication": 9, "size": 10, "weight": 11, "constellation": 12,
+	"remoteSensing": 13, "launchDate": 14, "operator": 15, "missionObjective": 16, "status": 17,
+	"archived": 18, "updatedAt": 19, "custom": 20, "tags": 21,
+}
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// protoWireTypes gives the wire type each kind of satellite field is encoded with.
+var protoWireTypes = map[reflect.Kind]int{reflect.String: wireBytes, reflect.Float64: wireFixed64, reflect.Bool: wireVarint, reflect.Map: wireBytes, reflect.Slice: wireBytes}
+
+// protoField is one satellite field as encoded: its struct index, JSON name and field number.
+type protoField struct {
+	index  int
+	name   string
+	number int
+}
+
+// protoFields lists the satellite fields in struct order. Every field must have a number in
+// protoFieldNumbers, so one added to types.Satellite without a number fails at startup.
+var protoFields = func() []protoField {
+	t := reflect.TypeOf(types.Satellite{})
+	fields := make([]protoField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		number, ok := protoFieldNumbers[name]
+		if !ok {
+			panic(fmt.Sprintf("satellite field %s has no protobuf field number", name))
+		}
+		fields = append(fields, protoField{index: i, name: name, number: number})
+	}
+	return fields
+}()
+
+// protoSchema returns the proto3 definition of the files writeProtobuf writes.
+func protoSchema() string {
+	var b strings.Builder
+	b.WriteString(`// Satellite records as written by 'satcli export --format protobuf' and read by 'satcli import'.
+// A file is one serialized SatelliteList. Field names match the JSON keys of 'satcli schema'.
+syntax = "proto3";
+
+package satcli;
+
+message SatelliteList {
+  repeated Satellite satellites = 1;
+}
+
+message Satellite {
+`)
+	t := reflect.TypeOf(types.Satellite{})
+	for _, f := range protoFields {
+		protoType := "string"
+		switch t.Field(f.index).Type.Kind() {
+		case reflect.Float64:
+			protoType = "double"
+		case reflect.Bool:
+			protoType = "bool"
+		case reflect.Map:
+			protoType = "map<string, string>"
+		case reflect.Slice:
+			protoType = "repeated string"
+		}
+		fmt.Fprintf(&b, "  %s %s = %d;\n", protoType, f.name, f.number)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// writeProtobuf writes sats as one serialized SatelliteList (see protoSchema). As in proto3, fields
+// with their zero value are left out; doubles keep all 64 bits, so nothing is rounded.
+func writeProtobuf(w io.Writer, sats []types.Satellite) error {
+	var out []byte
+	for _, sat := range sats {
+		out = appendProtoBytes(out, 1, encodeProtoSatellite(sat))
+	}
+	_, err := w.Write(out)
+	return err
+}
+
+// encodeProtoSatellite encodes one Satellite message.
+func encodeProtoSatellite(sat types.Satellite) []byte {
+	var msg []byte
+	v := reflect.ValueOf(sat)
+	for _, f := range protoFields {
+		field := v.Field(f.index)
+		switch field.Kind() {
+		case reflect.String:
+			if s := field.String(); s != "" {
+				msg = appendProtoBytes(msg, f.number, []byte(s))
+			}
+		case reflect.Float64:
+			if bits := math.Float64bits(field.Float()); bits != 0 {
+				msg = binary.AppendUvarint(msg, uint64(f.number)<<3|wireFixed64)
+				msg = binary.LittleEndian.AppendUint64(msg, bits)
+			}
+		case reflect.Bool:
+			if field.Bool() {
+				msg = binary.AppendUvarint(msg, uint64(f.number)<<3|wireVarint)
+				msg = append(msg, 1)
+			}
+		case reflect.Map:
+			for _, key := range sortedKeys(sat.Custom) {
+				entry := appendProtoBytes(nil, 1, []byte(key))
+				entry = appendProtoBytes(entry, 2, []byte(sat.Custom[key]))
+				msg = appendProtoBytes(msg, f.number, entry)
+			}
+		case reflect.Slice:
+			for _, item := range field.Interface().([]string) {
+				msg = appendProtoBytes(msg, f.number, []byte(item))
+			}
+		}
+	}
+	return msg
+}
+
+// appendProtoBytes appends a length-delimited field.
+func appendProtoBytes(b []byte, number int, data []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(number)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// protoReader walks the fields of one encoded message.
+type protoReader struct {
+	data []byte
+}
+
+var errProtoTruncated = errors.New("truncated protobuf data")
+
+// next returns the next field's number and wire type, and its value: the varint or fixed-width
+// bits, or the bytes of a length-delimited field. It returns io.EOF at the end of the message.
+func (r *protoReader) next() (number int, wireType int, bits uint64, data []byte, err error) {
+	if len(r.data) == 0 {
+		return 0, 0, 0, nil, io.EOF
+	}
+	tag, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		return 0, 0, 0, nil, errProtoTruncated
+	}
+	r.data = r.data[n:]
+	number, wireType = int(tag>>3), int(tag&7)
+	switch wireType {
+	case wireVarint:
+		if bits, n = binary.Uvarint(r.data); n <= 0 {
+			return 0, 0, 0, nil, errProtoTruncated
+		}
+		r.data = r.data[n:]
+	case wireFixed64:
+		if len(r.data) < 8 {
+			return 0, 0, 0, nil, errProtoTruncated
+		}
+		bits, r.data = binary.LittleEndian.Uint64(r.data), r.data[8:]
+	case wireFixed32:
+		if len(r.data) < 4 {
+			return 0, 0, 0, nil, errProtoTruncated
+		}
+		bits, r.data = uint64(binary.LittleEndian.Uint32(r.data)), r.data[4:]
+	case wireBytes:
+		size, n := binary.Uvarint(r.data)
+		if n <= 0 || uint64(len(r.data)-n) < size {
+			return 0, 0, 0, nil, errProtoTruncated
+		}
+		data, r.data = r.data[n:n+int(size)], r.data[n+int(size):]
+	default:
+		return 0, 0, 0, nil, fmt.Errorf("unsupported protobuf wire type %d", wireType)
+	}
+	return number, wireType, bits, data, nil
+}
+
+// protobufToRecords decodes a SatelliteList into one JSON record per satellite, for the import
+// pipeline. Unknown field numbers are skipped, as protobuf readers do.
+func protobufToRecords(data []byte) ([]json.RawMessage, error) {
+	byNumber := make(map[int]protoField, len(protoFields))
+	for _, f := range protoFields {
+		byNumber[f.number] = f
+	}
+	var records []json.RawMessage
+	list := protoReader{data: data}
+	for {
+		number, wireType, _, msg, err := list.next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("not a protobuf SatelliteList: %w", err)
+		}
+		if number != 1 || wireType != wireBytes {
+			continue
+		}
+		sat, err := decodeProtoSatellite(msg, byNumber)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", len(records), err)
+		}
+		raw, err := json.Marshal(sat)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", len(records), err)
+		}
+		records = append(records, raw)
+	}
+}
+
+// decodeProtoSatellite decodes one Satellite message.
+func decodeProtoSatellite(msg []byte, byNumber map[int]protoField) (types.Satellite, error) {
+	var sat types.Satellite
+	v := reflect.ValueOf(&sat).Elem()
+	r := protoReader{data: msg}
+	for {
+		number, wireType, bits, data, err := r.next()
+		if err == io.EOF {
+			return sat, nil
+		}
+		if err != nil {
+			return sat, err
+		}
+		f, known := byNumber[number]
+		if !known {
+			continue
+		}
+		field := v.Field(f.index)
+		if want := protoWireTypes[field.Kind()]; wireType != want {
+			return sat, fmt.Errorf("field %s has wire type %d, want %d", f.name, wireType, want)
+		}
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(string(data))
+		case reflect.Float64:
+			field.SetFloat(math.Float64frombits(bits))
+		case reflect.Bool:
+			field.SetBool(bits != 0)
+		case reflect.Map:
+			key, value, err := decodeProtoMapEntry(data)
+			if err != nil {
+				return sat, fmt.Errorf("field %s: %w", f.name, err)
+			}
+			if sat.Custom == nil {
+				sat.Custom = make(map[string]string)
+			}
+			sat.Custom[key] = value
+		case reflect.Slice:
+			field.Set(reflect.Append(field, reflect.ValueOf(string(data))))
+		}
+	}
+}
+
+// decodeProtoMapEntry decodes a map<string, string> entry: key is field 1, value field 2.
+func decodeProtoMapEntry(msg []byte) (key, value string, err error) {
+	r := protoReader{data: msg}
+	for {
+		number, wireType, _, data, err := r.next()
+		if err == io.EOF {
+			return key, value, nil
+		}
+		if err != nil {
+			return "", "", err
+		}
+		if wireType != wireBytes {
+			continue
+		}
+		switch number {
+		case 1:
+			key = string(data)
+		case 2:
+			value = string(data)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// cmd/satcli/prune_decayed_cmd.go
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// decayedStatus is the status prune-decayed gives satellites it does not delete.
+const decayedStatus = "decayed"
+
+var pruneDecayedCmd = &cobra.Command{
+	Use:   "prune-decayed",
+	Short: "Mark or delete low-orbit satellites that have probably reentered",
+	Long: `Finds LEO and SSO satellites that have almost certainly reentered and sets their status to
+"decayed", or deletes them with --delete. A satellite is selected if its altitude is below --below
+km, or, with --decay-estimate, if more time has passed since its orbital elements were last known
+(updatedAt from 'satcli refresh-tle', else the launch date) than a rough drag lifetime for its
+perigee. The estimate assumes a typical small satellite and moderate solar activity, so treat it
+as a hint. --status limits the selection to one status; records already "decayed" or without an
+altitude are skipped.
+
+The changes are always previewed first. --dry-run stops after the preview; otherwise you are asked
+to confirm unless --yes is given. The datastore is saved once.
+
+Examples:
+  satcli prune-decayed --below 200 --status active --dry-run
+  satcli prune-decayed --decay-estimate --yes
+  satcli prune-decayed --below 150 --delete`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		below, _ := cmd.Flags().GetFloat64("below")
+		useEstimate, _ := cmd.Flags().GetBool("decay-estimate")
+		status, _ := cmd.Flags().GetString("status")
+		remove, _ := cmd.Flags().GetBool("delete")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		cmd.SilenceUsage = true
+		if below < 0 {
+			return fmt.Errorf("--below cannot be negative")
+		}
+		if below == 0 && !useEstimate {
+			return fmt.Errorf("give --below, --decay-estimate, or both")
+		}
+
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		now := time.Now()
+		var selected []types.Satellite
+		for _, name := range sortedKeys(satsMap) {
+			sat := satsMap[name]
+			orbit := strings.ToUpper(strings.TrimSpace(sat.OrbitType))
+			if (orbit != "LEO" && orbit != "SSO") || sat.Altitude <= 0 || strings.EqualFold(sat.Status, decayedStatus) {
+				continue
+			}
+			if status != "" && !strings.EqualFold(sat.Status, status) {
+				continue
+			}
+			var reasons []string
+			if below > 0 && sat.Altitude < below {
+				reasons = append(reasons, fmt.Sprintf("altitude %.0f km below %g km", sat.Altitude, below))
+			}
+			if useEstimate {
+				if decayed, reentry, ok := sat.LikelyDecayed(now); ok && decayed {
+					reasons = append(reasons, "estimated reentry by "+reentry.Format(config.DateFormat))
+				}
+			}
+			if len(reasons) == 0 {
+				continue
+			}
+			action := fmt.Sprintf("status %q -> %q", sat.Status, decayedStatus)
+			if remove {
+				action = "delete"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s (%s)\n", name, action, strings.Join(reasons, "; "))
+			selected = append(selected, sat)
+		}
+		if len(selected) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No satellites look decayed.")
+			return nil
+		}
+		question, outcome := fmt.Sprintf("Mark %d satellite(s) as decayed?", len(selected)), "marked decayed"
+		if remove {
+			question, outcome = fmt.Sprintf("Delete %d decayed satellite(s)?", len(selected)), "deleted"
+		}
+		if dryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "Dry run: %d satellite(s) would be %s.\n", len(selected), outcome)
+			return nil
+		}
+		if !yes {
+			confirmed, err := confirm(question)
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Fprintln(cmd.OutOrStdout(), "Aborted; nothing was changed.")
+				return nil
+			}
+		}
+
+		for _, sat := range selected {
+			if remove {
+				err = datastore.DeleteSatellite(sat.Name)
+			} else {
+				sat.Status = decayedStatus
+				err = datastore.AddSatellite(sat)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to save after pruning %d record(s): %w", len(selected), err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Pruned %d satellite(s): %s.\n", len(selected), outcome)
+		return nil
+	},
+}
+
+func init() {
+	pruneDecayedCmd.Flags().Float64("below", 0, "Select satellites with an altitude below this many km (0 means no threshold)")
+	pruneDecayedCmd.Flags().Bool("decay-estimate", false, "Select satellites whose estimated drag lifetime has passed since their elements were last known")
+	pruneDecayedCmd.Flags().StringP("status", "s", "", "Only satellites with this status (case-insensitive)")
+	pruneDecayedCmd.Flags().Bool("delete", false, "Delete the selected satellites instead of setting their status to decayed")
+	pruneDecayedCmd.Flags().Bool("yes", false, "Apply the changes without asking for confirmation")
+	rootCmd.AddCommand(pruneDecayedCmd)
+}
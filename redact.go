@@ -0,0 +1,82 @@
+// cmd/satcli/redact.go
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// redactedValue replaces the value of each field named by --redact.
+const redactedValue = "REDACTED"
+
+// addRedactFlag registers --redact on commands that print or export satellite records.
+func addRedactFlag(cmd *cobra.Command) {
+	cmd.Flags().String("redact", "", "Comma-separated text fields to show as "+redactedValue+", e.g. operator,missionObjective (custom.<key> or custom for attributes)")
+}
+
+// redactFromFlags returns sats with the fields named by --redact replaced by redactedValue, leaving
+// sats itself untouched. Only text fields can be redacted, so every output keeps its types and
+// columns, and empty values stay empty. custom.<key> redacts one custom attribute and custom all of them.
+func redactFromFlags(cmd *cobra.Command, sats []types.Satellite) ([]types.Satellite, error) {
+	list, _ := cmd.Flags().GetString("redact")
+	if strings.TrimSpace(list) == "" {
+		return sats, nil
+	}
+	fields := satelliteJSONFields()
+	var redacted []string
+	var customKeys []string
+	allCustom := false
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if key, isCustom := strings.CutPrefix(field, "custom."); isCustom && key != "" {
+			customKeys = append(customKeys, key)
+			continue
+		}
+		if strings.EqualFold(field, "custom") {
+			allCustom = true
+			continue
+		}
+		jsonName := ""
+		for name := range fields {
+			if strings.EqualFold(name, field) {
+				jsonName = name
+			}
+		}
+		if jsonName == "" || fields[jsonName] != reflect.String {
+			var valid []string
+			for name, kind := range fields {
+				if kind == reflect.String {
+					valid = append(valid, name)
+				}
+			}
+			sort.Strings(valid)
+			return nil, fmt.Errorf("cannot redact '%s'. Redactable fields: %s, custom.<key>, custom", field, strings.Join(valid, ", "))
+		}
+		redacted = append(redacted, jsonName)
+	}
+
+	out := make([]types.Satellite, len(sats))
+	for i, sat := range sats {
+		sat = sat.Clone()
+		v := reflect.ValueOf(&sat).Elem()
+		for j := 0; j < v.NumField(); j++ {
+			name := strings.Split(v.Type().Field(j).Tag.Get("json"), ",")[0]
+			if containsString(redacted, name) && v.Field(j).String() != "" {
+				v.Field(j).SetString(redactedValue)
+			}
+		}
+		for key := range sat.Custom {
+			if allCustom || containsString(customKeys, key) {
+				sat.Custom[key] = redactedValue
+			}
+		}
+		out[i] = sat
+	}
+	return out, nil
+}
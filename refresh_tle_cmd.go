@@ -0,0 +1,92 @@
+// cmd/satcli/refresh_tle_cmd.go
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+var refreshTLECmd = &cobra.Command{
+	Use:   "refresh-tle",
+	Short: "Update the orbital elements of stored satellites from newer TLEs",
+	Long: `Reads TLE text from --file or, with --file - or --stdin, from standard input, as import-tle does, and for each
+element set whose name matches a stored satellite updates only its altitude, semi-major axis,
+inclination and eccentricity, and sets updatedAt to the current time. Operator, status, mission,
+orbit type and custom attributes are left untouched. TLEs that match no stored satellite are
+listed and skipped rather than added; use 'satcli import-tle' to add them. The datastore is saved
+once at the end.
+
+Examples:
+  satcli refresh-tle --file latest.txt
+  curl -s https://celestrak.org/NORAD/elements/gp.php?GROUP=stations | satcli refresh-tle --stdin`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		cmd.SilenceUsage = true
+		in, source, size, closeInput, err := openTLEInput(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeInput()
+		p := newProgress(cmd.ErrOrStderr(), "Refreshing TLEs", size)
+
+		existing, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		now := time.Now()
+		updates := make(map[string]types.Satellite)
+		var order, unmatched []string
+		skipped, err := scanTLEBlocks(in, func(t types.TLE) {
+			sat, ok := updates[t.Name]
+			if !ok {
+				if sat, ok = existing[t.Name]; !ok {
+					if !containsString(unmatched, t.Name) {
+						unmatched = append(unmatched, t.Name)
+					}
+					return
+				}
+				order = append(order, t.Name)
+			}
+			updates[t.Name] = t.Refresh(sat, now)
+			p.Update(len(updates), in.n)
+		}, func(at tleLine, reason error) {
+			p.Clear()
+			fmt.Fprintf(cmd.ErrOrStderr(), "skipped block at line %d (byte %d): %v\n", at.Line, at.Offset, reason)
+		})
+		p.Clear()
+		if err != nil {
+			return fmt.Errorf("failed to read TLEs from %s: %w", source, err)
+		}
+		for _, name := range unmatched {
+			fmt.Fprintf(cmd.ErrOrStderr(), "unmatched: %s (no stored satellite with this name)\n", name)
+		}
+		if len(updates) == 0 {
+			return &exitCodeError{exitNotFound, fmt.Errorf("no TLE in %s matches a stored satellite (%d unmatched, %d block(s) skipped)", source, len(unmatched), skipped)}
+		}
+
+		for _, name := range order {
+			if err := datastore.AddSatellite(updates[name]); err != nil {
+				return err
+			}
+		}
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to save %d refreshed record(s): %w", len(updates), err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Refreshed %d record(s): %d unmatched TLE(s) and %d malformed block(s) skipped.\n", len(updates), len(unmatched), skipped)
+		return nil
+	},
+}
+
+func init() {
+	refreshTLECmd.Flags().String("file", "", "Path of a TLE text file, or - for standard input")
+	refreshTLECmd.Flags().Bool("stdin", false, "Read TLE text from stdin instead of --file")
+	rootCmd.AddCommand(refreshTLECmd)
+}
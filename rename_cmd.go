@@ -0,0 +1,78 @@
+// cmd/satcli/rename_cmd.go
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename [old] [new]",
+	Short: "Rename a satellite record in the secure datastore",
+	Long: `Renames a satellite, keeping all of its other fields.
+Fails if [old] does not exist or [new] is already taken, unless --force is given to overwrite [new].
+[old] may differ from the stored name in case, and [new] counts as taken by any other record
+whose name differs from it only in case; renaming to a different case of the same name is allowed.
+
+Examples:
+  satcli rename ISS "ISS (ZARYA)"
+  satcli rename Starlink-1007 Starlink-1008 --force`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		oldName, newName := args[0], args[1]
+		cmd.SilenceUsage = true
+		if newName == "" {
+			return fmt.Errorf("new satellite name cannot be empty")
+		}
+		if oldName == newName {
+			return fmt.Errorf("old and new names are identical: '%s'", oldName)
+		}
+		sats, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		sat, exists, err := lookupSatellite(sats, oldName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &exitCodeError{exitNotFound, fmt.Errorf("satellite '%s' not found", oldName)}
+		}
+		oldName = sat.Name
+		if oldName == newName {
+			return fmt.Errorf("old and new names are identical: '%s'", oldName)
+		}
+		for _, taken := range sortedKeys(sats) {
+			if taken == oldName || !strings.EqualFold(taken, newName) {
+				continue
+			}
+			force, _ := cmd.Flags().GetBool("force")
+			if !force {
+				return fmt.Errorf("a satellite named '%s' already exists. Use --force to overwrite it", taken)
+			}
+			if err := datastore.DeleteSatellite(taken); err != nil {
+				return err
+			}
+		}
+		if err := datastore.RenameSatellite(oldName, newName); err != nil {
+			return err
+		}
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to save rename of '%s': %w", oldName, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Record renamed: %s -> %s\n", oldName, newName)
+		return nil
+	},
+}
+
+func init() {
+	renameCmd.Flags().Bool("force", false, "Overwrite an existing satellite with the new name")
+	rootCmd.AddCommand(renameCmd)
+}
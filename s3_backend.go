@@ -0,0 +1,195 @@
+// internal/datastore/s3_backend.go
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	s3Scheme = "s3://"
+	// s3Attempts is how many times the SDK sends a request failing with a network error, 5xx or throttling.
+	s3Attempts = 3
+	// s3ConsistencyAttempts is how many times a read is tried while it still misses this process's
+	// own last write, for S3-compatible services that are only eventually consistent.
+	s3ConsistencyAttempts = 5
+	s3RetryDelay          = 200 * time.Millisecond
+	s3DefaultRegion       = "us-east-1"
+)
+
+// s3Backend keeps the datastore as one object in S3 or an S3-compatible service through the AWS
+// SDK, so credentials, region and endpoint are resolved as by the AWS CLI: environment variables,
+// the shared config and credentials files (profiles, SSO, credential_process), web identity, and
+// ECS or EC2 instance roles. The region defaults to us-east-1, and a custom endpoint
+// (AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL) is addressed path-style.
+// Versions are object ETags, and Write is a conditional PUT (If-Match, or If-None-Match for a new
+// object), so a concurrent save by another client is detected by the service itself.
+type s3Backend struct {
+	location    string
+	bucket, key string
+	client      *s3.Client
+	written     string // ETag of this process's last write; reads returning an older object are retried
+}
+
+func newS3Backend(location string) (*s3Backend, error) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(location, s3Scheme), "/")
+	if bucket == "" || key == "" || strings.HasSuffix(key, "/") {
+		return nil, fmt.Errorf("invalid S3 datastore location %q: want s3://bucket/key", location)
+	}
+	// Loading reads only the environment and config files; credentials are fetched on first use.
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRetryMaxAttempts(s3Attempts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration for %s: %w", location, err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = s3DefaultRegion
+	}
+	endpoint := firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL")
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+		// Dotted bucket names do not match the wildcard certificate of virtual-hosted addresses.
+		o.UsePathStyle = endpoint != "" || strings.Contains(bucket, ".")
+		// Checksums only where S3 requires them, as S3-compatible services may reject the others.
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+	})
+	return &s3Backend{location: location, bucket: bucket, key: key, client: client}, nil
+}
+
+func (b *s3Backend) Location() string {
+	return b.location
+}
+
+func (b *s3Backend) Read(ctx context.Context) ([]byte, string, error) {
+	var data []byte
+	var version string
+	err := b.consistently(ctx, func() (string, error) {
+		out, err := b.client.GetObject(ctx, &s3.GetObjectInput{Bucket: &b.bucket, Key: &b.key})
+		if err != nil {
+			return "", b.requestError(http.MethodGet, err)
+		}
+		defer out.Body.Close()
+		if data, err = io.ReadAll(out.Body); err != nil {
+			return "", fmt.Errorf("S3 GET %s failed: %w", b.location, err)
+		}
+		version = aws.ToString(out.ETag)
+		return version, nil
+	})
+	return data, version, err
+}
+
+func (b *s3Backend) Version(ctx context.Context) (string, error) {
+	var version string
+	err := b.consistently(ctx, func() (string, error) {
+		out, err := b.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &b.bucket, Key: &b.key})
+		if err != nil {
+			return "", b.requestError(http.MethodHead, err)
+		}
+		version = aws.ToString(out.ETag)
+		return version, nil
+	})
+	if errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	return version, err
+}
+
+// consistently runs op, a GET or HEAD returning the object's ETag, again while it misses (not found,
+// or another ETag) this process's last unobserved write, and returns the last result once attempts run out.
+func (b *s3Backend) consistently(ctx context.Context, op func() (string, error)) error {
+	delay := s3RetryDelay
+	for attempt := 1; ; attempt++ {
+		version, err := op()
+		stale := b.written != "" && (errors.Is(err, ErrNotFound) || (err == nil && version != b.written))
+		if !stale || attempt == s3ConsistencyAttempts {
+			if err == nil {
+				b.written = "" // Seen a current object; later reads need not wait for the write
+			}
+			return err
+		}
+		logger.Debug("s3 read is behind last write, retrying", "location", b.location, "attempt", attempt)
+		if err := sleepCtx(ctx, delay); err != nil {
+			return err
+		}
+		delay *= 2
+	}
+}
+
+func (b *s3Backend) CheckWritable(ctx context.Context) error {
+	if _, err := b.client.Options().Credentials.Retrieve(ctx); err != nil {
+		return fmt.Errorf("no AWS credentials for %s: %w", b.location, err)
+	}
+	return nil
+}
+
+func (b *s3Backend) Write(ctx context.Context, data []byte, ifVersion string, force bool) (string, error) {
+	in := &s3.PutObjectInput{
+		Bucket:      &b.bucket,
+		Key:         &b.key,
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/octet-stream"),
+	}
+	switch {
+	case force:
+	case ifVersion == "":
+		in.IfNoneMatch = aws.String("*")
+	default:
+		in.IfMatch = aws.String(ifVersion)
+	}
+	out, err := b.client.PutObject(ctx, in)
+	if err != nil {
+		return "", b.requestError(http.MethodPut, err)
+	}
+	b.written = aws.ToString(out.ETag)
+	return b.written, nil
+}
+
+// requestError reports 404 as ErrNotFound, and 412 (precondition failed) and 409 (a concurrent
+// conditional write) as ErrModifiedExternally; other failures keep the SDK's error.
+func (b *s3Backend) requestError(method string, err error) error {
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		switch respErr.HTTPStatusCode() {
+		case http.StatusNotFound:
+			return fmt.Errorf("%w: %s", ErrNotFound, b.location)
+		case http.StatusPreconditionFailed, http.StatusConflict:
+			return modifiedExternally(b.location)
+		}
+	}
+	return fmt.Errorf("S3 %s %s failed: %w", method, b.location, err)
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// sleepCtx waits for d, or returns ctx.Err() if ctx is done first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
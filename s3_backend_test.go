@@ -0,0 +1,169 @@
+// internal/datastore/s3_backend_test.go
+package datastore
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 is a minimal path-style S3 serving one bucket, with conditional PUTs.
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	failures int // Requests still to answer with 500
+	unsigned int // Requests without a SigV4 Authorization header for the test credentials
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDTEST/") {
+		f.unsigned++
+	}
+	if f.failures > 0 {
+		f.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, `<Error><Code>InternalError</Code><Message>try again</Message></Error>`)
+		return
+	}
+	data, exists := f.objects[r.URL.Path]
+	etag := ""
+	if exists {
+		sum := md5.Sum(data)
+		etag = `"` + hex.EncodeToString(sum[:]) + `"`
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			}
+			return
+		}
+		w.Header().Set("ETag", etag)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodPut:
+		if (r.Header.Get("If-None-Match") == "*" && exists) || (r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			io.WriteString(w, `<Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>`)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+		sum := md5.Sum(body)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// newTestS3Backend returns a backend for s3://team-sats/fleet.store served by fake, with
+// static credentials and no shared config files, so the developer's AWS setup is not used.
+func newTestS3Backend(t *testing.T, fake *fakeS3) *s3Backend {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+	for name, value := range map[string]string{
+		"AWS_ACCESS_KEY_ID":           "AKIDTEST",
+		"AWS_SECRET_ACCESS_KEY":       "secret",
+		"AWS_SESSION_TOKEN":           "",
+		"AWS_PROFILE":                 "",
+		"AWS_REGION":                  "eu-west-1",
+		"AWS_ENDPOINT_URL_S3":         server.URL,
+		"AWS_CONFIG_FILE":             filepath.Join(dir, "config"),
+		"AWS_SHARED_CREDENTIALS_FILE": filepath.Join(dir, "credentials"),
+		"AWS_EC2_METADATA_DISABLED":   "true",
+	} {
+		t.Setenv(name, value)
+	}
+	b, err := newS3Backend("s3://team-sats/fleet.store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestS3BackendConditionalWrites(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}}
+	b := newTestS3Backend(t, fake)
+	ctx := context.Background()
+
+	if _, _, err := b.Read(ctx); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Read of a missing object: err = %v, want ErrNotFound", err)
+	}
+	if version, err := b.Version(ctx); err != nil || version != "" {
+		t.Fatalf("Version of a missing object = %q, %v; want \"\", nil", version, err)
+	}
+	v1, err := b.Write(ctx, []byte("first"), "", false)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := b.Write(ctx, []byte("again"), "", false); !errors.Is(err, ErrModifiedExternally) {
+		t.Fatalf("second create: err = %v, want ErrModifiedExternally", err)
+	}
+	data, version, err := b.Read(ctx)
+	if err != nil || string(data) != "first" || version != v1 {
+		t.Fatalf("Read = %q, %q, %v; want \"first\", %q", data, version, err, v1)
+	}
+	v2, err := b.Write(ctx, []byte("second"), v1, false)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := b.Write(ctx, []byte("stale"), v1, false); !errors.Is(err, ErrModifiedExternally) {
+		t.Fatalf("update from a stale version: err = %v, want ErrModifiedExternally", err)
+	}
+	if _, err := b.Write(ctx, []byte("forced"), v1, true); err != nil {
+		t.Fatalf("forced update: %v", err)
+	}
+	if version, err := b.Version(ctx); err != nil || version == v2 {
+		t.Fatalf("Version after forced update = %q, %v; want a new ETag", version, err)
+	}
+	if fake.objects["/team-sats/fleet.store"] == nil {
+		t.Error("object not stored path-style at /team-sats/fleet.store")
+	}
+	if fake.unsigned != 0 {
+		t.Errorf("%d requests not signed with the configured credentials", fake.unsigned)
+	}
+}
+
+func TestS3BackendRetriesServerErrors(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{"/team-sats/fleet.store": []byte("data")}, failures: s3Attempts - 1}
+	b := newTestS3Backend(t, fake)
+	if data, _, err := b.Read(context.Background()); err != nil || string(data) != "data" {
+		t.Fatalf("Read after %d server errors = %q, %v", s3Attempts-1, data, err)
+	}
+
+	fake.failures = s3Attempts
+	if _, _, err := b.Read(context.Background()); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("Read with every attempt failing: err = %v, want a server error", err)
+	}
+}
+
+func TestS3BackendCheckWritableNeedsCredentials(t *testing.T) {
+	b := newTestS3Backend(t, &fakeS3{objects: map[string][]byte{}})
+	if err := b.CheckWritable(context.Background()); err != nil {
+		t.Fatalf("CheckWritable with credentials: %v", err)
+	}
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	b, err := newS3Backend("s3://team-sats/fleet.store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.CheckWritable(context.Background()); err == nil {
+		t.Error("CheckWritable succeeded without credentials")
+	}
+}
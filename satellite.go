@@ -1,21 +1,161 @@
 // types/satellite.go
 package types
 
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
 // Satellite represents information about an Earth satellite.
+// Fields tagged schema:"required" must be present and non-empty in SatelliteSchema.
 type Satellite struct {
-	Name             string  `json:"name"`
-	OrbitType        string  `json:"orbitType"`
-	Altitude         float64 `json:"altitude"`
-	Eccentricity     float64 `json:"eccentricity"`
-	Inclination      float64 `json:"inclination"`
-	PowerSystem      string  `json:"powerSystem"`
-	Communication    string  `json:"communication"`
-	Size             float64 `json:"size"`
-	Weight           float64 `json:"weight"`
-	Constellation    bool    `json:"constellation"`
-	RemoteSensing    string  `json:"remoteSensing"`
-	LaunchDate       string  `json:"launchDate"` // Format: YYYY-MM-DD
-	Operator         string  `json:"operator"`
-	MissionObjective string  `json:"missionObjective"`
-	Status           string  `json:"status"` // e.g., Active, Inactive
+	Name             string            `json:"name" schema:"required"`
+	OrbitType        string            `json:"orbitType" schema:"required"`
+	Altitude         float64           `json:"altitude"`
+	Eccentricity     float64           `json:"eccentricity"`
+	SemiMajorAxisKm  float64           `json:"semiMajorAxis,omitempty"` // From orbital datasets; Altitude is derived from it when missing
+	Inclination      float64           `json:"inclination"`
+	Longitude        float64           `json:"longitude,omitempty"` // Degrees east (-180 to 180) of a GEO/GSO slot
+	PowerSystem      string            `json:"powerSystem"`
+	Communication    string            `json:"communication"`
+	Size             float64           `json:"size"`
+	Weight           float64           `json:"weight"`
+	Constellation    bool              `json:"constellation"`
+	RemoteSensing    string            `json:"remoteSensing"`
+	LaunchDate       string            `json:"launchDate"` // Format: YYYY-MM-DD
+	Operator         string            `json:"operator" schema:"required"`
+	MissionObjective string            `json:"missionObjective"`
+	Status           string            `json:"status" schema:"required"` // e.g., Active, Inactive
+	Archived         bool              `json:"archived,omitempty"`       // Retired; hidden from list and query unless asked for
+	UpdatedAt        string            `json:"updatedAt,omitempty"`      // RFC3339 time the orbital elements were last refreshed from a TLE
+	Tags             []string          `json:"tags,omitempty"`           // Ad-hoc labels such as "watchlist"; see 'satcli tag'
+	Custom           map[string]string `json:"custom,omitempty"`         // Organization-specific attributes; see --set
+}
+
+// Clone returns a copy of s that shares no maps with it.
+func (s Satellite) Clone() Satellite {
+	if s.Custom != nil {
+		custom := make(map[string]string, len(s.Custom))
+		for k, v := range s.Custom {
+			custom[k] = v
+		}
+		s.Custom = custom
+	}
+	if s.Tags != nil {
+		s.Tags = append([]string(nil), s.Tags...)
+	}
+	return s
+}
+
+// HasTag reports whether s has tag, compared case-insensitively.
+func (s Satellite) HasTag(tag string) bool {
+	for _, t := range s.Tags {
+		if strings.EqualFold(t, strings.TrimSpace(tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags returns s with each of tags it lacks appended, trimmed, and the tags that were added.
+func (s Satellite) AddTags(tags ...string) (Satellite, []string) {
+	s = s.Clone()
+	var added []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" && !s.HasTag(tag) {
+			s.Tags = append(s.Tags, tag)
+			added = append(added, tag)
+		}
+	}
+	return s, added
+}
+
+// RemoveTags returns s without tags (compared case-insensitively), and the stored tags removed.
+func (s Satellite) RemoveTags(tags ...string) (Satellite, []string) {
+	s = s.Clone()
+	var kept, removed []string
+	for _, t := range s.Tags {
+		drop := false
+		for _, tag := range tags {
+			drop = drop || strings.EqualFold(t, strings.TrimSpace(tag))
+		}
+		if drop {
+			removed = append(removed, t)
+		} else {
+			kept = append(kept, t)
+		}
+	}
+	s.Tags = kept
+	return s, removed
+}
+
+// daysPerYear is the mean Julian year used for ages.
+const daysPerYear = 365.25
+
+// YearsInOrbit returns the time from LaunchDate (YYYY-MM-DD) to now in years; it is negative for a
+// launch date in the future.
+func (s Satellite) YearsInOrbit() (float64, error) {
+	launched, err := time.Parse("2006-01-02", s.LaunchDate)
+	if err != nil {
+		return 0, fmt.Errorf("launch date '%s' of '%s' is not YYYY-MM-DD", s.LaunchDate, s.Name)
+	}
+	return time.Since(launched).Hours() / 24 / daysPerYear, nil
+}
+
+// SemiMajorAxisToleranceKm is how far a recorded Altitude may be from the one SemiMajorAxisKm implies
+// before the two are reported as inconsistent.
+const SemiMajorAxisToleranceKm = 5.0
+
+// SemiMajorAxis returns the semi-major axis in km: SemiMajorAxisKm when recorded, otherwise Altitude
+// (the mean altitude) plus EarthRadiusKm.
+func (s Satellite) SemiMajorAxis() float64 {
+	if s.SemiMajorAxisKm > 0 {
+		return s.SemiMajorAxisKm
+	}
+	return s.Altitude + EarthRadiusKm
+}
+
+// AltitudeFromSemiMajorAxis returns s with a zero Altitude set to SemiMajorAxisKm less EarthRadiusKm,
+// and how far a recorded Altitude is from that value (0 unless both are set). A recorded Altitude is
+// kept, as it is authoritative.
+func (s Satellite) AltitudeFromSemiMajorAxis() (Satellite, float64) {
+	if s.SemiMajorAxisKm <= 0 {
+		return s, 0
+	}
+	derived := s.SemiMajorAxisKm - EarthRadiusKm
+	if s.Altitude == 0 {
+		s.Altitude = derived
+		return s, 0
+	}
+	return s, s.Altitude - derived
+}
+
+// IsGeosynchronous reports whether the orbit type is GEO or GSO, for which Longitude is meaningful.
+func (s Satellite) IsGeosynchronous() bool {
+	orbit := strings.TrimSpace(s.OrbitType)
+	return strings.EqualFold(orbit, "GEO") || strings.EqualFold(orbit, "GSO")
+}
+
+// AnalemmaAmplitude estimates the daily figure-eight an inclined geosynchronous satellite traces about
+// its slot, as the half-amplitudes in degrees of latitude (the inclination) and longitude, for a
+// circular orbit. ok is false unless the satellite is geosynchronous with a nonzero inclination.
+func (s Satellite) AnalemmaAmplitude() (latitudeDeg, longitudeDeg float64, ok bool) {
+	if !s.IsGeosynchronous() || s.Inclination <= 0 || s.Inclination >= 90 {
+		return 0, 0, false
+	}
+	// The longitude offset atan(cos i * tan u) - u peaks where tan u = 1/sqrt(cos i).
+	c := math.Cos(s.Inclination * math.Pi / 180)
+	return s.Inclination, math.Atan((1-c)/(2*math.Sqrt(c))) * 180 / math.Pi, true
+}
+
+// ApogeePerigeeKm returns the highest and lowest altitudes of the orbit, treating Altitude as the
+// mean altitude (semi-major axis less EarthRadiusKm). Eccentricity must be in [0, 1).
+func (s Satellite) ApogeePerigeeKm() (apo, peri float64, err error) {
+	if s.Eccentricity < 0 || s.Eccentricity >= 1 {
+		return 0, 0, fmt.Errorf("eccentricity %v of '%s' is outside [0, 1)", s.Eccentricity, s.Name)
+	}
+	a := s.Altitude + EarthRadiusKm
+	return a*(1+s.Eccentricity) - EarthRadiusKm, a*(1-s.Eccentricity) - EarthRadiusKm, nil
 }
@@ -0,0 +1,42 @@
+// types/satellite_test.go
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+func TestApogeePerigeeKm(t *testing.T) {
+	tests := []struct {
+		name           string
+		altitude, ecc  float64
+		wantApo, wantP float64
+		wantErr        bool
+	}{
+		{"molniya", 26600, 0.74, 51004, 2196, false},
+		{"circular", 550, 0, 550, 550, false},
+		{"geo", 35786, 0.0002, 35794, 35778, false},
+		{"negative eccentricity", 26600, -0.1, 0, 0, true},
+		{"parabolic", 26600, 1, 0, 0, true},
+		{"hyperbolic", 26600, 1.5, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sat := Satellite{Name: tt.name, Altitude: tt.altitude, Eccentricity: tt.ecc}
+			apo, peri, err := sat.ApogeePerigeeKm()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if math.Round(apo) != tt.wantApo || math.Round(peri) != tt.wantP {
+				t.Errorf("apogee/perigee = %.2f/%.2f km, want %.0f/%.0f", apo, peri, tt.wantApo, tt.wantP)
+			}
+			// The mean of apogee and perigee radii is the semi-major axis.
+			if mean := (apo+peri)/2 + EarthRadiusKm; math.Abs(mean-(tt.altitude+EarthRadiusKm)) > 1e-6 {
+				t.Errorf("mean radius %.6f km, want %.6f", mean, tt.altitude+EarthRadiusKm)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// types/schema.go
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Statuses lists the satellite status values accepted by SatelliteSchema.
+var Statuses = []string{"active", "inactive", "planned", "decommissioned", "deorbited", "decayed"}
+
+// JSONSchema is the subset of JSON Schema (draft 2020-12) used to describe satellite records.
+type JSONSchema struct {
+	Schema           string                 `json:"$schema,omitempty"`
+	Title            string                 `json:"title,omitempty"`
+	Description      string                 `json:"description,omitempty"`
+	Type             string                 `json:"type"`
+	Properties       map[string]*JSONSchema `json:"properties,omitempty"`
+	Required         []string               `json:"required,omitempty"`
+	Enum             []string               `json:"enum,omitempty"`
+	Pattern          string                 `json:"pattern,omitempty"`
+	MinLength        *int                   `json:"minLength,omitempty"`
+	Minimum          *float64               `json:"minimum,omitempty"`
+	Maximum          *float64               `json:"maximum,omitempty"`
+	ExclusiveMaximum *float64               `json:"exclusiveMaximum,omitempty"`
+	// AdditionalProperties, on a map-typed property, describes each of its values.
+	AdditionalProperties *JSONSchema `json:"additionalProperties,omitempty"`
+	// Items, on an array-typed property, describes each element.
+	Items *JSONSchema `json:"items,omitempty"`
+}
+
+// SchemaViolation is one way a record fails to match a JSONSchema.
+type SchemaViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (v SchemaViolation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+// SatelliteSchema describes Satellite's JSON encoding. Properties and types come from the
+// struct's json tags via reflection; required fields from schema:"required" tags; enums and
+// ranges from the values the CLI accepts.
+func SatelliteSchema() *JSONSchema {
+	s := &JSONSchema{
+		Schema:     "https://json-schema.org/draft/2020-12/schema",
+		Title:      "Satellite",
+		Type:       "object",
+		Properties: map[string]*JSONSchema{},
+	}
+	t := reflect.TypeOf(Satellite{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		prop := &JSONSchema{Type: jsonType(field.Type.Kind())}
+		if field.Tag.Get("schema") == "required" {
+			s.Required = append(s.Required, name)
+			if prop.Type == "string" {
+				one := 1
+				prop.MinLength = &one
+			}
+		}
+		switch field.Type.Kind() {
+		case reflect.Map:
+			prop.AdditionalProperties = &JSONSchema{Type: jsonType(field.Type.Elem().Kind())}
+		case reflect.Slice:
+			prop.Items = &JSONSchema{Type: jsonType(field.Type.Elem().Kind())}
+		}
+		s.Properties[name] = prop
+	}
+
+	s.Properties["orbitType"].Enum = OrbitNames()
+	s.Properties["orbitType"].Description = "matched case-insensitively, as by add and query"
+	s.Properties["status"].Enum = Statuses
+	s.Properties["status"].Description = "matched case-insensitively, as by add and query"
+	s.Properties["launchDate"].Pattern = `^\d{4}-\d{2}-\d{2}$`
+	s.Properties["launchDate"].Description = "YYYY-MM-DD"
+	zero, one, maxInclination := 0.0, 1.0, 180.0
+	s.Properties["altitude"].Minimum = &zero
+	s.Properties["altitude"].Description = "km"
+	s.Properties["semiMajorAxis"].Minimum = &zero
+	s.Properties["semiMajorAxis"].Description = "km; altitude is derived from it when missing"
+	s.Properties["inclination"].Minimum = &zero
+	s.Properties["inclination"].Maximum = &maxInclination
+	s.Properties["inclination"].Description = "degrees"
+	minLongitude, maxLongitude := -180.0, 180.0
+	s.Properties["longitude"].Minimum = &minLongitude
+	s.Properties["longitude"].Maximum = &maxLongitude
+	s.Properties["longitude"].Description = "degrees east of a GEO/GSO slot"
+	s.Properties["eccentricity"].Minimum = &zero
+	s.Properties["eccentricity"].ExclusiveMaximum = &one
+	s.Properties["archived"].Description = "retired records are hidden from list and query by default"
+	s.Properties["updatedAt"].Description = "RFC3339 time the orbital elements were last refreshed (see 'satcli refresh-tle')"
+	s.Properties["custom"].Description = "organization-specific key/value attributes"
+	s.Properties["tags"].Description = "ad-hoc labels, matched case-insensitively (see 'satcli tag')"
+	return s
+}
+
+func jsonType(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// Validate checks a decoded JSON object (as produced by json.Unmarshal into interface{})
+// against an object schema and returns every violation, ordered by field.
+// Properties not described by the schema are allowed, and enums are compared ignoring case.
+func (s *JSONSchema) Validate(record interface{}) []SchemaViolation {
+	obj, ok := record.(map[string]interface{})
+	if !ok {
+		return []SchemaViolation{{Field: "(record)", Message: "must be a JSON object"}}
+	}
+	var violations []SchemaViolation
+	for _, name := range s.Required {
+		if _, present := obj[name]; !present {
+			violations = append(violations, SchemaViolation{Field: name, Message: "is required"})
+		}
+	}
+	for name, value := range obj {
+		if prop, known := s.Properties[name]; known {
+			if msg := prop.checkValue(value); msg != "" {
+				violations = append(violations, SchemaViolation{Field: name, Message: msg})
+			}
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Field < violations[j].Field })
+	return violations
+}
+
+// checkValue returns why value does not match the scalar schema s, or "" if it does.
+func (s *JSONSchema) checkValue(value interface{}) string {
+	switch s.Type {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("must be a string, got %s", describeJSONValue(value))
+		}
+		if s.MinLength != nil && len(str) < *s.MinLength {
+			return "must not be empty"
+		}
+		if len(s.Enum) > 0 && str != "" && !containsFold(s.Enum, str) { // The CLI compares orbit types and statuses ignoring case
+			return fmt.Sprintf("'%s' is not one of: %s", str, strings.Join(s.Enum, ", "))
+		}
+		if s.Pattern != "" && str != "" && !regexp.MustCompile(s.Pattern).MatchString(str) {
+			return fmt.Sprintf("'%s' does not match %s", str, s.Description)
+		}
+	case "number", "integer":
+		n, ok := value.(float64)
+		if !ok {
+			return fmt.Sprintf("must be a number, got %s", describeJSONValue(value))
+		}
+		if s.Type == "integer" && n != float64(int64(n)) {
+			return fmt.Sprintf("must be an integer, got %v", n)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return fmt.Sprintf("%v is below the minimum %v", n, *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			return fmt.Sprintf("%v is above the maximum %v", n, *s.Maximum)
+		}
+		if s.ExclusiveMaximum != nil && n >= *s.ExclusiveMaximum {
+			return fmt.Sprintf("%v must be less than %v", n, *s.ExclusiveMaximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("must be a boolean, got %s", describeJSONValue(value))
+		}
+	case "array":
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Sprintf("must be an array, got %s", describeJSONValue(value))
+		}
+		if s.Items != nil {
+			for i, item := range list {
+				if msg := s.Items.checkValue(item); msg != "" {
+					return fmt.Sprintf("item %d %s", i, msg)
+				}
+			}
+		}
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Sprintf("must be an object, got %s", describeJSONValue(value))
+		}
+		if s.AdditionalProperties != nil {
+			keys := make([]string, 0, len(obj))
+			for key := range obj {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if msg := s.AdditionalProperties.checkValue(obj[key]); msg != "" {
+					return fmt.Sprintf("'%s' %s", key, msg)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func describeJSONValue(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,50 @@
+// cmd/satcli/schema_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for satellite records",
+	Long: `Prints a JSON Schema (draft 2020-12) describing one satellite record as used by 'satcli import',
+'add --stdin' and JSON output: field types, required fields, and the accepted orbit types and statuses.
+With --format proto, prints instead the proto3 message definitions of 'satcli export --format protobuf'
+files, for generating readers in other languages.
+
+Examples:
+  satcli schema > satellite.schema.json
+  satcli schema --format proto > satellite.proto`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{skipDatastoreAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		switch strings.ToLower(format) {
+		case "json":
+		case "proto":
+			fmt.Fprint(cmd.OutOrStdout(), protoSchema())
+			return nil
+		default:
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid value for --format: '%s'. Use json or proto", format)
+		}
+		output, errJson := json.MarshalIndent(types.SatelliteSchema(), "", "  ")
+		if errJson != nil {
+			return fmt.Errorf("failed to marshal schema to JSON: %w", errJson)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.Flags().String("format", "json", "Schema format: json (JSON Schema) or proto (protobuf definitions)")
+	rootCmd.AddCommand(schemaCmd)
+}
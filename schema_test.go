@@ -0,0 +1,33 @@
+// types/schema_test.go
+package types
+
+import "testing"
+
+func TestSatelliteSchemaEnumsIgnoreCase(t *testing.T) {
+	schema := SatelliteSchema()
+	record := func(orbitType, status string) map[string]interface{} {
+		return map[string]interface{}{"name": "A", "orbitType": orbitType, "operator": "ESA", "status": status}
+	}
+	tests := []struct {
+		orbitType, status string
+		wantFields        []string
+	}{
+		{"LEO", "active", nil},
+		{"leo", "Active", nil},
+		{"Geo", "INACTIVE", nil},
+		{"LOW", "active", []string{"orbitType"}},
+		{"LEO", "retired", []string{"status"}},
+	}
+	for _, tt := range tests {
+		violations := schema.Validate(record(tt.orbitType, tt.status))
+		if len(violations) != len(tt.wantFields) {
+			t.Errorf("%s/%s: violations %v, want fields %v", tt.orbitType, tt.status, violations, tt.wantFields)
+			continue
+		}
+		for i, v := range violations {
+			if v.Field != tt.wantFields[i] {
+				t.Errorf("%s/%s: violation on %s, want %s", tt.orbitType, tt.status, v.Field, tt.wantFields[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// cmd/satcli/seed_cmd.go
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// seedOperator is a demo operator with the name prefix, orbit types and constellation flag it uses.
+type seedOperator struct {
+	Name          string
+	Prefix        string
+	OrbitTypes    []string
+	Constellation bool
+	Objective     string
+	Communication string
+}
+
+var seedOperators = []seedOperator{
+	{"SpaceX", "Starlink", []string{"LEO"}, true, "Broadband internet", "Ku/Ka-band"},
+	{"OneWeb", "OneWeb", []string{"LEO"}, true, "Broadband internet", "Ku-band"},
+	{"ESA", "Galileo", []string{"MEO"}, true, "Navigation", "L-band"},
+	{"ESA", "Sentinel", []string{"SSO"}, false, "Earth observation", "X-band"},
+	{"NASA", "Explorer", []string{"LEO", "SSO", "HEO"}, false, "Scientific research", "S-band"},
+	{"NOAA", "GOES", []string{"GEO"}, false, "Weather monitoring", "L-band"},
+	{"Intelsat", "Intelsat", []string{"GEO", "GSO"}, false, "Telecommunications", "C/Ku-band"},
+	{"Roscosmos", "Molniya", []string{"HEO"}, false, "Communications", "C-band"},
+	{"USSF", "GPS", []string{"MEO"}, true, "Navigation", "L-band"},
+	{"JAXA", "Himawari", []string{"GEO"}, false, "Weather monitoring", "Ka-band"},
+}
+
+// maxSeedCount keeps generation well within the name space (prefix + 4 digits per operator).
+const maxSeedCount = 10000
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Insert randomized demo satellites into the datastore (development aid)",
+	Long: `Adds --count realistic but fictional satellites: varied operators, orbit types with matching
+altitudes, and valid launch dates. Names never collide with existing records. Requires --yes,
+since the records are written to the real datastore. --seed-value makes the generated set repeatable.
+
+Examples:
+  satcli seed --count 50 --yes
+  satcli seed --count 20 --seed-value 42 --yes`,
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		count, _ := cmd.Flags().GetInt("count")
+		yes, _ := cmd.Flags().GetBool("yes")
+		seedValue, _ := cmd.Flags().GetInt64("seed-value")
+		cmd.SilenceUsage = true
+		if count < 1 || count > maxSeedCount {
+			return fmt.Errorf("--count must be between 1 and %d", maxSeedCount)
+		}
+		if !yes {
+			return fmt.Errorf("refusing to add %d demo record(s) to %s without --yes", count, mustDatastorePath())
+		}
+		if !cmd.Flags().Changed("seed-value") {
+			seedValue = time.Now().UnixNano()
+		}
+
+		existing, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		for _, sat := range generateSeedSatellites(rand.New(rand.NewSource(seedValue)), count, existing) {
+			if err := datastore.AddSatellite(sat); err != nil {
+				return err
+			}
+		}
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to save demo records: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Seeded %d demo satellite(s) (seed value %d).\n", count, seedValue)
+		return nil
+	},
+}
+
+// generateSeedSatellites returns count random satellites whose names are not in existing.
+func generateSeedSatellites(r *rand.Rand, count int, existing map[string]types.Satellite) []types.Satellite {
+	taken := make(map[string]bool, len(existing)+count)
+	for name := range existing {
+		taken[name] = true
+	}
+	start := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := int(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC).Sub(start).Hours() / 24)
+
+	sats := make([]types.Satellite, 0, count)
+	for len(sats) < count {
+		op := seedOperators[r.Intn(len(seedOperators))]
+		name := fmt.Sprintf("%s-%04d", op.Prefix, 1+r.Intn(9999))
+		if taken[name] {
+			continue
+		}
+		taken[name] = true
+
+		orbitType := op.OrbitTypes[r.Intn(len(op.OrbitTypes))]
+		orbit, _ := types.LookupOrbit(orbitType)
+		altitude := orbit.AltitudeMinKm + r.Float64()*(orbit.AltitudeMaxKm-orbit.AltitudeMinKm)
+		inclination := r.Float64() * 98
+		switch orbitType {
+		case "GEO":
+			inclination = r.Float64() * 0.1
+		case "SSO":
+			inclination = 96 + r.Float64()*3
+		case "HEO":
+			inclination = 63.4
+		}
+		eccentricity := r.Float64() * 0.002
+		if orbitType == "HEO" {
+			eccentricity = 0.6 + r.Float64()*0.15
+		}
+		status := "active"
+		if r.Intn(5) == 0 {
+			status = types.Statuses[1+r.Intn(len(types.Statuses)-1)]
+		}
+
+		sats = append(sats, types.Satellite{
+			Name:             name,
+			Operator:         op.Name,
+			Status:           status,
+			OrbitType:        orbitType,
+			Altitude:         float64(int(altitude)),
+			Inclination:      float64(int(inclination*100)) / 100,
+			Eccentricity:     float64(int(eccentricity*10000)) / 10000,
+			LaunchDate:       start.AddDate(0, 0, r.Intn(days)).Format(config.DateFormat),
+			Constellation:    op.Constellation,
+			MissionObjective: op.Objective,
+			Communication:    op.Communication,
+			PowerSystem:      "Solar",
+			Size:             float64(1+r.Intn(80)) / 10,
+			Weight:           float64(100 + r.Intn(6000)),
+		})
+	}
+	return sats
+}
+
+// mustDatastorePath returns the datastore path for messages, or a placeholder if it cannot be resolved.
+func mustDatastorePath() string {
+	path, err := datastore.Path()
+	if err != nil {
+		return "the datastore"
+	}
+	return path
+}
+
+func init() {
+	seedCmd.Flags().Int("count", 50, "Number of demo satellites to add")
+	seedCmd.Flags().Bool("yes", false, "Confirm writing demo records to the datastore")
+	seedCmd.Flags().Int64("seed-value", 0, "Random seed for a repeatable set (default: random)")
+	rootCmd.AddCommand(seedCmd)
+}
@@ -0,0 +1,58 @@
+// cmd/satcli/select_cmd.go
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var selectCmd = &cobra.Command{
+	Use:   "select",
+	Short: "Pick a satellite name interactively and print it, for use in shell scripts",
+	Long: `Opens a fuzzy picker over the stored satellite names: type to filter, up/down to move, Enter to
+choose. The chosen name is the only thing written to stdout; the picker draws on stderr, so
+command substitution captures just the name. Esc or Ctrl-C cancels with a non-zero exit status.
+
+Examples:
+  satcli get "$(satcli select)"
+  satcli rename "$(satcli select)" ISS-2`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		cmd.SilenceUsage = true
+		if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) {
+			return fmt.Errorf("select requires an interactive terminal on stdin and stderr")
+		}
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		if len(satsMap) == 0 {
+			return fmt.Errorf("no satellites to select from")
+		}
+
+		final, err := tea.NewProgram(tui.NewSelectModel(sortedKeys(satsMap)), tea.WithOutput(os.Stderr)).Run()
+		if err != nil {
+			return fmt.Errorf("error running TUI: %w", err)
+		}
+		m, _ := final.(tui.SelectModel)
+		if m.Selected == "" {
+			return fmt.Errorf("selection canceled")
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), m.Selected)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selectCmd)
+}
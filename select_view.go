@@ -0,0 +1,174 @@
+// tui/select_view.go
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// selectVisibleRows is how many matching names the picker shows at once.
+const selectVisibleRows = 10
+
+var (
+	selectPromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("69")).Bold(true)
+	selectMatchStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("69"))
+	selectHintStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
+
+// SelectModel is a fuzzy picker over names: typing narrows the list to names containing the typed
+// characters in order, best matches first; Enter chooses the highlighted name.
+type SelectModel struct {
+	Names    []string
+	Selected string // The chosen name, set when the program quits after Enter
+	Canceled bool   // The user quit without choosing
+
+	query   []rune
+	matches []fuzzyMatch
+	cursor  int
+	offset  int
+}
+
+type fuzzyMatch struct {
+	name      string
+	score     int
+	positions []int // Rune indexes of name matching the query, for highlighting
+}
+
+// NewSelectModel creates a picker over names, listed in the given order until something is typed.
+func NewSelectModel(names []string) SelectModel {
+	m := SelectModel{Names: names}
+	m.filter()
+	return m
+}
+
+// Init is a required method for tea.Model.
+func (m SelectModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update is a required method for tea.Model.
+func (m SelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.Type {
+	case tea.KeyCtrlC, tea.KeyEsc:
+		m.Canceled = true
+		return m, tea.Quit
+	case tea.KeyEnter:
+		if len(m.matches) == 0 {
+			return m, nil
+		}
+		m.Selected = m.matches[m.cursor].name
+		return m, tea.Quit
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyDown, tea.KeyCtrlN:
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+	case tea.KeyBackspace:
+		if len(m.query) > 0 {
+			m.query = m.query[:len(m.query)-1]
+			m.filter()
+		}
+	case tea.KeyCtrlU:
+		m.query = nil
+		m.filter()
+	case tea.KeySpace:
+		m.query = append(m.query, ' ')
+		m.filter()
+	case tea.KeyRunes:
+		m.query = append(m.query, key.Runes...)
+		m.filter()
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+selectVisibleRows {
+		m.offset = m.cursor - selectVisibleRows + 1
+	}
+	return m, nil
+}
+
+// filter recomputes the matches for the query and selects the best one.
+func (m *SelectModel) filter() {
+	m.matches = m.matches[:0]
+	for _, name := range m.Names {
+		if match, ok := fuzzyScore(name, m.query); ok {
+			m.matches = append(m.matches, match)
+		}
+	}
+	if len(m.query) > 0 {
+		sort.SliceStable(m.matches, func(i, j int) bool { return m.matches[i].score > m.matches[j].score })
+	}
+	m.cursor, m.offset = 0, 0
+}
+
+// fuzzyScore matches query against name case-insensitively as a subsequence. Consecutive matched
+// characters, matches at the start of a word, and shorter names score higher.
+func fuzzyScore(name string, query []rune) (fuzzyMatch, bool) {
+	runes := []rune(name)
+	match := fuzzyMatch{name: name}
+	q := 0
+	for i := 0; i < len(runes) && q < len(query); i++ {
+		if unicode.ToLower(runes[i]) != unicode.ToLower(query[q]) {
+			continue
+		}
+		match.score++
+		if n := len(match.positions); n > 0 && match.positions[n-1] == i-1 {
+			match.score += 4
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			match.score += 3
+		}
+		match.positions = append(match.positions, i)
+		q++
+	}
+	if q < len(query) {
+		return fuzzyMatch{}, false
+	}
+	match.score = match.score*100 - len(runes)
+	return match, true
+}
+
+// View is a required method for tea.Model.
+func (m SelectModel) View() string {
+	if m.Selected != "" || m.Canceled {
+		return "" // Leave nothing behind on the terminal; the selection goes to stdout
+	}
+	var b strings.Builder
+	b.WriteString(selectPromptStyle.Render("> ") + string(m.query) + "\n")
+	last := min(m.offset+selectVisibleRows, len(m.matches))
+	for i := m.offset; i < last; i++ {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = selectPromptStyle.Render("▸ ")
+		}
+		b.WriteString(cursor + highlightMatch(m.matches[i]) + "\n")
+	}
+	b.WriteString(selectHintStyle.Render(fmt.Sprintf("%d/%d • type to filter • up/down to move • enter to choose • esc to cancel", len(m.matches), len(m.Names))) + "\n")
+	return b.String()
+}
+
+// highlightMatch renders the name with its matched characters styled.
+func highlightMatch(match fuzzyMatch) string {
+	var b strings.Builder
+	next := 0
+	for i, r := range []rune(match.name) {
+		if next < len(match.positions) && match.positions[next] == i {
+			b.WriteString(selectMatchStyle.Render(string(r)))
+			next++
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,218 @@
+// cmd/satcli/serve_cmd.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// serveShutdownTimeout is how long in-flight requests get to finish after SIGINT or SIGTERM.
+const serveShutdownTimeout = 5 * time.Second
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the satellite records read-only over HTTP",
+	Long: `Unlocks the datastore once and serves its records as JSON until interrupted:
+
+  GET /satellites          records matching the query string, sorted by name
+  GET /satellites/{name}   one record by exact name (404 if there is none)
+
+Query parameters are the filter flags of 'satcli query' without the dashes, so
+/satellites?operator=ESA&min-altitude=500&tag=watchlist&tag=legacy&tag-mode=all matches
+'satcli query --operator ESA --min-altitude 500 --tag watchlist --tag legacy --tag-mode all'.
+Repeat a parameter where the flag is repeatable, give boolean flags as ?include-archived or
+?include-archived=true, and use sort-by to change the order. Unknown parameters and invalid values
+are answered with 400 and a JSON error, so a typo never returns every record.
+
+The server is strictly read-only: only GET (and HEAD) requests are accepted, and nothing is ever
+saved. It serves the records as loaded at startup; restart it to pick up changes made since.
+--addr defaults to the loopback interface; use --addr :8080 to share the data on the local
+network, which makes every record readable by anyone who can reach the port. SIGINT or SIGTERM
+stops accepting connections and lets requests in flight finish.
+
+Examples:
+  satcli serve
+  satcli serve --addr :8080 --read-only
+  curl 'http://localhost:8080/satellites?orbit-type=GEO&status=active'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		addr, _ := cmd.Flags().GetString("addr")
+		readOnly, _ := cmd.Flags().GetBool("read-only")
+		cmd.SilenceUsage = true
+		if !readOnly {
+			return fmt.Errorf("only read-only serving is supported; --read-only cannot be false")
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /satellites", serveSatellites)
+		mux.HandleFunc("GET /satellites/{name}", serveSatellite)
+		mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+			writeServeError(w, http.StatusNotFound, "not found; use /satellites or /satellites/{name}")
+		})
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		server := &http.Server{
+			Handler:           logRequests(mux),
+			ReadHeaderTimeout: 10 * time.Second,
+			BaseContext:       func(net.Listener) context.Context { return ctx },
+		}
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("cannot listen on %s: %w", addr, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Serving satellites read-only on http://%s/satellites (Ctrl+C to stop)\n", listener.Addr())
+
+		errServe := make(chan error, 1)
+		go func() { errServe <- server.Serve(listener) }()
+		select {
+		case err := <-errServe:
+			return fmt.Errorf("server stopped: %w", err)
+		case <-ctx.Done():
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shut down the server: %w", err)
+		}
+		if err := <-errServe; !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server stopped: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "Server stopped.")
+		return nil
+	},
+}
+
+// serveSatellites answers GET /satellites with the records matching the query string.
+func serveSatellites(w http.ResponseWriter, r *http.Request) {
+	filter, sortBy, err := filterFromQuery(r.URL.Query())
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	satsMap, err := datastore.GetSatellitesCtx(r.Context())
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sats := make([]types.Satellite, 0, len(satsMap))
+	for _, sat := range satsMap {
+		sats = append(sats, sat)
+	}
+	matched := FilterSatellites(sats, filter)
+	if err := sortSatellites(matched, sortBy); err != nil {
+		writeServeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if matched == nil {
+		matched = []types.Satellite{} // An empty JSON array rather than null
+	}
+	writeServeJSON(w, http.StatusOK, matched)
+}
+
+// serveSatellite answers GET /satellites/{name} with the record of that exact name.
+func serveSatellite(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	satsMap, err := datastore.GetSatellitesCtx(r.Context())
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sat, ok := satsMap[name]
+	if !ok {
+		writeServeError(w, http.StatusNotFound, fmt.Sprintf("satellite '%s' not found", name))
+		return
+	}
+	writeServeJSON(w, http.StatusOK, sat)
+}
+
+// filterFromQuery builds a Filter from query parameters named after the query filter flags,
+// checking them exactly as the flags are checked, and returns the sort-by parameter.
+func filterFromQuery(values url.Values) (Filter, string, error) {
+	flags := &cobra.Command{Use: "satellites"}
+	addQueryFilterFlags(flags)
+	addQueryMatchFlags(flags)
+	addAltitudeUnitFlag(flags)
+	flags.Flags().String("sort-by", "name", "")
+	for name, list := range values {
+		flag := flags.Flags().Lookup(name)
+		if flag == nil {
+			return Filter{}, "", fmt.Errorf("unknown query parameter '%s'", name)
+		}
+		for _, value := range list {
+			if value == "" && flag.NoOptDefVal != "" {
+				value = flag.NoOptDefVal // ?include-archived means true, as the bare flag does
+			}
+			if err := flags.Flags().Set(name, value); err != nil {
+				return Filter{}, "", fmt.Errorf("invalid value '%s' for %s: want a %s", value, name, flag.Value.Type())
+			}
+		}
+	}
+	filter, err := filterFromFlags(flags)
+	sortBy, _ := flags.Flags().GetString("sort-by")
+	return filter, sortBy, err
+}
+
+// writeServeJSON writes v as an indented JSON response.
+func writeServeJSON(w http.ResponseWriter, status int, v interface{}) {
+	output, errJson := json.MarshalIndent(v, "", "  ")
+	if errJson != nil {
+		writeServeError(w, http.StatusInternalServerError, errJson.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(output, '\n'))
+}
+
+// writeServeError writes {"error": message} with status.
+func writeServeError(w http.ResponseWriter, status int, message string) {
+	output, _ := json.Marshal(map[string]string{"error": message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(output, '\n'))
+}
+
+// statusRecorder captures the status code of a response for logRequests.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs each request under --log-level info.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		logger.Info("request served", "method", r.Method, "url", r.URL.String(), "remote", r.RemoteAddr,
+			"status", rec.status, "duration", time.Since(start))
+	})
+}
+
+func init() {
+	serveCmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on, e.g. :8080 for every interface")
+	serveCmd.Flags().Bool("read-only", true, "Serve without ever changing the datastore (the only mode supported)")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,153 @@
+// cmd/satcli/settings.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// skipDatastoreAnnotation marks commands that never need the datastore unlocked.
+const skipDatastoreAnnotation = "satcli/skip-datastore"
+
+// settingEnvVars maps each flag that can be defaulted from the environment or config file to its env var.
+var settingEnvVars = map[string]string{
+	"output":         config.OutputEnvVar,
+	"datastore":      config.DatastoreEnvVar,
+	"sort-by":        config.SortByEnvVar,
+	"color":          config.ColorEnvVar,
+	"kdf":            config.KDFEnvVar,
+	"cipher":         config.CipherEnvVar,
+	"argon2-time":    config.Argon2TimeEnvVar,
+	"argon2-memory":  config.Argon2MemoryEnvVar,
+	"argon2-threads": config.Argon2ThreadsEnvVar,
+	"quiet":          config.QuietEnvVar,
+	"log-level":      config.LogLevelEnvVar,
+}
+
+// settingSources records where each resolved setting came from: flag, env, config or default.
+var settingSources = map[string]string{}
+
+// colorEnabled reports whether styled output should be produced, as resolved from --color.
+var colorEnabled bool
+
+// colorOperators reports whether operator names are rendered in their per-operator color
+// (--operator-colors, only effective when color is enabled).
+var colorOperators bool
+
+// quiet reports whether non-error notices are suppressed (--quiet).
+var quiet bool
+
+// noticeOut receives notices; the root pre-run sets it to the running command's error writer.
+var noticeOut io.Writer = os.Stderr
+
+// noticef prints a non-error notice or warning to noticeOut unless --quiet is set.
+func noticef(format string, args ...interface{}) {
+	if !quiet {
+		fmt.Fprintf(noticeOut, format, args...)
+	}
+}
+
+// skipsDatastore reports whether cmd or one of its parents opted out of datastore initialization.
+func skipsDatastore(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations[skipDatastoreAnnotation] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// applySettingDefaults fills flags not given on the command line, in order of precedence:
+// flag > env > config file > built-in default.
+func applySettingDefaults(cmd *cobra.Command) error {
+	path, err := config.Path()
+	if err != nil {
+		return &exitCodeError{exitIO, err}
+	}
+	file, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	if err := types.SetOperatorAliases(file.OperatorAliases); err != nil {
+		return fmt.Errorf("invalid operator-aliases in config file %s: %w", path, err)
+	}
+	fileValues := file.Values()
+
+	for name := range settingEnvVars {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			continue
+		}
+		value, source := resolveSetting(cmd, name, fileValues)
+		settingSources[name] = source
+		if source != "env" && source != "config" {
+			continue
+		}
+		// Set the value directly so flag.Changed keeps meaning "given on the command line".
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid %s value '%s' from %s: %w", name, value, source, err)
+		}
+	}
+	return nil
+}
+
+// resolveSetting returns the value in effect for cmd's flag name and its source (flag, env, config
+// or default) without changing the flag. fileValues are the config file's entries (File.Values).
+func resolveSetting(cmd *cobra.Command, name string, fileValues map[string]string) (value, source string) {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		return "", ""
+	}
+	if flag.Changed {
+		return flag.Value.String(), "flag"
+	}
+	if value := os.Getenv(settingEnvVars[name]); value != "" {
+		return value, "env"
+	}
+	if value := fileValues[name]; value != "" {
+		return value, "config"
+	}
+	return flag.DefValue, "default"
+}
+
+// applyColorMode resolves --color (auto, always, never) and configures lipgloss accordingly.
+func applyColorMode(mode string) error {
+	switch strings.ToLower(mode) {
+	case "auto", "":
+		colorEnabled = term.IsTerminal(int(os.Stdout.Fd())) && os.Getenv("NO_COLOR") == ""
+	case "always":
+		colorEnabled = true
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	case "never":
+		colorEnabled = false
+	default:
+		return fmt.Errorf("invalid value for --color: '%s'. Use auto, always, or never", mode)
+	}
+	if !colorEnabled {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	return nil
+}
+
+// logger records the CLI's own diagnostic events; PersistentPreRunE replaces it according to --log-level.
+var logger = slog.New(slog.DiscardHandler)
+
+// newLogger returns a text logger on w at level (debug, info, warn or error).
+func newLogger(w io.Writer, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid value for --log-level: '%s'. Use debug, info, warn, or error", level)
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: lvl})), nil
+}
@@ -0,0 +1,157 @@
+// cmd/satcli/slots_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// slotCluster is a run of geosynchronous satellites whose slots are each within tolerance of a
+// neighbor. MinLongitude is greater than MaxLongitude for a cluster that crosses the antimeridian.
+type slotCluster struct {
+	MinLongitude float64           `json:"minLongitude"`
+	MaxLongitude float64           `json:"maxLongitude"`
+	Span         float64           `json:"span"`
+	Satellites   []types.Satellite `json:"satellites"`
+}
+
+// slotEpsilon absorbs floating-point error, so neighbors exactly --tolerance apart are grouped.
+const slotEpsilon = 1e-9
+
+var slotsCmd = &cobra.Command{
+	Use:   "slots",
+	Short: "Report GEO/GSO satellites occupying overlapping longitude slots",
+	Long: `Sorts the active GEO and GSO satellites by slot longitude and groups neighbors whose longitudes
+are within --tolerance degrees (single linkage, so a cluster can span more than the tolerance),
+including across the antimeridian. Clusters of two or more are reported, from west to east.
+--include-inactive also considers satellites whose status is not active, and archived records
+are left out unless --include-archived or --archived-only is set. A longitude of 0 is a slot at
+0 deg east like any other, so GEO records added without a longitude cluster there.
+
+Examples:
+  satcli slots
+  satcli slots --tolerance 0.5 --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		tolerance, _ := cmd.Flags().GetFloat64("tolerance")
+		includeInactive, _ := cmd.Flags().GetBool("include-inactive")
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+		if tolerance < 0 {
+			return fmt.Errorf("tolerance cannot be negative")
+		}
+		archived, err := archivedFilterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		var geo []types.Satellite
+		for _, sat := range satsMap {
+			if !sat.IsGeosynchronous() || (archived != nil && sat.Archived != *archived) {
+				continue
+			}
+			if !includeInactive && !strings.EqualFold(strings.TrimSpace(sat.Status), "active") {
+				continue
+			}
+			geo = append(geo, sat)
+		}
+		clusters := findSlotClusters(geo, tolerance)
+
+		if strings.ToLower(outputFormat) == "json" {
+			if clusters == nil {
+				clusters = []slotCluster{}
+			}
+			output, errJson := json.MarshalIndent(clusters, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal slot clusters to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+		printSlotClusters(cmd.OutOrStdout(), clusters, len(geo), tolerance)
+		return nil
+	},
+}
+
+// findSlotClusters sorts sats by longitude and groups each with its neighbors within tolerance
+// degrees, joining the westernmost and easternmost clusters when they meet across 180 deg. Only
+// clusters of two or more are returned, ordered by their western edge.
+func findSlotClusters(sats []types.Satellite, tolerance float64) []slotCluster {
+	sort.Slice(sats, func(i, j int) bool {
+		if sats[i].Longitude != sats[j].Longitude {
+			return sats[i].Longitude < sats[j].Longitude
+		}
+		return sats[i].Name < sats[j].Name
+	})
+	var runs [][]types.Satellite
+	for i, sat := range sats {
+		if i == 0 || sat.Longitude-sats[i-1].Longitude > tolerance+slotEpsilon {
+			runs = append(runs, nil)
+		}
+		runs[len(runs)-1] = append(runs[len(runs)-1], sat)
+	}
+	if len(runs) > 1 && sats[0].Longitude+360-sats[len(sats)-1].Longitude <= tolerance+slotEpsilon {
+		last := len(runs) - 1
+		runs[0] = append(runs[last], runs[0]...) // Now starts east of 180 and ends west of it
+		runs = runs[:last]
+	}
+
+	var clusters []slotCluster
+	for _, run := range runs {
+		if len(run) < 2 {
+			continue
+		}
+		span := 0.0
+		for i := 1; i < len(run); i++ {
+			span += math.Mod(run[i].Longitude-run[i-1].Longitude+360, 360)
+		}
+		clusters = append(clusters, slotCluster{
+			MinLongitude: run[0].Longitude,
+			MaxLongitude: run[len(run)-1].Longitude,
+			Span:         math.Round(span*1e6) / 1e6,
+			Satellites:   run,
+		})
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].MinLongitude < clusters[j].MinLongitude })
+	return clusters
+}
+
+func printSlotClusters(out io.Writer, clusters []slotCluster, considered int, tolerance float64) {
+	if len(clusters) == 0 {
+		fmt.Fprintf(out, "No overlapping slots: none of the %d geosynchronous satellite(s) are within %.2f deg of another.\n", considered, tolerance)
+		return
+	}
+	fmt.Fprintf(out, "Found %d cluster(s) of satellites within %.2f deg of a neighbor, among %d geosynchronous satellite(s):\n", len(clusters), tolerance, considered)
+	var rows [][]string
+	for i, c := range clusters {
+		for _, sat := range c.Satellites {
+			rows = append(rows, []string{strconv.Itoa(i + 1), fmt.Sprintf("%.2f", c.Span), sat.Name, longitudeCell(sat), sat.Operator, sat.Status})
+		}
+	}
+	printTableRows(out, []tableColumn{{Header: "CLUSTER"}, {Header: "SPAN (deg)"}, {Header: "NAME"}, {Header: "LONGITUDE (deg)"}, {Header: "OPERATOR"}, {Header: "STATUS"}}, rows)
+}
+
+func init() {
+	slotsCmd.Flags().Float64("tolerance", 0.1, "Largest longitude difference in degrees between neighbors in one cluster")
+	slotsCmd.Flags().Bool("include-inactive", false, "Also consider satellites whose status is not active")
+	slotsCmd.Flags().StringP("output", "O", "table", "Output format: table or json")
+	addArchivedFlags(slotsCmd)
+	rootCmd.AddCommand(slotsCmd)
+}
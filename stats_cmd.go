@@ -0,0 +1,230 @@
+// cmd/satcli/stats_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+)
+
+// histogramBarWidth is the length of the bar for the fullest bin.
+const histogramBarWidth = 40
+
+// histogramUnits are the fields stats --histogram can chart, with their units.
+var histogramUnits = map[string]string{"altitude": "km", "inclination": "deg"}
+
+// fieldStats summarizes one numeric field.
+type fieldStats struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	Max  float64 `json:"max"`
+}
+
+// histogramBin is one bin of a histogram: the values from From up to To (inclusive for the last bin).
+type histogramBin struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize the stored satellites, optionally with a histogram",
+	Long: `Prints the number of records, how many are active, and the minimum, mean and maximum altitude
+and inclination. --histogram altitude or --histogram inclination adds an ASCII chart of that field
+over --bins equal bins spanning its range across the selected records; records without an altitude
+are left out of the altitude chart. --operator, --status and --orbit-type narrow the selection, and
+archived records are excluded unless --include-archived or --archived-only is given.
+
+Examples:
+  satcli stats
+  satcli stats --histogram altitude --bins 10
+  satcli stats --histogram inclination --orbit-type LEO --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		operator, _ := cmd.Flags().GetString("operator")
+		status, _ := cmd.Flags().GetString("status")
+		orbitType, _ := cmd.Flags().GetString("orbit-type")
+		field, _ := cmd.Flags().GetString("histogram")
+		bins, _ := cmd.Flags().GetInt("bins")
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+		field = strings.ToLower(strings.TrimSpace(field))
+		unit, chartOK := histogramUnits[field]
+		if field != "" && !chartOK {
+			return fmt.Errorf("invalid --histogram '%s'. Use one of: %s", field, strings.Join(sortedKeys(histogramUnits), ", "))
+		}
+		if bins < 1 {
+			return fmt.Errorf("--bins must be at least 1")
+		}
+		archivedFilter, err := archivedFilterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		var selected []types.Satellite
+		for _, name := range sortedKeys(satsMap) {
+			sat := satsMap[name]
+			if archivedFilter != nil && sat.Archived != *archivedFilter {
+				continue
+			}
+			if (operator != "" && !strings.EqualFold(sat.Operator, operator)) ||
+				(status != "" && !strings.EqualFold(sat.Status, status)) ||
+				(orbitType != "" && !strings.EqualFold(sat.OrbitType, orbitType)) {
+				continue
+			}
+			selected = append(selected, sat)
+		}
+		if len(selected) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No satellites found matching specified criteria.")
+			return nil
+		}
+
+		active := 0
+		var altitudes, inclinations []float64
+		for _, sat := range selected {
+			if strings.EqualFold(sat.Status, "active") {
+				active++
+			}
+			if sat.Altitude > 0 { // An altitude of 0 means it was never recorded
+				altitudes = append(altitudes, sat.Altitude)
+			}
+			inclinations = append(inclinations, sat.Inclination)
+		}
+		var histogram []histogramBin
+		switch field {
+		case "altitude":
+			histogram = binValues(altitudes, bins)
+		case "inclination":
+			histogram = binValues(inclinations, bins)
+		}
+
+		if strings.ToLower(outputFormat) == "json" {
+			report := struct {
+				Records     int            `json:"records"`
+				Active      int            `json:"active"`
+				Altitude    *fieldStats    `json:"altitude,omitempty"`
+				Inclination *fieldStats    `json:"inclination,omitempty"`
+				Field       string         `json:"histogramField,omitempty"`
+				Histogram   []histogramBin `json:"histogram,omitempty"`
+			}{len(selected), active, summarizeField(altitudes), summarizeField(inclinations), field, histogram}
+			output, errJson := json.MarshalIndent(report, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal stats to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+
+		out := cmd.OutOrStdout()
+		rows := [][]string{{"Records:", strconv.Itoa(len(selected))}, {"Active:", strconv.Itoa(active)}}
+		for _, f := range []struct {
+			label string
+			stats *fieldStats
+			unit  string
+		}{{"Altitude:", summarizeField(altitudes), "km"}, {"Inclination:", summarizeField(inclinations), "deg"}} {
+			if f.stats != nil {
+				rows = append(rows, []string{f.label, fmt.Sprintf("min %s, mean %s, max %s %s",
+					formatStat(f.stats.Min), formatStat(f.stats.Mean), formatStat(f.stats.Max), f.unit)})
+			}
+		}
+		printAlignedRows(out, rows)
+		if !chartOK {
+			return nil
+		}
+		fmt.Fprintf(out, "\nHistogram of %s (%s):\n", field, unit)
+		if len(histogram) == 0 {
+			fmt.Fprintf(out, "No records with a %s.\n", field)
+			return nil
+		}
+		printHistogram(out, histogram)
+		return nil
+	},
+}
+
+// summarizeField returns the minimum, mean and maximum of values, or nil if there are none.
+func summarizeField(values []float64) *fieldStats {
+	if len(values) == 0 {
+		return nil
+	}
+	s := fieldStats{Min: values[0], Max: values[0]}
+	sum := 0.0
+	for _, v := range values {
+		s.Min, s.Max = math.Min(s.Min, v), math.Max(s.Max, v)
+		sum += v
+	}
+	s.Mean = sum / float64(len(values))
+	return &s
+}
+
+// binValues counts values into bins equal bins from their minimum to their maximum. If all values
+// are equal there is a single bin.
+func binValues(values []float64, bins int) []histogramBin {
+	s := summarizeField(values)
+	if s == nil {
+		return nil
+	}
+	if s.Max == s.Min {
+		return []histogramBin{{From: s.Min, To: s.Max, Count: len(values)}}
+	}
+	width := (s.Max - s.Min) / float64(bins)
+	histogram := make([]histogramBin, bins)
+	for i := range histogram {
+		histogram[i].From = s.Min + float64(i)*width
+		histogram[i].To = s.Min + float64(i+1)*width
+	}
+	histogram[bins-1].To = s.Max
+	for _, v := range values {
+		i := min(int((v-s.Min)/width), bins-1) // The maximum falls in the last bin
+		histogram[i].Count++
+	}
+	return histogram
+}
+
+// printHistogram writes one row per bin: its range, a bar scaled to the fullest bin, and its count.
+func printHistogram(out io.Writer, histogram []histogramBin) {
+	most := 0
+	for _, bin := range histogram {
+		most = max(most, bin.Count)
+	}
+	rows := make([][]string, len(histogram))
+	for i, bin := range histogram {
+		bar := strings.Repeat("█", bin.Count*histogramBarWidth/most)
+		if bar == "" && bin.Count > 0 {
+			bar = "▏" // Keep non-empty bins visible next to a much fuller one
+		}
+		rows[i] = []string{formatStat(bin.From) + " - " + formatStat(bin.To), "│" + bar, strconv.Itoa(bin.Count)}
+	}
+	printAlignedRows(out, rows)
+}
+
+// formatStat formats a statistic with at most one decimal place.
+func formatStat(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+func init() {
+	statsCmd.Flags().String("operator", "", "Only satellites of this operator (case-insensitive)")
+	statsCmd.Flags().StringP("status", "s", "", "Only satellites with this status (case-insensitive)")
+	statsCmd.Flags().String("orbit-type", "", "Only satellites in this orbit type (case-insensitive)")
+	addArchivedFlags(statsCmd)
+	statsCmd.Flags().String("histogram", "", "Also chart this field: altitude or inclination")
+	statsCmd.Flags().Int("bins", 10, "Number of histogram bins")
+	statsCmd.Flags().StringP("output", "O", "text", "Output format: text or json")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,39 @@
+// cmd/satcli/stdio.go
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// stdioPath is the --file value meaning standard input, or standard output for export.
+const stdioPath = "-"
+
+// openInputFile opens path for reading, or standard input for "-", returning a name for messages,
+// the size if known (zero for a pipe), and a func to close it that leaves standard input open.
+func openInputFile(path string) (in io.Reader, source string, size int64, closeInput func() error, err error) {
+	if path == stdioPath {
+		return os.Stdin, "stdin", 0, func() error { return nil }, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", 0, nil, err
+	}
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+	return f, path, size, f.Close, nil
+}
+
+// createOutputFile creates (or truncates) path for writing, or returns stdout for "-", which
+// closing leaves open. Commands pass cmd.OutOrStdout(), so output redirected with SetOut follows.
+func createOutputFile(path string, stdout io.Writer) (io.WriteCloser, error) {
+	if path == stdioPath {
+		return nopWriteCloser{stdout}, nil
+	}
+	return os.Create(path)
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,45 @@
+// cmd/satcli/stdio_test.go
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateOutputFileWritesDashToStdout(t *testing.T) {
+	var stdout bytes.Buffer
+	f, err := createOutputFile(stdioPath, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("name,operator\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := stdout.String(); got != "name,operator\n" {
+		t.Errorf("stdout = %q", got)
+	}
+}
+
+func TestCreateOutputFileCreatesPath(t *testing.T) {
+	var stdout bytes.Buffer
+	path := filepath.Join(t.TempDir(), "export.csv")
+	f, err := createOutputFile(path, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("data"))
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "data" {
+		t.Errorf("file = %q, %v", data, err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("stdout got %q", stdout.String())
+	}
+}
@@ -1,7 +1,13 @@
 // tui/styles.go
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // You can centralize more lipgloss styles here if the TUI grows.
 // For example:
@@ -14,3 +20,17 @@ var (
 // Ensure styles defined in list_view.go are either moved here and exported,
 // or kept local if only used by list_view.go.
 // If moved here, they need to be Exported (e.g., DocStyle instead of docStyle).
+
+// OperatorColor maps an operator name to a stable color from the 256-color palette.
+// The name is hashed (FNV-1a, case-insensitive) into the 6x6x6 color cube (17-231),
+// skipping the base 16 colors and grays so the result stays readable and is the same across runs.
+func OperatorColor(operator string) lipgloss.Color {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(strings.TrimSpace(operator))))
+	return lipgloss.Color(strconv.Itoa(17 + int(h.Sum32()%215)))
+}
+
+// OperatorStyle renders text in the operator's color.
+func OperatorStyle(operator string) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(OperatorColor(operator))
+}
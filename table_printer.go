@@ -1,29 +1,543 @@
 // cmd/satcli/table_printer.go
 package main
 
-// Currently, printSatellitesTable is in main.go.
-// It could be moved here for better organization if main.go grows too large.
-// Remember to adjust visibility (e.g., make it public if called from other packages,
-// or keep it private if only used within the main cmd package).
-/*
 import (
 	"fmt"
+	"io"
 	"os"
-	"text/tabwriter"
-	"github.com/yackko/satcom-code/types" // Adjust import path
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yackko/satcom-code/tui"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// tableColumn describes one column of the table output, keyed by the field's JSON name.
+type tableColumn struct {
+	Key    string
+	Header string
+	Value  func(sat types.Satellite) string
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// allTableColumns lists every field that can appear in the table, in display order.
+var allTableColumns = []tableColumn{
+	{"name", "NAME", func(s types.Satellite) string { return s.Name }},
+	{"operator", "OPERATOR", func(s types.Satellite) string { return s.Operator }},
+	{"status", "STATUS", func(s types.Satellite) string { return s.Status }},
+	{"orbitType", "ORBIT TYPE", func(s types.Satellite) string { return s.OrbitType }},
+	{"launchDate", "LAUNCH DATE", func(s types.Satellite) string { return s.LaunchDate }},
+	{"altitude", "ALTITUDE (km)", func(s types.Satellite) string { return fmt.Sprintf("%.0f", s.Altitude) }},
+	{"constellation", "CONSTELLATION", func(s types.Satellite) string { return yesNo(s.Constellation) }},
+	{"inclination", "INCLINATION (deg)", func(s types.Satellite) string { return fmt.Sprintf("%.2f", s.Inclination) }},
+	{"eccentricity", "ECCENTRICITY", func(s types.Satellite) string { return fmt.Sprintf("%.4f", s.Eccentricity) }},
+	{"missionObjective", "MISSION OBJECTIVE", func(s types.Satellite) string { return s.MissionObjective }},
+	{"communication", "COMMUNICATION", func(s types.Satellite) string { return s.Communication }},
+	{"powerSystem", "POWER SYSTEM", func(s types.Satellite) string { return s.PowerSystem }},
+	{"remoteSensing", "REMOTE SENSING", func(s types.Satellite) string { return s.RemoteSensing }},
+	{"size", "SIZE (m)", func(s types.Satellite) string { return fmt.Sprintf("%.1f", s.Size) }},
+	{"weight", "WEIGHT (kg)", func(s types.Satellite) string { return fmt.Sprintf("%.0f", s.Weight) }},
+	{"apogee", "APOGEE (km)", func(s types.Satellite) string { return apsisCell(s, true, 1) }},
+	{"perigee", "PERIGEE (km)", func(s types.Satellite) string { return apsisCell(s, false, 1) }},
+	{"age", "AGE (y)", func(s types.Satellite) string { return ageCell(s) }},
+	{"longitude", "LONGITUDE (deg)", func(s types.Satellite) string { return longitudeCell(s) }},
+	{"analemma", "ANALEMMA (deg)", func(s types.Satellite) string { return analemmaCell(s) }},
+	{"tags", "TAGS", func(s types.Satellite) string { return strings.Join(s.Tags, ", ") }},
+}
+
+// longitudeCell formats the slot longitude of a geosynchronous s, or "-" for other orbits.
+func longitudeCell(s types.Satellite) string {
+	if !s.IsGeosynchronous() {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", s.Longitude)
+}
+
+// analemmaCell formats the latitude and longitude half-amplitudes of s's ground-track figure-eight,
+// or "-" if it has none (see Satellite.AnalemmaAmplitude).
+func analemmaCell(s types.Satellite) string {
+	lat, lon, ok := s.AnalemmaAmplitude()
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("±%.2f lat ±%.3f lon", lat, lon)
+}
+
+// geoColumnKeys are the columns added to the default table when most rows are geosynchronous.
+var geoColumnKeys = []string{"longitude", "analemma"}
+
+// withGeoColumns appends geoColumnKeys to columns when --columns was not given and more than half of
+// sats are GEO/GSO, where the slot longitude matters more than the altitude.
+func withGeoColumns(cmd *cobra.Command, columns []tableColumn, sats []types.Satellite) []tableColumn {
+	if cmd.Flags().Changed("columns") || len(sats) == 0 {
+		return columns
+	}
+	geo := 0
+	for _, sat := range sats {
+		if sat.IsGeosynchronous() {
+			geo++
+		}
+	}
+	if geo*2 <= len(sats) {
+		return columns
+	}
+	extra, _ := lookupTableColumns(geoColumnKeys)
+	return append(columns, extra...)
+}
+
+// ageCell formats the years in orbit of s, or "-" if its launch date cannot be parsed.
+func ageCell(s types.Satellite) string {
+	years, err := s.YearsInOrbit()
+	if err != nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", years)
+}
+
+// apsisColumnKeys are the computed columns appended by --show-apsis.
+var apsisColumnKeys = []string{"apogee", "perigee"}
+
+// apsisCell formats the apogee or perigee of s, or "-" if its eccentricity is out of range.
+// s.Altitude and the result are in units of perKm per kilometre (see altitudeUnit).
+func apsisCell(s types.Satellite, apogee bool, perKm float64) string {
+	s.Altitude /= perKm
+	apo, peri, err := s.ApogeePerigeeKm()
+	if err != nil {
+		return "-"
+	}
+	if apogee {
+		return fmt.Sprintf("%.0f", apo*perKm)
+	}
+	return fmt.Sprintf("%.0f", peri*perKm)
+}
+
+var (
+	defaultTableColumnKeys = []string{"name", "operator", "status", "orbitType", "launchDate", "altitude", "constellation"}
+	wideTableColumnKeys    = []string{"inclination", "eccentricity", "missionObjective", "communication"}
 )
 
-func PrintSatellitesTable(satellitesToPrint []types.Satellite) {
-	if len(satellitesToPrint) == 0 { return }
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "NAME\tOPERATOR\tSTATUS\tORBIT TYPE\tLAUNCH DATE\tALTITUDE (km)\tCONSTELLATION")
-	fmt.Fprintln(w, "----\t--------\t------\t----------\t-----------\t-------------\t-------------")
-	for _, sat := range satellitesToPrint {
-		constellationStatus := "No"; if sat.Constellation { constellationStatus = "Yes" }
-		altitudeStr := fmt.Sprintf("%.0f", sat.Altitude)
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			sat.Name, sat.Operator, sat.Status, sat.OrbitType, sat.LaunchDate, altitudeStr, constellationStatus)
+// lookupTableColumns resolves field names (case-insensitive) to table columns.
+func lookupTableColumns(keys []string) ([]tableColumn, error) {
+	var cols []tableColumn
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		found := false
+		for _, col := range allTableColumns {
+			if strings.EqualFold(col.Key, key) {
+				cols = append(cols, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			var valid []string
+			for _, col := range allTableColumns {
+				valid = append(valid, col.Key)
+			}
+			return nil, fmt.Errorf("unknown column '%s'. Valid columns: %s", key, strings.Join(valid, ", "))
+		}
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("no columns selected")
+	}
+	return cols, nil
+}
+
+// addTableColumnFlags registers the --wide and --columns flags on cmd.
+func addTableColumnFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wide", false, "Table/markdown output: append inclination, eccentricity, mission objective and communication columns")
+	cmd.Flags().String("columns", "", "Table/markdown output: comma-separated fields to show, e.g. name,operator,altitude")
+	cmd.Flags().Bool("show-apsis", false, "Table/markdown output: append apogee and perigee columns derived from altitude and eccentricity")
+}
+
+// minAutoTruncateWidth is the narrowest cell limit the terminal-based --truncate default sets.
+const minAutoTruncateWidth = 20
+
+// addTruncateFlag registers --truncate on commands with table output.
+func addTruncateFlag(cmd *cobra.Command) {
+	cmd.Flags().Int("truncate", 0, "Table output: shorten cells longer than N characters with an ellipsis (0 means never; default: a quarter of the terminal width)")
+}
+
+// truncateFromFlags returns the longest table cell --truncate allows, 0 meaning no limit. Without
+// the flag, cells are limited to a quarter of the terminal width (at least minAutoTruncateWidth)
+// when out is a terminal, and not at all otherwise, so piped output keeps full values.
+func truncateFromFlags(cmd *cobra.Command) (int, error) {
+	if cmd.Flags().Lookup("truncate") == nil {
+		return 0, nil
+	}
+	if cmd.Flags().Changed("truncate") {
+		width, _ := cmd.Flags().GetInt("truncate")
+		if width < 0 {
+			return 0, fmt.Errorf("--truncate cannot be negative")
+		}
+		return width, nil
+	}
+	f, ok := cmd.OutOrStdout().(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return 0, nil
+	}
+	columns, _, err := term.GetSize(int(f.Fd()))
+	if err != nil || columns <= 0 {
+		return 0, nil
+	}
+	return max(columns/4, minAutoTruncateWidth), nil
+}
+
+// truncateRows applies truncateCell to every cell of rows; a width of 0 leaves them unchanged.
+func truncateRows(rows [][]string, width int) [][]string {
+	if width <= 0 {
+		return rows
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = truncateCell(cell, width)
+		}
+	}
+	return rows
+}
+
+// truncateCell shortens s to width characters, the last being "…". ANSI escape sequences, such as
+// operator colors, are kept and not counted, so a colored cell is still reset at its end.
+func truncateCell(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width || lipgloss.Width(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	shown := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '[' {
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			j = min(j+1, len(s))
+			b.WriteString(s[i:j])
+			i = j
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case shown < width-1:
+			b.WriteRune(r)
+		case shown == width-1:
+			b.WriteString("…")
+		}
+		shown++
+	}
+	return b.String()
+}
+
+// tableColumnsFromFlags returns the columns selected by --columns/--wide, or the compact default.
+func tableColumnsFromFlags(cmd *cobra.Command) ([]tableColumn, error) {
+	columnsStr, _ := cmd.Flags().GetString("columns")
+	wide, _ := cmd.Flags().GetBool("wide")
+	showApsis, _ := cmd.Flags().GetBool("show-apsis")
+	if columnsStr != "" && wide {
+		return nil, fmt.Errorf("--columns and --wide cannot be combined")
+	}
+	unit, err := altitudeUnitFromFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+	dateLayout, err := dateFormatFromFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+	keys := defaultTableColumnKeys
+	if columnsStr != "" {
+		keys = strings.Split(columnsStr, ",")
+	} else if wide {
+		keys = append(append([]string{}, defaultTableColumnKeys...), wideTableColumnKeys...)
+	}
+	if showApsis {
+		keys = append(append([]string{}, keys...), apsisColumnKeys...)
+	}
+	columns, err := lookupTableColumns(keys)
+	if err != nil {
+		return nil, err
+	}
+	for i := range columns {
+		switch columns[i].Key {
+		case "altitude":
+			columns[i].Header = "ALTITUDE (" + unit.Name + ")"
+		case "apogee", "perigee":
+			apogee, perKm := columns[i].Key == "apogee", unit.PerKm // Rows arrive with Altitude in unit
+			columns[i].Header = strings.ToUpper(columns[i].Key) + " (" + unit.Name + ")"
+			columns[i].Value = func(s types.Satellite) string { return apsisCell(s, apogee, perKm) }
+		case "launchDate":
+			if dateLayout != "" {
+				now := time.Now()
+				columns[i].Value = func(s types.Satellite) string { return formatLaunchDate(s.LaunchDate, dateLayout, now) }
+			}
+		}
+	}
+	return columns, nil
+}
+
+// printSatellitesTable formats and prints a list of satellites as a table with the given columns.
+// Columns are aligned on display width rather than with text/tabwriter, so ANSI-colored
+// cells (see colorOperators) still line up.
+func printSatellitesTable(out io.Writer, satellitesToPrint []types.Satellite, columns []tableColumn, maxCellWidth int) {
+	if len(satellitesToPrint) == 0 {
+		return // Caller should ideally handle "no results found" message
+	}
+	printTableRows(out, columns, truncateRows(satelliteRows(satellitesToPrint, columns, colorOperators), maxCellWidth))
+}
+
+// printTableRows prints a header and separator row for columns followed by rows, aligned.
+func printTableRows(out io.Writer, columns []tableColumn, rows [][]string) {
+	headers := make([]string, len(columns))
+	rules := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.Header
+		rules[i] = strings.Repeat("-", len(col.Header))
+	}
+	printAlignedRows(out, append([][]string{headers, rules}, rows...))
+}
+
+// satelliteRows returns the cells of each satellite for columns, optionally coloring the operator.
+func satelliteRows(sats []types.Satellite, columns []tableColumn, color bool) [][]string {
+	rows := make([][]string, 0, len(sats))
+	for _, sat := range sats {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			cells[i] = col.Value(sat)
+			if color && col.Key == "operator" {
+				cells[i] = tui.OperatorStyle(sat.Operator).Render(cells[i])
+			}
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+// constellationRows returns one summary row per group for columns. Columns without a
+// meaningful roll-up (e.g. launch date) are left empty.
+func constellationRows(groups []types.ConstellationGroup, columns []tableColumn, color bool) [][]string {
+	rows := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			switch col.Key {
+			case "name":
+				cells[i] = fmt.Sprintf("%s constellation (%d)", g.Operator, g.Members)
+			case "operator":
+				cells[i] = g.Operator
+				if color {
+					cells[i] = tui.OperatorStyle(g.Operator).Render(cells[i])
+				}
+			case "orbitType":
+				cells[i] = strings.Join(g.OrbitTypes, "/")
+			case "altitude":
+				cells[i] = formatAltitudeRange(g.AltitudeMinKm, g.AltitudeMaxKm)
+			case "constellation":
+				cells[i] = yesNo(true)
+			}
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+// formatAltitudeRange renders a km range, collapsing it to one value when min equals max.
+func formatAltitudeRange(minKm, maxKm float64) string {
+	if minKm == maxKm {
+		return fmt.Sprintf("%.0f", minKm)
+	}
+	return fmt.Sprintf("%.0f-%.0f", minKm, maxKm)
+}
+
+// printSatellitesMarkdown prints satellites as a GitHub-flavored Markdown table with the given columns.
+// Cells are never colored.
+func printSatellitesMarkdown(out io.Writer, satellitesToPrint []types.Satellite, columns []tableColumn) {
+	if len(satellitesToPrint) == 0 {
+		return
+	}
+	printMarkdownTable(out, columns, satelliteRows(satellitesToPrint, columns, false))
+}
+
+// printMarkdownTable prints columns and rows as a Markdown table, escaping every cell.
+func printMarkdownTable(out io.Writer, columns []tableColumn, rows [][]string) {
+	headers := make([]string, len(columns))
+	rules := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.Header
+		rules[i] = "---"
+	}
+	printMarkdownRow(out, headers)
+	printMarkdownRow(out, rules)
+	for _, row := range rows {
+		printMarkdownRow(out, row)
+	}
+}
+
+func printMarkdownRow(out io.Writer, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = markdownCell(cell)
+	}
+	fmt.Fprintln(out, "| "+strings.Join(escaped, " | ")+" |")
+}
+
+// markdownCell escapes pipes and collapses whitespace (including newlines) so value stays within one table cell.
+func markdownCell(value string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(value, "|", `\|`)), " ")
+}
+
+// treeIndent is the lipgloss style for each level below the operator in tree output.
+var treeIndent = lipgloss.NewStyle().PaddingLeft(2)
+
+// printSatellitesTree prints sats as an indented operator -> orbit type -> name tree. Operators are
+// grouped case-insensitively and every level is sorted.
+func printSatellitesTree(out io.Writer, sats []types.Satellite) {
+	type operatorNode struct {
+		name   string
+		orbits map[string][]string
+	}
+	operators := make(map[string]*operatorNode)
+	for _, sat := range sats {
+		key := strings.ToLower(strings.TrimSpace(sat.Operator))
+		node, ok := operators[key]
+		if !ok {
+			node = &operatorNode{name: sat.Operator, orbits: make(map[string][]string)}
+			operators[key] = node
+		}
+		orbit := strings.ToUpper(strings.TrimSpace(sat.OrbitType))
+		node.orbits[orbit] = append(node.orbits[orbit], sat.Name)
+	}
+
+	for _, key := range sortedKeys(operators) {
+		node := operators[key]
+		name := node.name
+		if strings.TrimSpace(name) == "" {
+			name = "(no operator)"
+		} else if colorOperators {
+			name = tui.OperatorStyle(node.name).Render(name)
+		}
+		fmt.Fprintln(out, name)
+		for _, orbit := range sortedKeys(node.orbits) {
+			names := node.orbits[orbit]
+			sort.Strings(names)
+			label := orbit
+			if label == "" {
+				label = "(no orbit type)"
+			}
+			fmt.Fprintln(out, treeIndent.Render(fmt.Sprintf("%s (%d)", label, len(names))))
+			for _, satName := range names {
+				fmt.Fprintln(out, treeIndent.Render(treeIndent.Render(satName)))
+			}
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// printAlignedRows writes rows with two spaces of padding between columns; the last
+// column is left unpadded, matching the previous tabwriter layout.
+func printAlignedRows(out io.Writer, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if w := lipgloss.Width(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		b.Reset()
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+2))
+			}
+		}
+		fmt.Fprintln(out, b.String())
+	}
+}
+
+// operatorSummary is one operator's line in --output summary.
+type operatorSummary struct {
+	Operator   string         `json:"operator"`
+	Satellites int            `json:"satellites"`
+	Active     int            `json:"active"`
+	OrbitTypes map[string]int `json:"orbitTypes"`
+}
+
+// summarizeOperators rolls sats up per operator, grouped case-insensitively as in tree output and
+// sorted by operator.
+func summarizeOperators(sats []types.Satellite) []operatorSummary {
+	byKey := make(map[string]*operatorSummary)
+	for _, sat := range sats {
+		key := strings.ToLower(strings.TrimSpace(sat.Operator))
+		summary, ok := byKey[key]
+		if !ok {
+			summary = &operatorSummary{Operator: sat.Operator, OrbitTypes: make(map[string]int)}
+			byKey[key] = summary
+		}
+		summary.Satellites++
+		if strings.EqualFold(strings.TrimSpace(sat.Status), "active") {
+			summary.Active++
+		}
+		summary.OrbitTypes[strings.ToUpper(strings.TrimSpace(sat.OrbitType))]++
+	}
+	summaries := make([]operatorSummary, 0, len(byKey))
+	for _, key := range sortedKeys(byKey) {
+		summaries = append(summaries, *byKey[key])
+	}
+	return summaries
+}
+
+// printOperatorSummary prints one line per operator, e.g. "ESA: 12 sats (8 active), LEO×5 MEO×4 GEO×3",
+// with orbit types by descending count.
+func printOperatorSummary(out io.Writer, summaries []operatorSummary) {
+	for _, summary := range summaries {
+		name := summary.Operator
+		if strings.TrimSpace(name) == "" {
+			name = "(no operator)"
+		} else if colorOperators {
+			name = tui.OperatorStyle(summary.Operator).Render(name)
+		}
+		orbits := sortedKeys(summary.OrbitTypes)
+		sort.SliceStable(orbits, func(i, j int) bool { return summary.OrbitTypes[orbits[i]] > summary.OrbitTypes[orbits[j]] })
+		counts := make([]string, len(orbits))
+		for i, orbit := range orbits {
+			label := orbit
+			if label == "" {
+				label = "(none)"
+			}
+			counts[i] = fmt.Sprintf("%s×%d", label, summary.OrbitTypes[orbit])
+		}
+		noun := "sats"
+		if summary.Satellites == 1 {
+			noun = "sat"
+		}
+		fmt.Fprintf(out, "%s: %d %s (%d active), %s\n", name, summary.Satellites, noun, summary.Active, strings.Join(counts, " "))
 	}
-	w.Flush()
 }
-*/
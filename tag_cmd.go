@@ -0,0 +1,152 @@
+// cmd/satcli/tag_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Add, remove or list ad-hoc tags on satellites",
+	Long: `Tags are free-form labels such as "watchlist" or "legacy" for grouping satellites independently of
+operator and orbit. They are compared case-insensitively and kept in the order they were added.
+Select tagged satellites with 'satcli query --tag', and show them with --columns ...,tags.
+
+Examples:
+  satcli tag add ISS watchlist crewed
+  satcli tag remove ISS crewed
+  satcli tag list
+  satcli query --tag watchlist --output table`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add [name] [tag...]",
+	Short: "Add tags to a satellite",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeTags(cmd, args[0], args[1:], true)
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove [name] [tag...]",
+	Short: "Remove tags from a satellite",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeTags(cmd, args[0], args[1:], false)
+	},
+}
+
+var tagListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the tags in use with the number of satellites carrying each",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		counts := make(map[string]int) // Keyed by lower-case tag
+		spellings := make(map[string]string)
+		for _, name := range sortedKeys(satsMap) {
+			for _, tag := range satsMap[name].Tags {
+				key := strings.ToLower(tag)
+				if _, seen := spellings[key]; !seen {
+					spellings[key] = tag
+				}
+				counts[key]++
+			}
+		}
+
+		type tagCount struct {
+			Tag        string `json:"tag"`
+			Satellites int    `json:"satellites"`
+		}
+		list := make([]tagCount, 0, len(counts))
+		for _, key := range sortedKeys(counts) {
+			list = append(list, tagCount{Tag: spellings[key], Satellites: counts[key]})
+		}
+		if strings.ToLower(outputFormat) == "json" {
+			output, errJson := json.MarshalIndent(list, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal tags to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		}
+		if len(list) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No tags in use. Add one with 'satcli tag add [name] [tag]'.")
+			return nil
+		}
+		rows := make([][]string, len(list))
+		for i, entry := range list {
+			rows[i] = []string{entry.Tag, strconv.Itoa(entry.Satellites)}
+		}
+		printTableRows(cmd.OutOrStdout(), []tableColumn{{Header: "TAG"}, {Header: "SATELLITES"}}, rows)
+		return nil
+	},
+}
+
+// changeTags adds tags to, or removes them from, the named satellite and saves if it changed.
+func changeTags(cmd *cobra.Command, name string, tags []string, add bool) error {
+	if !datastore.IsUnlocked() {
+		return errDatastoreLocked()
+	}
+	cmd.SilenceUsage = true
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("tags cannot be empty")
+		}
+	}
+	satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get satellites: %w", err)
+	}
+	sat, ok, err := lookupSatellite(satsMap, name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &exitCodeError{exitNotFound, fmt.Errorf("satellite '%s' not found", name)}
+	}
+	name = sat.Name
+
+	var changed []string
+	verb := "Tagged"
+	if add {
+		sat, changed = sat.AddTags(tags...)
+	} else {
+		sat, changed = sat.RemoveTags(tags...)
+		verb = "Untagged"
+	}
+	if len(changed) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No changes: %s tags are [%s].\n", name, strings.Join(sat.Tags, ", "))
+		return nil
+	}
+	if err := datastore.AddSatellite(sat); err != nil {
+		return err
+	}
+	if err := datastore.SaveCtx(cmd.Context()); err != nil {
+		return fmt.Errorf("failed to save tags of '%s': %w", name, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %s: %s (tags now [%s])\n", verb, name, strings.Join(changed, ", "), strings.Join(sat.Tags, ", "))
+	return nil
+}
+
+func init() {
+	tagListCmd.Flags().StringP("output", "O", "table", "Output format: table or json")
+	tagCmd.AddCommand(tagAddCmd, tagRemoveCmd, tagListCmd)
+	rootCmd.AddCommand(tagCmd)
+}
@@ -0,0 +1,152 @@
+// types/tle.go
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EarthRadiusKm is the WGS 84 equatorial radius, used to turn a semi-major axis into an altitude.
+	EarthRadiusKm = 6378.137
+	// earthMuKm3s2 is Earth's standard gravitational parameter in km^3/s^2.
+	earthMuKm3s2 = 398600.4418
+	// tleLineLength is the length of each data line of a two-line element set.
+	tleLineLength = 69
+)
+
+// TLE is the subset of a NORAD two-line element set that maps onto Satellite.
+type TLE struct {
+	Name            string
+	CatalogNumber   string // NORAD catalog number
+	IntlDesignator  string // International designator, e.g. 98067A
+	InclinationDeg  float64
+	Eccentricity    float64
+	MeanMotionRevPD float64 // Revolutions per day
+}
+
+// ParseTLE parses the two data lines of an element set; name is the optional title line.
+// Line numbers, lengths, checksums and matching catalog numbers are all checked.
+func ParseTLE(name, line1, line2 string) (TLE, error) {
+	line1, line2 = strings.TrimRight(line1, " \r"), strings.TrimRight(line2, " \r")
+	for i, line := range []string{line1, line2} {
+		if len(line) != tleLineLength {
+			return TLE{}, fmt.Errorf("line %d has %d characters, want %d", i+1, len(line), tleLineLength)
+		}
+		if line[0] != byte('1'+i) || line[1] != ' ' {
+			return TLE{}, fmt.Errorf("line %d does not start with '%d '", i+1, i+1)
+		}
+		if want := tleChecksum(line[:68]); line[68] != byte('0'+want) {
+			return TLE{}, fmt.Errorf("line %d checksum is %c, want %d", i+1, line[68], want)
+		}
+	}
+	t := TLE{
+		Name:           strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "0 ")),
+		CatalogNumber:  strings.TrimSpace(line1[2:7]),
+		IntlDesignator: strings.TrimSpace(line1[9:17]),
+	}
+	if other := strings.TrimSpace(line2[2:7]); other != t.CatalogNumber {
+		return TLE{}, fmt.Errorf("catalog numbers differ between lines (%s, %s)", t.CatalogNumber, other)
+	}
+	if t.Name == "" {
+		t.Name = t.CatalogNumber
+	}
+
+	var err error
+	if t.InclinationDeg, err = strconv.ParseFloat(strings.TrimSpace(line2[8:16]), 64); err != nil {
+		return TLE{}, fmt.Errorf("invalid inclination '%s'", line2[8:16])
+	}
+	if t.Eccentricity, err = strconv.ParseFloat("0."+strings.TrimSpace(line2[26:33]), 64); err != nil {
+		return TLE{}, fmt.Errorf("invalid eccentricity '%s'", line2[26:33])
+	}
+	if t.MeanMotionRevPD, err = strconv.ParseFloat(strings.TrimSpace(line2[52:63]), 64); err != nil || t.MeanMotionRevPD <= 0 {
+		return TLE{}, fmt.Errorf("invalid mean motion '%s'", line2[52:63])
+	}
+	return t, nil
+}
+
+// tleChecksum is the modulo-10 sum of the digits in line, counting each '-' as 1.
+func tleChecksum(line string) int {
+	sum := 0
+	for _, c := range line {
+		switch {
+		case c >= '0' && c <= '9':
+			sum += int(c - '0')
+		case c == '-':
+			sum++
+		}
+	}
+	return sum % 10
+}
+
+// SemiMajorAxisKm derives the semi-major axis from the mean motion (Kepler's third law).
+func (t TLE) SemiMajorAxisKm() float64 {
+	n := t.MeanMotionRevPD * 2 * math.Pi / 86400 // rad/s
+	return math.Cbrt(earthMuKm3s2 / (n * n))
+}
+
+// AltitudeKm is the mean altitude: the semi-major axis less Earth's equatorial radius.
+func (t TLE) AltitudeKm() float64 {
+	return t.SemiMajorAxisKm() - EarthRadiusKm
+}
+
+// OrbitType classifies the orbit into one of Orbits by eccentricity, altitude and inclination.
+func (t TLE) OrbitType() string {
+	altitude := t.AltitudeKm()
+	switch {
+	case t.Eccentricity >= 0.25:
+		return "HEO"
+	case math.Abs(altitude-35786) <= 200:
+		if t.InclinationDeg < 1 {
+			return "GEO"
+		}
+		return "GSO"
+	case altitude < 2000:
+		if altitude >= 600 && altitude <= 800 && t.InclinationDeg >= 96 && t.InclinationDeg <= 99 {
+			return "SSO"
+		}
+		return "LEO"
+	case altitude < 35786:
+		return "MEO"
+	default:
+		return "HEO"
+	}
+}
+
+// Apply returns sat with its orbital fields (altitude, semi-major axis, inclination, eccentricity, orbit type) and
+// catalog identifiers taken from t. Other fields are kept.
+func (t TLE) Apply(sat Satellite) Satellite {
+	sat = sat.Clone()
+	if sat.Name == "" {
+		sat.Name = t.Name
+	}
+	sat.Altitude = math.Round(t.AltitudeKm())
+	sat.SemiMajorAxisKm = math.Round(t.SemiMajorAxisKm())
+	sat.Inclination = t.InclinationDeg
+	sat.Eccentricity = t.Eccentricity
+	sat.OrbitType = t.OrbitType()
+	if sat.Custom == nil {
+		sat.Custom = make(map[string]string)
+	}
+	sat.Custom["noradId"] = t.CatalogNumber
+	if t.IntlDesignator != "" {
+		sat.Custom["intlDesignator"] = t.IntlDesignator
+	}
+	return sat
+}
+
+// Refresh returns sat with only its orbital elements (altitude, semi-major axis, inclination,
+// eccentricity) taken from t and UpdatedAt set to now. Unlike Apply, the orbit type and custom
+// attributes are left as curated.
+func (t TLE) Refresh(sat Satellite, now time.Time) Satellite {
+	sat = sat.Clone()
+	sat.Altitude = math.Round(t.AltitudeKm())
+	sat.SemiMajorAxisKm = math.Round(t.SemiMajorAxisKm())
+	sat.Inclination = t.InclinationDeg
+	sat.Eccentricity = t.Eccentricity
+	sat.UpdatedAt = now.UTC().Format(time.RFC3339)
+	return sat
+}
@@ -1,6 +1,181 @@
 // cmd/satcli/tui_launcher.go
 package main
 
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yackko/satcom-code/tui"
+	"github.com/yackko/satcom-code/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
 // This file can contain helper functions to prepare data and launch
 // different TUI views if the TUI logic becomes more complex or shared.
-// For example, if queryCmd also gets a TUI view, the launching logic could be here.
+
+// renderSatellites prints sats in the format selected by cmd's --output flag (json, ndjson, kv, table, markdown, tree, or tui).
+// With --group-constellation, constellation members are rolled up per operator.
+// Altitudes are shown in --altitude-unit, except the km-named constellation fields of JSON output.
+// Fields named by --redact are replaced first, so every format hides them.
+func renderSatellites(cmd *cobra.Command, sats []types.Satellite) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	grouped := groupConstellationsFromFlags(cmd)
+	unit, errUnit := altitudeUnitFromFlags(cmd)
+	if errUnit != nil {
+		cmd.SilenceUsage = true
+		return errUnit
+	}
+	dateLayout, errDate := dateFormatFromFlags(cmd)
+	if errDate != nil {
+		cmd.SilenceUsage = true
+		return errDate
+	}
+	sats, errRedact := redactFromFlags(cmd, sats)
+	if errRedact != nil {
+		cmd.SilenceUsage = true
+		return errRedact
+	}
+	kmSats := sats
+	if unit.PerKm != 1 {
+		sats = convertAltitudes(sats, unit)
+	}
+	switch strings.ToLower(outputFormat) {
+	case "tui":
+		if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+			noticef("Notice: TUI requires an interactive terminal; falling back to --output table.\n")
+			return renderSatellitesTable(cmd, sats)
+		}
+		if threshold, _ := cmd.Flags().GetInt("tui-threshold"); threshold > 0 && len(sats) > threshold {
+			ok, err := confirm(fmt.Sprintf("Load %d satellites into the TUI?", len(sats)))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				noticef("Notice: TUI not started; narrow the selection, use --output table, or raise --tui-threshold.\n")
+				return nil
+			}
+		}
+		model := tui.NewListModel(sats) // From tui package; it sorts by launch date itself, so dates stay as stored
+		if grouped {
+			model = tui.NewGroupedListModel(sats)
+		}
+		model.ColorOperators = colorOperators
+		model.AltitudeUnit = unit.Name
+		p := tea.NewProgram(model, tea.WithAltScreen())
+		if _, errRun := p.Run(); errRun != nil {
+			return fmt.Errorf("error running TUI: %w", errRun)
+		}
+	case "table":
+		return renderSatellitesTable(cmd, sats)
+	case "ndjson":
+		if grouped {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("--group-constellation is not supported with --output ndjson")
+		}
+		if dateLayout != "" {
+			sats = formatLaunchDates(sats, dateLayout, time.Now())
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		for _, sat := range sats {
+			if err := enc.Encode(sat); err != nil {
+				return fmt.Errorf("failed to write satellite '%s' as NDJSON: %w", sat.Name, err)
+			}
+		}
+	case "kv":
+		if grouped {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("--group-constellation is not supported with --output kv")
+		}
+		if dateLayout != "" {
+			sats = formatLaunchDates(sats, dateLayout, time.Now())
+		}
+		printSatellitesKV(cmd.OutOrStdout(), sats)
+	case "tree":
+		printSatellitesTree(cmd.OutOrStdout(), sats)
+	case "summary":
+		printOperatorSummary(cmd.OutOrStdout(), summarizeOperators(sats))
+	case "summary-json":
+		output, errJson := json.MarshalIndent(summarizeOperators(sats), "", "  ")
+		if errJson != nil {
+			return fmt.Errorf("failed to marshal operator summary to JSON: %w", errJson)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
+	case "markdown":
+		columns, errCols := tableColumnsFromFlags(cmd)
+		if errCols != nil {
+			cmd.SilenceUsage = true
+			return errCols
+		}
+		if grouped {
+			individuals, groups := types.GroupConstellations(sats)
+			printMarkdownTable(cmd.OutOrStdout(), columns, append(satelliteRows(individuals, columns, false), constellationRows(groups, columns, false)...))
+			return nil
+		}
+		printSatellitesMarkdown(cmd.OutOrStdout(), sats, withGeoColumns(cmd, columns, sats))
+	default: // JSON; tables format --date-format in their launchDate column instead
+		if dateLayout != "" {
+			sats = formatLaunchDates(sats, dateLayout, time.Now())
+		}
+		var v interface{} = sats
+		if grouped {
+			individuals, _ := types.GroupConstellations(sats)
+			_, groups := types.GroupConstellations(kmSats) // ConstellationGroup's JSON fields are named in km
+			if individuals == nil {
+				individuals = []types.Satellite{}
+			}
+			v = struct {
+				Satellites     []types.Satellite          `json:"satellites"`
+				Constellations []types.ConstellationGroup `json:"constellations"`
+			}{individuals, groups}
+		}
+		output, errJson := json.MarshalIndent(v, "", "  ")
+		if errJson != nil {
+			return fmt.Errorf("failed to marshal satellites to JSON: %w", errJson)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
+	}
+	return nil
+}
+
+// renderSatellitesTable prints sats as a table using the columns selected by --wide/--columns,
+// with cells shortened to --truncate.
+func renderSatellitesTable(cmd *cobra.Command, sats []types.Satellite) error {
+	columns, errCols := tableColumnsFromFlags(cmd)
+	if errCols != nil {
+		cmd.SilenceUsage = true
+		return errCols
+	}
+	maxCellWidth, errTruncate := truncateFromFlags(cmd)
+	if errTruncate != nil {
+		cmd.SilenceUsage = true
+		return errTruncate
+	}
+	if groupConstellationsFromFlags(cmd) {
+		individuals, groups := types.GroupConstellations(sats)
+		rows := append(satelliteRows(individuals, columns, colorOperators), constellationRows(groups, columns, colorOperators)...)
+		printTableRows(cmd.OutOrStdout(), columns, truncateRows(rows, maxCellWidth))
+		return nil
+	}
+	printSatellitesTable(cmd.OutOrStdout(), sats, withGeoColumns(cmd, columns, sats), maxCellWidth)
+	return nil
+}
+
+// addGroupConstellationFlag registers --group-constellation on cmd.
+func addGroupConstellationFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("group-constellation", false, "Collapse constellation members into one summary row per operator (expandable in the TUI)")
+}
+
+// groupConstellationsFromFlags reports whether --group-constellation is set; commands without the flag never group.
+func groupConstellationsFromFlags(cmd *cobra.Command) bool {
+	if cmd.Flags().Lookup("group-constellation") == nil {
+		return false
+	}
+	grouped, _ := cmd.Flags().GetBool("group-constellation")
+	return grouped
+}
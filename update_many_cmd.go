@@ -0,0 +1,316 @@
+// cmd/satcli/update_many_cmd.go
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+	"github.com/yackko/satcom-code/types"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// updateManyFilterFlags are the selection flags of update-many; at least one is required.
+var updateManyFilterFlags = []string{"operator", "status", "orbit-type", "constellation", "constellation-only", "exclude-constellation"}
+
+// fieldSetter assigns one JSON field (or one custom attribute, as custom.<key>) of a satellite.
+type fieldSetter struct {
+	Field string
+	Value string
+}
+
+var updateManyCmd = &cobra.Command{
+	Use:   "update-many",
+	Short: "Set fields on every satellite matching the given filters",
+	Long: `Selects satellites by --operator, --status, --orbit-type and/or --constellation (at least one is
+required) and applies each --set field=value to all of them, saving once. Fields are named as in the
+JSON output (e.g. status, orbitType, altitude); custom.<key>=value sets a custom attribute and
+custom.<key>= removes it. The name cannot be changed here (see 'satcli rename'), nor the tags
+(see 'satcli tag').
+
+The changes are always previewed first. --dry-run stops after the preview; otherwise you are asked
+to confirm, or --yes applies them without asking. --warn-inconsistent warns about updated records
+whose altitude no longer fits their orbit type (see 'satcli validate').
+
+Examples:
+  satcli update-many --status active --set status=inactive --dry-run
+  satcli update-many --operator ESA --orbit-type SSO --set custom.program=copernicus --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		setPairs, _ := cmd.Flags().GetStringArray("set")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		cmd.SilenceUsage = true
+
+		filtered := false
+		for _, name := range updateManyFilterFlags {
+			filtered = filtered || cmd.Flags().Changed(name)
+		}
+		if !filtered {
+			return fmt.Errorf("at least one filter is required (--%s)", strings.Join(updateManyFilterFlags, ", --"))
+		}
+		if len(setPairs) == 0 {
+			return fmt.Errorf("at least one --set field=value is required")
+		}
+		setters, err := parseFieldSetters(setPairs)
+		if err != nil {
+			return err
+		}
+		match, err := basicFilterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		var names []string
+		for name, sat := range satsMap {
+			if match(sat) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		var updated []types.Satellite
+		for _, name := range names {
+			sat, changes, err := applyFieldSetters(satsMap[name], setters)
+			if err != nil {
+				return fmt.Errorf("'%s': %w", name, err)
+			}
+			if len(changes) == 0 {
+				continue
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", name, strings.Join(changes, ", "))
+			warnIfOrbitInconsistent(cmd, sat)
+			updated = append(updated, sat)
+		}
+		if len(updated) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "No changes: %d satellite(s) matched and already have these values.\n", len(names))
+			return nil
+		}
+		if dryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "Dry run: %d of %d matching satellite(s) would be updated.\n", len(updated), len(names))
+			return nil
+		}
+		if !yes {
+			confirmed, err := confirm(fmt.Sprintf("Update %d satellite(s)?", len(updated)))
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Fprintln(cmd.OutOrStdout(), "Aborted; nothing was changed.")
+				return nil
+			}
+		}
+
+		for _, sat := range updated {
+			if err := datastore.AddSatellite(sat); err != nil {
+				return err
+			}
+		}
+		if err := datastore.SaveCtx(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to save %d updated record(s): %w", len(updated), err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Updated %d satellite(s).\n", len(updated))
+		return nil
+	},
+}
+
+// basicFilterFromFlags returns a matcher for the update-many filter flags (case-insensitive equality).
+func basicFilterFromFlags(cmd *cobra.Command) (func(types.Satellite) bool, error) {
+	operator, _ := cmd.Flags().GetString("operator")
+	status, _ := cmd.Flags().GetString("status")
+	orbitType, _ := cmd.Flags().GetString("orbit-type")
+	constellation, err := constellationFilterFromFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return func(sat types.Satellite) bool {
+		return (operator == "" || strings.EqualFold(sat.Operator, operator)) &&
+			(status == "" || strings.EqualFold(sat.Status, status)) &&
+			(orbitType == "" || strings.EqualFold(sat.OrbitType, orbitType)) &&
+			(constellation == nil || sat.Constellation == *constellation)
+	}, nil
+}
+
+// parseFieldSetters parses --set values, resolving field names (case-insensitive) to JSON names.
+func parseFieldSetters(pairs []string) ([]fieldSetter, error) {
+	fields := satelliteJSONFields()
+	var setters []fieldSetter
+	for _, pair := range pairs {
+		field, value, ok := strings.Cut(pair, "=")
+		field = strings.TrimSpace(field)
+		if !ok || field == "" {
+			return nil, fmt.Errorf("invalid value for --set: '%s'. Use field=value", pair)
+		}
+		if key, isCustom := strings.CutPrefix(field, "custom."); isCustom {
+			if key == "" {
+				return nil, fmt.Errorf("invalid value for --set: '%s'. Use custom.<key>=value", pair)
+			}
+			setters = append(setters, fieldSetter{Field: "custom." + key, Value: value})
+			continue
+		}
+		jsonName, known := "", false
+		for name := range fields {
+			if strings.EqualFold(name, field) {
+				jsonName, known = name, true
+			}
+		}
+		if !known || jsonName == "name" || jsonName == "custom" || jsonName == "tags" {
+			var valid []string
+			for name := range fields {
+				if name != "name" && name != "custom" && name != "tags" {
+					valid = append(valid, name)
+				}
+			}
+			sort.Strings(valid)
+			return nil, fmt.Errorf("cannot set field '%s'. Settable fields: %s, custom.<key>", field, strings.Join(valid, ", "))
+		}
+		setters = append(setters, fieldSetter{Field: jsonName, Value: strings.TrimSpace(value)})
+	}
+	return setters, nil
+}
+
+// satelliteJSONFields maps each Satellite JSON field name to its Go kind.
+func satelliteJSONFields() map[string]reflect.Kind {
+	fields := make(map[string]reflect.Kind)
+	t := reflect.TypeOf(types.Satellite{})
+	for i := 0; i < t.NumField(); i++ {
+		if name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			fields[name] = t.Field(i).Type.Kind()
+		}
+	}
+	return fields
+}
+
+// applyFieldSetters returns sat with setters applied and a description of each changed field.
+// Set fields are checked against SatelliteSchema and the checks of validateSatellite.
+func applyFieldSetters(sat types.Satellite, setters []fieldSetter) (types.Satellite, []string, error) {
+	raw, err := json.Marshal(sat)
+	if err != nil {
+		return sat, nil, err
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return sat, nil, err
+	}
+
+	fields := satelliteJSONFields()
+	var changes []string
+	for _, s := range setters {
+		if key, isCustom := strings.CutPrefix(s.Field, "custom."); isCustom {
+			custom, _ := record["custom"].(map[string]interface{})
+			if custom == nil {
+				custom = make(map[string]interface{})
+			}
+			old, had := custom[key]
+			switch {
+			case s.Value == "" && had:
+				delete(custom, key)
+				changes = append(changes, fmt.Sprintf("%s %v -> (removed)", s.Field, old))
+			case s.Value != "" && old != s.Value:
+				custom[key] = s.Value
+				changes = append(changes, fmt.Sprintf("%s %v -> %s", s.Field, describeSetValue(old, had), s.Value))
+			}
+			record["custom"] = custom
+			continue
+		}
+
+		var value interface{} = s.Value
+		switch fields[s.Field] {
+		case reflect.Float64:
+			n, err := strconv.ParseFloat(s.Value, 64)
+			if err != nil {
+				return sat, nil, fmt.Errorf("invalid number for %s: '%s'", s.Field, s.Value)
+			}
+			value = n
+		case reflect.Bool:
+			b, err := strconv.ParseBool(s.Value)
+			if err != nil {
+				return sat, nil, fmt.Errorf("invalid value for %s: '%s'. Use 'true' or 'false'", s.Field, s.Value)
+			}
+			value = b
+		}
+		if record[s.Field] != value {
+			changes = append(changes, fmt.Sprintf("%s %v -> %v", s.Field, describeSetValue(record[s.Field], true), value))
+			record[s.Field] = value
+		}
+	}
+
+	schema := types.SatelliteSchema()
+	for _, v := range schema.Validate(record) {
+		for _, s := range setters {
+			if v.Field == strings.Split(s.Field, ".")[0] {
+				return sat, nil, v
+			}
+		}
+	}
+	raw, err = json.Marshal(record)
+	if err != nil {
+		return sat, nil, err
+	}
+	var updated types.Satellite
+	if err := json.Unmarshal(raw, &updated); err != nil {
+		return sat, nil, err
+	}
+	// Only the fields being set are checked, so a problem the record already had (e.g. a legacy
+	// launch date) neither blocks unrelated changes nor hides a problem with a set field.
+	for _, problem := range satelliteFieldErrors(updated) {
+		for _, s := range setters {
+			if problem.Field == s.Field {
+				return sat, nil, problem.Err
+			}
+		}
+	}
+	return updated, changes, nil
+}
+
+func describeSetValue(v interface{}, present bool) string {
+	if !present {
+		return "(unset)"
+	}
+	if s, ok := v.(string); ok && s == "" {
+		return `""`
+	}
+	return fmt.Sprint(v)
+}
+
+// confirm asks a yes/no question on the terminal; without one, --yes is required instead.
+func confirm(question string) (bool, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("not running in a terminal to confirm; re-run with --yes to apply or --dry-run to preview")
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+func init() {
+	updateManyCmd.Flags().StringP("operator", "o", "", "Select satellites by operator (case-insensitive)")
+	updateManyCmd.Flags().StringP("status", "s", "", "Select satellites by status (case-insensitive)")
+	updateManyCmd.Flags().StringP("orbit-type", "t", "", "Select satellites by orbit type (case-insensitive)")
+	_ = updateManyCmd.RegisterFlagCompletionFunc("orbit-type", completeOrbitTypes)
+	updateManyCmd.Flags().String("constellation", "", "Select satellites by constellation status ('true' or 'false')")
+	addConstellationShorthandFlags(updateManyCmd)
+	updateManyCmd.Flags().StringArray("set", nil, "Set a field as field=value, or a custom attribute as custom.<key>=value (repeatable)")
+	updateManyCmd.Flags().Bool("yes", false, "Apply the changes without asking for confirmation")
+	updateManyCmd.Flags().Bool("warn-inconsistent", false, "Warn about updated records whose altitude does not fit their orbit type")
+	rootCmd.AddCommand(updateManyCmd)
+}
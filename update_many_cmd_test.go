@@ -0,0 +1,46 @@
+// cmd/satcli/update_many_cmd_test.go
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yackko/satcom-code/types"
+)
+
+func TestApplyFieldSettersChecksOnlySetFields(t *testing.T) {
+	valid := types.Satellite{Name: "A", OrbitType: "LEO", Operator: "ESA", Status: "active", Altitude: 550, LaunchDate: "2020-01-01"}
+	legacy := valid
+	legacy.LaunchDate = "2020/01/01" // Unparsable date stored before validation existed
+
+	tests := []struct {
+		name    string
+		sat     types.Satellite
+		setters []fieldSetter
+		wantErr bool
+	}{
+		{"valid change", valid, []fieldSetter{{"status", "inactive"}}, false},
+		{"status in another case", valid, []fieldSetter{{"status", "Inactive"}}, false},
+		{"legacy date does not block other fields", legacy, []fieldSetter{{"status", "inactive"}}, false},
+		{"invalid set field on a legacy record", legacy, []fieldSetter{{"semiMajorAxis", "100"}}, true},
+		{"invalid set field", valid, []fieldSetter{{"semiMajorAxis", "100"}}, true},
+		{"valid set field on a legacy record", legacy, []fieldSetter{{"semiMajorAxis", "6928"}}, false},
+		{"setting the legacy date again", legacy, []fieldSetter{{"launchDate", "2020/01/01"}}, true},
+		{"fixing the legacy date", legacy, []fieldSetter{{"launchDate", "2020-01-01"}}, false},
+		{"negative altitude", valid, []fieldSetter{{"altitude", "-1"}}, true},
+		{"longitude out of range", legacy, []fieldSetter{{"longitude", "181"}}, true},
+		{"status outside the schema", valid, []fieldSetter{{"status", "retired"}}, true},
+		{"custom attribute", legacy, []fieldSetter{{"custom.owner", "ops"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, _, err := applyFieldSetters(tt.sat, tt.setters)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err != nil && !reflect.DeepEqual(updated, tt.sat) {
+				t.Error("a rejected update changed the record")
+			}
+		})
+	}
+}
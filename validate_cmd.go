@@ -0,0 +1,87 @@
+// cmd/satcli/validate_cmd.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+// validationIssue is one problem validate found in a stored record.
+type validationIssue struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"` // "error" or "warning"
+	Message  string `json:"message"`
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the stored satellite records for invalid or inconsistent data",
+	Long: `Checks every stored record against the rules 'satcli add' and 'satcli import' apply (errors), and
+for data that is valid but probably wrong (warnings): an altitude outside the range 'satcli explain'
+gives for the orbit type, with extra tolerance for GSO, HEO and SSO. A GEO satellite at 500 km is
+reported, for example. Records without an altitude are not checked against their orbit type.
+
+Exits non-zero if any record has an error, or, with --strict, a warning. Nothing is changed.
+
+Examples:
+  satcli validate
+  satcli validate --strict --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !datastore.IsUnlocked() {
+			return errDatastoreLocked()
+		}
+		strict, _ := cmd.Flags().GetBool("strict")
+		outputFormat, _ := cmd.Flags().GetString("output")
+		cmd.SilenceUsage = true
+
+		satsMap, err := datastore.GetSatellitesCtx(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get satellites: %w", err)
+		}
+		issues := []validationIssue{}
+		errorCount, warningCount := 0, 0
+		for _, name := range sortedKeys(satsMap) {
+			sat := satsMap[name]
+			if err := validateSatellite(sat); err != nil {
+				issues = append(issues, validationIssue{Name: name, Severity: "error", Message: err.Error()})
+				errorCount++
+			}
+			if err := sat.ValidateOrbitConsistency(); err != nil {
+				issues = append(issues, validationIssue{Name: name, Severity: "warning", Message: err.Error()})
+				warningCount++
+			}
+		}
+
+		if strings.ToLower(outputFormat) == "json" {
+			output, errJson := json.MarshalIndent(issues, "", "  ")
+			if errJson != nil {
+				return fmt.Errorf("failed to marshal validation results to JSON: %w", errJson)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		} else {
+			rows := make([][]string, len(issues))
+			for i, issue := range issues {
+				rows[i] = []string{strings.ToUpper(issue.Severity), issue.Name, issue.Message}
+			}
+			printAlignedRows(cmd.OutOrStdout(), rows)
+			fmt.Fprintf(cmd.OutOrStdout(), "Checked %d record(s): %d error(s), %d warning(s).\n", len(satsMap), errorCount, warningCount)
+		}
+
+		if errorCount > 0 || (strict && warningCount > 0) {
+			return fmt.Errorf("validation failed: %d error(s), %d warning(s)", errorCount, warningCount)
+		}
+		return nil
+	},
+}
+
+func init() {
+	validateCmd.Flags().Bool("strict", false, "Also fail on warnings")
+	validateCmd.Flags().StringP("output", "O", "table", "Output format: table or json")
+	rootCmd.AddCommand(validateCmd)
+}
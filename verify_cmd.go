@@ -0,0 +1,52 @@
+// cmd/satcli/verify_cmd.go
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yackko/satcom-code/internal/config"
+	"github.com/yackko/satcom-code/internal/crypto"
+	"github.com/yackko/satcom-code/internal/datastore"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that the passphrase decrypts the datastore, for use in scripts",
+	Long: `Decrypts the datastore with the configured passphrase source (` + config.PassphraseEnvVar + ` or the prompt)
+and reports only whether it succeeded. No satellite data is printed and the session is not unlocked.
+
+Exit codes (the same as every other command; see 'satcli --help'):
+  0  the datastore decrypts
+  3  wrong passphrase (or ciphertext that fails authentication)
+  4  datastore file not found
+  5  datastore file is corrupted
+  1  any other error
+
+Examples:
+  satcli verify && satcli import --file batch.json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{skipDatastoreAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := datastore.Verify(cmd.Context())
+		switch {
+		case err == nil:
+			fmt.Fprintln(cmd.OutOrStdout(), "OK: datastore decrypts with the provided passphrase.")
+			return nil
+		case errors.Is(err, crypto.ErrWrongPassphrase):
+			return &exitCodeError{exitLocked, err}
+		case errors.Is(err, datastore.ErrNotFound):
+			return &exitCodeError{exitNotFound, err}
+		case errors.Is(err, datastore.ErrCorrupted):
+			return &exitCodeError{exitCorrupted, err}
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
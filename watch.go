@@ -0,0 +1,54 @@
+// cmd/satcli/watch.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/yackko/satcom-code/internal/datastore"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// watchQuery re-reads the datastore and calls render every interval until ctx is
+// cancelled or the process receives SIGINT/SIGTERM. Errors from a single refresh are
+// shown on screen and do not stop the watch.
+func watchQuery(ctx context.Context, out, errOut io.Writer, interval time.Duration, render func() error) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	refresh := func(reload bool) {
+		fmt.Fprint(out, clearScreen)
+		fmt.Fprintf(out, "Every %s: satcli %s    %s\n\n", interval, strings.Join(os.Args[1:], " "), time.Now().Format("2006-01-02 15:04:05"))
+		if reload {
+			if err := datastore.Reload(); err != nil {
+				fmt.Fprintf(errOut, "Error: failed to reload datastore: %v\n", err)
+				return
+			}
+		}
+		if err := render(); err != nil {
+			fmt.Fprintf(errOut, "Error: %v\n", err)
+		}
+	}
+
+	refresh(false) // Data was just loaded by Init
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(out)
+			return nil
+		case <-ticker.C:
+			refresh(true)
+		}
+	}
+}
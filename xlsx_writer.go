@@ -0,0 +1,125 @@
+// cmd/satcli/xlsx_writer.go
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// numericColumnKeys are the table columns written as number cells in spreadsheets and sorted
+// numerically in HTML reports.
+var numericColumnKeys = map[string]bool{
+	"altitude": true, "inclination": true, "eccentricity": true, "size": true, "weight": true,
+	"apogee": true, "perigee": true, "age": true,
+}
+
+// maxXLSXColumnWidth caps auto-sized column widths (in characters) so long free text stays readable.
+const maxXLSXColumnWidth = 60
+
+// The fixed parts of a single-sheet workbook. Style 1 (bold on light gray) is used for the header row.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Satellites" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill><fill><patternFill patternType="solid"><fgColor rgb="FFD9D9D9"/><bgColor indexed="64"/></patternFill></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="2" borderId="0" xfId="0" applyFont="1" applyFill="1"/></cellXfs></styleSheet>`
+)
+
+// writeXLSX writes columns and rows as a one-sheet workbook: a styled, frozen header row and
+// columns sized to their longest cell. Cells of numericColumnKeys columns that parse as numbers
+// are stored as numbers; everything else is stored as inline text.
+func writeXLSX(w io.Writer, columns []tableColumn, rows [][]string) error {
+	zw := zip.NewWriter(w)
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{"xl/worksheets/sheet1.xml", xlsxSheet(columns, rows)},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// xlsxSheet renders the worksheet XML for columns and rows.
+func xlsxSheet(columns []tableColumn, rows [][]string) string {
+	widths := make([]int, len(columns))
+	for i, col := range columns {
+		widths[i] = utf8.RuneCountInString(col.Header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<cols>`)
+	for i, width := range widths {
+		fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, min(width+2, maxXLSXColumnWidth))
+	}
+	b.WriteString(`</cols><sheetData>`)
+
+	b.WriteString(`<row r="1">`)
+	for i, col := range columns {
+		writeXLSXTextCell(&b, xlsxCellRef(i, 1), col.Header, 1)
+	}
+	b.WriteString(`</row>`)
+	for r, row := range rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+2)
+		for i, cell := range row {
+			ref := xlsxCellRef(i, r+2)
+			if _, err := strconv.ParseFloat(cell, 64); err == nil && numericColumnKeys[columns[i].Key] {
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, cell)
+				continue
+			}
+			writeXLSXTextCell(&b, ref, cell, 0)
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+func writeXLSXTextCell(b *bytes.Buffer, ref, text string, style int) {
+	fmt.Fprintf(b, `<c r="%s" t="inlineStr"`, ref)
+	if style != 0 {
+		fmt.Fprintf(b, ` s="%d"`, style)
+	}
+	b.WriteString(`><is><t xml:space="preserve">`)
+	xml.EscapeText(b, []byte(text))
+	b.WriteString(`</t></is></c>`)
+}
+
+// xlsxCellRef returns the A1-style reference of the zero-based column col in row (1-based).
+func xlsxCellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}